@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// issueAPIKey issues an api key of an existing user, the key is printed once and never stored.
+func issueAPIKey(a *app, args []string) (err error) {
+	flags := flag.NewFlagSet("apikey issue", flag.ContinueOnError)
+	userUUID := flags.String("user", "", "uuid of the user")
+	name := flags.String("name", "", "label of the key")
+	ttl := flags.Duration("ttl", 0, "lifetime of the key, e.g. 720h, it never expires by default")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *userUUID == "" || *name == "" {
+		return fmt.Errorf("usage: apikey issue -user <uuid> -name <name> [-ttl duration]")
+	}
+
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	if err = responseError(a.userUsecase().GetOneUser(ctx, *userUUID)); err != nil {
+		return
+	}
+
+	key, apiKey, err := a.apiKeyService().Issue(ctx, *userUUID, *name, *ttl)
+	if err != nil {
+		return
+	}
+
+	expiresAt := "never"
+	if apiKey.ExpiresAt != nil {
+		expiresAt = apiKey.ExpiresAt.Format(time.RFC3339)
+	}
+	fmt.Fprintf(os.Stdout, "api key %d of %s, expires %s\n%s\n", apiKey.ID, apiKey.UserUUID, expiresAt, key)
+	fmt.Fprintln(os.Stderr, "store the key now, it can't be shown again")
+	return
+}
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"todo-app-api/cmd/task/core"
+	taskV2 "todo-app-api/cmd/task/v2"
+	"todo-app-api/cmd/user/v1"
+	"todo-app-api/configs"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/apikey"
+	"todo-app-api/pkg/attachment"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/memstore"
+	"todo-app-api/pkg/middleware"
+
+	"github.com/go-playground/validator/v10"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// app holds the dependencies that are shared by the commands, they are opened on first use.
+type app struct {
+	cfg         *configs.Config
+	logger      *logrus.Logger
+	dbReadOnly  *sql.DB
+	dbReadWrite *sql.DB
+	dbRouter    *database.Router
+	validator   *validator.Validate
+	memory      *memoryTables
+	mongoClient *mongo.Client
+	// invertedIndex is the search index of the memory driver.
+	invertedIndex *core.InvertedIndex
+}
+
+// memoryTables are the tables of the memory driver, they live as long as the process.
+type memoryTables struct {
+	tasks        *memstore.Table[int64, entity.Task]
+	labels       *memstore.Table[int64, entity.Label]
+	checklists   *memstore.Table[int64, entity.ChecklistItem]
+	dependencies *memstore.Table[core.DependencyKey, entity.Dependency]
+	projects     *memstore.Table[int64, entity.Project]
+	comments     *memstore.Table[int64, entity.Comment]
+	activities   *memstore.Table[int64, entity.TaskActivity]
+	views        *memstore.Table[int64, entity.View]
+	users        *memstore.Table[string, entity.User]
+	apiKeys      *memstore.Table[string, entity.APIKey]
+	attachments  *memstore.Table[string, entity.AttachmentObject]
+}
+
+func (a *app) inMemory() bool {
+	return a.cfg.Repository.Driver == "memory"
+}
+
+// inMongo tells whether the tasks, their labels, projects, comments, activities and views are stored in mongo, the other repositories stay on mariadb.
+func (a *app) inMongo() bool {
+	return a.cfg.Repository.Driver == "mongo"
+}
+
+func (a *app) inPostgres() bool {
+	return a.cfg.Repository.Driver == "postgres"
+}
+
+// dialect is the sql dialect of the read only and the read write databases.
+func (a *app) dialect() database.Dialect {
+	if a.inPostgres() {
+		return database.Postgres
+	}
+	return database.MySQL
+}
+
+// openDatabases opens and pings the read only and the read write databases,
+// the memory driver gets its tables instead.
+func (a *app) openDatabases() (err error) {
+	if a.dbReadWrite != nil || a.memory != nil {
+		return
+	}
+
+	if a.inMemory() {
+		a.memory = &memoryTables{
+			tasks:        memstore.NewTable[int64, entity.Task](),
+			labels:       memstore.NewTable[int64, entity.Label](),
+			checklists:   memstore.NewTable[int64, entity.ChecklistItem](),
+			dependencies: memstore.NewTable[core.DependencyKey, entity.Dependency](),
+			projects:     memstore.NewTable[int64, entity.Project](),
+			comments:     memstore.NewTable[int64, entity.Comment](),
+			activities:   memstore.NewTable[int64, entity.TaskActivity](),
+			views:        memstore.NewTable[int64, entity.View](),
+			users:        memstore.NewTable[string, entity.User](),
+			apiKeys:      memstore.NewTable[string, entity.APIKey](),
+			attachments:  memstore.NewTable[string, entity.AttachmentObject](),
+		}
+		a.invertedIndex = core.NewInvertedIndex()
+		return
+	}
+
+	ro, rw := a.cfg.MariadbReadOnly, a.cfg.MariadbReadWrite
+	readOnly := sqlPool{ro.Driver, ro.DSN, ro.MaxOpenConnections, ro.MaxIdleConnections}
+	readWrite := sqlPool{rw.Driver, rw.DSN, rw.MaxOpenConnections, rw.MaxIdleConnections}
+	if a.inPostgres() {
+		ro, rw := a.cfg.PostgresReadOnly, a.cfg.PostgresReadWrite
+		readOnly = sqlPool{ro.Driver, ro.DSN, ro.MaxOpenConnections, ro.MaxIdleConnections}
+		readWrite = sqlPool{rw.Driver, rw.DSN, rw.MaxOpenConnections, rw.MaxIdleConnections}
+	}
+
+	// set read only object, the router sends the reads to the primary while the replica is down.
+	if a.dbReadOnly, err = readOnly.open(); err != nil {
+		return
+	}
+	if err := a.dbReadOnly.Ping(); err != nil {
+		a.logger.Warn("read only database is not reachable: ", err)
+	}
+
+	// set read write object
+	if a.dbReadWrite, err = readWrite.open(); err != nil {
+		return
+	}
+	if err = a.dbReadWrite.Ping(); err != nil {
+		return
+	}
+
+	// set the router of the reads and the writes, its health checks are started by serve.
+	a.dbRouter = database.NewRouter(a.logger, a.dbReadWrite, a.dbReadOnly, a.dialect(), database.RouterOptions{
+		HealthCheckInterval:  a.cfg.DatabaseRouter.HealthCheckInterval,
+		HealthCheckTimeout:   a.cfg.DatabaseRouter.HealthCheckTimeout,
+		MaxReplicaLag:        a.cfg.DatabaseRouter.MaxReplicaLag,
+		ReadYourWritesWindow: a.cfg.DatabaseRouter.ReadYourWritesWindow,
+		ClientKey:            middleware.ClientKey,
+	})
+
+	if a.inMongo() {
+		return a.openMongo()
+	}
+	return
+}
+
+// sqlPool is the connection settings of a database/sql pool.
+type sqlPool struct {
+	driver             string
+	dsn                string
+	maxOpenConnections int
+	maxIdleConnections int
+}
+
+// open opens the pool, it connects on first use.
+func (p sqlPool) open() (db *sql.DB, err error) {
+	if db, err = sql.Open(p.driver, p.dsn); err != nil {
+		return
+	}
+	db.SetConnMaxLifetime(time.Minute * 3)
+	db.SetMaxOpenConns(p.maxOpenConnections)
+	db.SetMaxIdleConns(p.maxIdleConnections)
+	return
+}
+
+// openMongo connects to mongo and creates the indexes of the task collections.
+func (a *app) openMongo() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	if a.mongoClient, err = mongo.Connect(ctx, a.cfg.Mongodb.ClientOptions); err != nil {
+		return
+	}
+	if err = a.mongoClient.Ping(ctx, nil); err != nil {
+		return
+	}
+
+	if err = core.CreateMongoTaskIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task"); err != nil {
+		return
+	}
+	if err = core.CreateMongoLabelIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "label"); err != nil {
+		return
+	}
+	if err = core.CreateMongoChecklistIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_checklist_item"); err != nil {
+		return
+	}
+	if err = core.CreateMongoDependencyIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_dependency"); err != nil {
+		return
+	}
+	if err = core.CreateMongoProjectIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "project"); err != nil {
+		return
+	}
+	if err = core.CreateMongoCommentIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_comment"); err != nil {
+		return
+	}
+	if err = core.CreateMongoActivityIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_activity"); err != nil {
+		return
+	}
+	return core.CreateMongoViewIndexes(ctx, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_view")
+}
+
+func (a *app) close() {
+	if a.dbRouter != nil {
+		a.dbRouter.Close()
+	}
+	if a.mongoClient != nil {
+		a.mongoClient.Disconnect(context.Background())
+	}
+	if a.dbReadOnly != nil {
+		a.dbReadOnly.Close()
+	}
+	if a.dbReadWrite != nil {
+		a.dbReadWrite.Close()
+	}
+}
+
+func (a *app) getValidator() *validator.Validate {
+	if a.validator == nil {
+		a.validator = validator.New()
+	}
+	return a.validator
+}
+
+// taskRepository returns the repository of the driver, it serves every version of the task api.
+func (a *app) taskRepository() core.TaskRepository {
+	if a.inMemory() {
+		return core.NewMemoryTaskRepository(a.memory.tasks)
+	}
+	if a.inMongo() {
+		return core.NewMongoTaskRepository(a.logger, a.mongoClient, a.mongoClient.Database(a.cfg.Mongodb.Database), "task")
+	}
+	return core.NewTaskRepository(a.logger, a.dbRouter, "task")
+}
+
+// labelRepository returns the repository of the driver, the labels live next to the tasks.
+func (a *app) labelRepository() core.LabelRepository {
+	if a.inMemory() {
+		return core.NewMemoryLabelRepository(a.memory.labels)
+	}
+	if a.inMongo() {
+		return core.NewMongoLabelRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "label")
+	}
+	return core.NewLabelRepository(a.logger, a.dbRouter, "label")
+}
+
+func (a *app) checklistRepository() core.ChecklistRepository {
+	if a.inMemory() {
+		return core.NewMemoryChecklistRepository(a.memory.checklists)
+	}
+	if a.inMongo() {
+		return core.NewMongoChecklistRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_checklist_item")
+	}
+	return core.NewChecklistRepository(a.logger, a.dbRouter, "task_checklist_item")
+}
+
+func (a *app) dependencyRepository() core.DependencyRepository {
+	if a.inMemory() {
+		return core.NewMemoryDependencyRepository(a.memory.dependencies)
+	}
+	if a.inMongo() {
+		return core.NewMongoDependencyRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_dependency")
+	}
+	return core.NewDependencyRepository(a.logger, a.dbRouter, "task_dependency")
+}
+
+func (a *app) projectRepository() core.ProjectRepository {
+	if a.inMemory() {
+		return core.NewMemoryProjectRepository(a.memory.projects)
+	}
+	if a.inMongo() {
+		return core.NewMongoProjectRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "project")
+	}
+	return core.NewProjectRepository(a.logger, a.dbRouter, "project")
+}
+
+func (a *app) commentRepository() core.CommentRepository {
+	if a.inMemory() {
+		return core.NewMemoryCommentRepository(a.memory.comments)
+	}
+	if a.inMongo() {
+		return core.NewMongoCommentRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_comment")
+	}
+	return core.NewCommentRepository(a.logger, a.dbRouter, "task_comment")
+}
+
+func (a *app) activityRepository() core.ActivityRepository {
+	if a.inMemory() {
+		return core.NewMemoryActivityRepository(a.memory.activities)
+	}
+	if a.inMongo() {
+		return core.NewMongoActivityRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_activity")
+	}
+	return core.NewActivityRepository(a.logger, a.dbRouter, "task_activity")
+}
+
+func (a *app) viewRepository() core.ViewRepository {
+	if a.inMemory() {
+		return core.NewMemoryViewRepository(a.memory.views)
+	}
+	if a.inMongo() {
+		return core.NewMongoViewRepository(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task_view")
+	}
+	return core.NewViewRepository(a.logger, a.dbRouter, "task_view")
+}
+
+// taskRepositories returns the repositories of the task service.
+func (a *app) taskRepositories() core.Repositories {
+	return core.Repositories{
+		Tasks:        a.taskRepository(),
+		Labels:       a.labelRepository(),
+		Checklists:   a.checklistRepository(),
+		Dependencies: a.dependencyRepository(),
+		Projects:     a.projectRepository(),
+		Comments:     a.commentRepository(),
+		Activities:   a.activityRepository(),
+		// the users stay on the sql database with the mongo driver.
+		Users:  a.userRepository(),
+		Search: a.searchIndex(),
+	}
+}
+
+// searchIndex returns the full-text index of the sql databases, the text index of mongo or the index of the process.
+func (a *app) searchIndex() core.SearchIndex {
+	if a.inMemory() {
+		return a.invertedIndex
+	}
+	if a.inMongo() {
+		return core.NewMongoSearchIndex(a.logger, a.mongoClient.Database(a.cfg.Mongodb.Database), "task")
+	}
+	return core.NewSearchIndex(a.logger, a.dbRouter, "task")
+}
+
+// taskOptions returns the limits of the task rules.
+func (a *app) taskOptions() core.TaskOptions {
+	return core.TaskOptions{MaxDepth: a.cfg.Task.MaxDepth}
+}
+
+func (a *app) userRepository() user.UserRepository {
+	if a.inMemory() {
+		return user.NewMemoryUserRepository(a.memory.users)
+	}
+	return user.NewUserRepository(a.logger, a.dbRouter, "user_encrypt")
+}
+
+func (a *app) attachmentRepository() attachment.Repository {
+	if a.inMemory() {
+		return attachment.NewMemoryRepository(a.memory.attachments)
+	}
+	return attachment.NewRepository(a.logger, a.dbRouter, "attachment")
+}
+
+func (a *app) apiKeyService() *apikey.Service {
+	if a.inMemory() {
+		return apikey.NewService(a.logger, a.cfg.Application.Timezone, apikey.NewMemoryRepository(a.memory.apiKeys))
+	}
+	return apikey.NewService(a.logger, a.cfg.Application.Timezone, apikey.NewRepository(a.logger, a.dbRouter, "api_key"))
+}
+
+// taskUsecaseV2 returns the usecase without the attachment uploaders, the commands never upload.
+func (a *app) taskUsecaseV2() taskV2.TaskUsecase {
+	return taskV2.NewTaskUsecase(a.logger, core.NewTaskService(a.logger, a.cfg.Application.Timezone, nil, nil, a.taskRepositories(), a.taskOptions()), nil)
+}
+
+func (a *app) userUsecase() user.UserUsecase {
+	return user.NewUserUsecase(a.logger, a.cfg.Application.Timezone, a.userRepository())
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"todo-app-api/configs"
+	"todo-app-api/pkg/hook"
+
+	"github.com/sirupsen/logrus"
+	ddlogrus "gopkg.in/DataDog/dd-trace-go.v1/contrib/sirupsen/logrus"
+)
+
+// command is a subcommand of the binary, a command with subcommands has no run.
+type command struct {
+	usage       string
+	run         func(a *app, args []string) error
+	subcommands map[string]command
+}
+
+var commands = map[string]command{
+	"serve":   {usage: "start the http server", run: serve},
+	"migrate": {usage: "apply or roll back the schema migrations", run: migrate},
+	"seed":    {usage: "load fixture users and tasks from a json file", run: seed},
+	"user": {subcommands: map[string]command{
+		"create": {usage: "create a user", run: createUser},
+	}},
+	"apikey": {subcommands: map[string]command{
+		"issue": {usage: "issue an api key of a user", run: issueAPIKey},
+	}},
+	"task": {subcommands: map[string]command{
+		"export": {usage: "export the tasks as json or csv", run: exportTasks},
+	}},
+	"config": {subcommands: map[string]command{
+		"print": {usage: "print the configuration with the secrets redacted", run: printConfig},
+	}},
+}
+
+// Run runs the subcommand of the arguments, the http server is started when there is none.
+func Run(args []string) (err error) {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	cmd, path, rest := lookup(args)
+	if cmd.run == nil {
+		printUsage(path)
+		if len(rest) > 0 && rest[0] != "help" && rest[0] != "-h" && rest[0] != "--help" {
+			return fmt.Errorf("unknown command %s", strings.Join(args, " "))
+		}
+		return nil
+	}
+
+	cfg := configs.Load()
+	a := &app{cfg: cfg, logger: newLogger(cfg)}
+	defer a.close()
+
+	if err = cmd.run(a, rest); err != nil {
+		a.logger.Error(err)
+	}
+	return
+}
+
+func lookup(args []string) (cmd command, path []string, rest []string) {
+	cmd = command{subcommands: commands}
+	rest = args
+	for len(rest) > 0 && cmd.run == nil {
+		next, ok := cmd.subcommands[rest[0]]
+		if !ok {
+			return
+		}
+		cmd, path, rest = next, append(path, rest[0]), rest[1:]
+	}
+	return
+}
+
+func printUsage(path []string) {
+	cmd := command{subcommands: commands}
+	for _, name := range path {
+		cmd = cmd.subcommands[name]
+	}
+
+	fmt.Fprintf(os.Stderr, "usage: %s <command>\n\ncommands:\n", strings.Join(append([]string{os.Args[0]}, path...), " "))
+	names := make([]string, 0, len(cmd.subcommands))
+	for name := range cmd.subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		usage := cmd.subcommands[name].usage
+		if usage == "" {
+			usage = "see " + strings.TrimSpace(strings.Join(append(path, name), " ")) + " help"
+		}
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, usage)
+	}
+}
+
+func newLogger(cfg *configs.Config) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(cfg.Logger.Formatter)
+	logger.SetReportCaller(true)
+	logger.AddHook(&ddlogrus.DDContextLogHook{})
+	logger.AddHook(hook.NewStdoutLoggerHook(logrus.New(), cfg.Logger.Formatter))
+	return logger
+}
@@ -0,0 +1,8 @@
+package cli
+
+import "os"
+
+// printConfig prints the loaded configuration as json with the secrets redacted.
+func printConfig(a *app, args []string) (err error) {
+	return printJSON(os.Stdout, a.cfg.Redacted())
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"text/tabwriter"
+	"todo-app-api/pkg/migration"
+)
+
+const migrateUsage = `usage: %s migrate <command>
+
+commands:
+  up [-steps n]      apply the pending migrations
+  down [-steps n]    roll back the latest migrations, one by default
+  status             list the migrations and whether they are applied
+  create [-dir dir] <name>
+                     create an empty migration of the next version for every dialect
+`
+
+// migrator returns the migrator of the read write database with the migrations of its dialect.
+func (a *app) migrator() (migrator *migration.Migrator, err error) {
+	files, err := fs.Sub(migration.Files, path.Join("sql", a.dialect().Name()))
+	if err != nil {
+		return
+	}
+
+	migrations, err := migration.Load(files, ".")
+	if err != nil {
+		return
+	}
+
+	migrator = migration.NewMigrator(a.logger, a.cfg.Application.Timezone, a.dbReadWrite, a.dialect(), migrations, a.cfg.Migration.TableName, a.cfg.Migration.LockTimeout)
+	return
+}
+
+// migrate runs the migrate subcommand against the read write database.
+func migrate(a *app, args []string) (err error) {
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return fmt.Errorf("missing migrate command")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	steps := flags.Int("steps", 0, "number of migrations")
+	dir := flags.String("dir", migration.Dir, "directory of the migration files")
+	if err = flags.Parse(args[1:]); err != nil {
+		return
+	}
+
+	if args[0] == "create" {
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: migrate create [-dir dir] <name>")
+		}
+		paths, err := migration.Create(*dir, flags.Arg(0))
+		for _, created := range paths {
+			fmt.Printf("created %s\n", created)
+		}
+		return err
+	}
+
+	if a.inMemory() {
+		return fmt.Errorf("migrations need a sql repository driver, not %s", a.cfg.Repository.Driver)
+	}
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	migrator, err := a.migrator()
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx, *steps)
+		for _, m := range applied {
+			fmt.Printf("applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migration")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Printf("rolled back %06d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Dirty {
+				state = "dirty"
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+
+	fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+	return fmt.Errorf("unknown migrate command %s", args[0])
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"todo-app-api/pkg/response"
+)
+
+// responseError turns the error of a usecase response into an error of the command.
+func responseError(resp response.Response) error {
+	if resp.Error() == nil {
+		return nil
+	}
+	if resp.Message() != "" {
+		return fmt.Errorf("%s: %s", resp.Status(), resp.Message())
+	}
+	return fmt.Errorf("%s: %v", resp.Status(), resp.Error())
+}
+
+func printJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	taskV2 "todo-app-api/cmd/task/v2"
+	"todo-app-api/cmd/user/v1"
+	"todo-app-api/pkg/exception"
+)
+
+// fixture is the content of a seed file.
+type fixture struct {
+	Users []user.UserRequest   `json:"users"`
+	Tasks []taskV2.TaskRequest `json:"tasks"`
+}
+
+// seed loads the users and the tasks of a json file through the usecases of the http api.
+// A user whose email is already registered is skipped, so a seed file can be loaded again.
+func seed(a *app, args []string) (err error) {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: seed <file.json>")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return
+	}
+
+	var data fixture
+	if err = json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("invalid seed file: %w", err)
+	}
+
+	for i, payload := range data.Users {
+		if err = a.getValidator().Struct(payload); err != nil {
+			return fmt.Errorf("user %d: %w", i, err)
+		}
+	}
+	for i, payload := range data.Tasks {
+		if err = a.getValidator().Struct(payload); err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+	}
+
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	userUsecase, taskUsecase := a.userUsecase(), a.taskUsecaseV2()
+
+	createdUsers, skippedUsers := 0, 0
+	for _, payload := range data.Users {
+		resp := userUsecase.CreateUser(ctx, payload)
+		if resp.Error() == exception.ErrConflict {
+			skippedUsers++
+			continue
+		}
+		if err = responseError(resp); err != nil {
+			return fmt.Errorf("user %s: %w", payload.Email, err)
+		}
+		createdUsers++
+	}
+
+	for _, payload := range data.Tasks {
+		if err = responseError(taskUsecase.CreateTask(ctx, payload)); err != nil {
+			return fmt.Errorf("task %s: %w", payload.Name, err)
+		}
+	}
+
+	fmt.Printf("seeded %d users (%d already registered) and %d tasks\n", createdUsers, skippedUsers, len(data.Tasks))
+	return
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"todo-app-api/cmd/task/core"
+	taskV1 "todo-app-api/cmd/task/v1"
+	taskV2 "todo-app-api/cmd/task/v2"
+	"todo-app-api/cmd/user/v1"
+	"todo-app-api/pkg/attachment"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/middleware"
+	"todo-app-api/pkg/notifier"
+	"todo-app-api/pkg/response"
+	"todo-app-api/pkg/scanner"
+	"todo-app-api/server"
+
+	gcs "cloud.google.com/go/storage"
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/mux"
+	"github.com/rs/cors"
+	"google.golang.org/api/option"
+
+	s "todo-app-api/pkg/storage"
+)
+
+var indexMessage string = "Application is running properly"
+
+// serve starts the http server and blocks until the process is signaled to stop.
+func serve(a *app, args []string) (err error) {
+	cfg, logger := a.cfg, a.logger
+
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	// apply the pending migrations, the lock keeps the other instances waiting.
+	if cfg.Migration.OnStart && !a.inMemory() {
+		migrator, err := a.migrator()
+		if err != nil {
+			return err
+		}
+		if _, err := migrator.Up(context.Background(), 0); err != nil {
+			return err
+		}
+	}
+
+	if a.dbRouter != nil {
+		a.dbRouter.Start()
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/todo", index)
+
+	basicAuthMiddleware := middleware.NewBasicAuth(cfg.BasicAuth.Username, cfg.BasicAuth.Password)
+	authMiddleware := middleware.NewAPIKeyAuth(a.apiKeyService(), basicAuthMiddleware)
+
+	// set google cloud storage
+	credentials, _ := ioutil.ReadFile("./secret/gcp_credential.json")
+	gcsclient, _ := gcs.NewClient(context.Background(), option.WithCredentialsJSON(credentials))
+	gcs := s.NewGCSAdapter(gcsclient, cfg.GCPStorage.AccessID, string(cfg.GCPStorage.PrivateKey))
+
+	// set validator
+	validator := a.getValidator()
+	// validator.RegisterTagNameFunc(customvalidator.SetTagName)
+	// validator.RegisterValidation("default-name", customvalidator.SetDefaultName)
+	// validator.RegisterValidation("idn-mobile-number", customvalidator.SetIDNMobileNumber)
+	// validator.RegisterValidation("ISO8601date", customvalidator.SetISO8601dateFormat)
+
+	// set attachment policy
+	attachmentRules := make(map[string]attachment.Rule)
+	for folder, rule := range cfg.Attachment.Folders {
+		attachmentRules[folder] = attachment.Rule{AllowedTypes: rule.AllowedTypes, MaxSize: rule.MaxSize}
+	}
+	attachmentPolicy := attachment.NewPolicy(cfg.Attachment.Bucket, cfg.Attachment.PathPrefix, cfg.Attachment.PublicHost, attachmentRules)
+	thumbnailer := attachment.NewThumbnailer(logger, gcs, cfg.Attachment.Thumbnail.Sizes, cfg.Attachment.Thumbnail.Workers, cfg.Attachment.Thumbnail.QueueSize, cfg.Attachment.Thumbnail.MaxPixels)
+	thumbnailer.Start()
+
+	// set attachment scanner
+	var attachmentScanner scanner.Scanner = scanner.NewNoopScanner()
+	if cfg.Attachment.Scan.Scanner == "clamd" {
+		attachmentScanner = scanner.NewClamdScanner(cfg.Attachment.Scan.ClamdAddress, cfg.Attachment.Scan.ClamdTimeout)
+	}
+	attachmentRepository := a.attachmentRepository()
+	scanWorker := attachment.NewScanWorker(logger, cfg.Application.Timezone, gcs, attachmentRepository, attachmentScanner, notifier.NewLogNotifier(logger), thumbnailer, attachment.ScanWorkerOptions{
+		Mode:             cfg.Attachment.Scan.Mode,
+		InfectedAction:   cfg.Attachment.Scan.InfectedAction,
+		QuarantinePrefix: cfg.Attachment.Scan.QuarantinePrefix,
+		StagingPrefix:    cfg.Attachment.Scan.StagingPrefix,
+		Workers:          cfg.Attachment.Scan.Workers,
+		QueueSize:        cfg.Attachment.Scan.QueueSize,
+		RetryInterval:    cfg.Attachment.Scan.RetryInterval,
+	})
+	scanWorker.Start()
+	attachmentUploader := attachment.NewUploader(logger, cfg.Application.Timezone, gcs, attachmentPolicy, attachmentRepository, scanWorker, thumbnailer)
+
+	// set resumable upload, the memory driver keeps the sessions in the process too.
+	redisClient := redis.NewClient(cfg.Redis.Options)
+	uploadSessionStore := attachment.NewMemorySessionStore()
+	locker := lock.NewMemoryLocker()
+	if !a.inMemory() {
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			logger.Warn("redis is not reachable, resumable uploads, reminders and the attachment gc are unavailable: ", err)
+		}
+		uploadSessionStore = attachment.NewRedisSessionStore(redisClient, fmt.Sprintf("%s:upload:", cfg.Application.Name))
+		locker = lock.NewRedisLocker(redisClient, fmt.Sprintf("%s:lock:", cfg.Application.Name))
+	}
+	resumableUploader := attachment.NewResumableUploader(logger, cfg.Application.Timezone, gcs, attachmentPolicy, uploadSessionStore, attachmentUploader, cfg.Attachment.Resumable.SessionTTL, cfg.Attachment.Resumable.MaxChunkSize)
+
+	// both versions of the task api share the service, they only map their payloads.
+	repositories := a.taskRepositories()
+	taskRepository := repositories.Tasks
+	// the lock keeps the replicas from writing the dependency edges of a cycle at the same time.
+	taskOptions := a.taskOptions()
+	taskOptions.Locker = locker
+	taskService := core.NewTaskService(logger, cfg.Application.Timezone, attachmentUploader, notifier.NewLogNotifier(logger), repositories, taskOptions)
+
+	taskUsecaseV1 := taskV1.NewTaskUsecase(logger, taskService)
+	taskV1.NewTaskHTTPHandler(logger, router, authMiddleware, validator, attachmentPolicy, taskUsecaseV1)
+
+	taskUsecaseV2 := taskV2.NewTaskUsecase(logger, taskService, resumableUploader)
+	taskV2.NewTaskHTTPHandler(logger, router, authMiddleware, validator, attachmentPolicy, taskUsecaseV2)
+
+	labelUsecase := taskV2.NewLabelUsecase(logger, core.NewLabelService(logger, cfg.Application.Timezone, repositories.Labels, taskRepository))
+	taskV2.NewLabelHTTPHandler(logger, router, authMiddleware, validator, labelUsecase)
+
+	projectUsecase := taskV2.NewProjectUsecase(logger, core.NewProjectService(logger, cfg.Application.Timezone, repositories.Projects, taskRepository))
+	taskV2.NewProjectHTTPHandler(logger, router, authMiddleware, validator, projectUsecase)
+
+	commentService := core.NewCommentService(logger, cfg.Application.Timezone, repositories.Comments, taskRepository, repositories.Users, notifier.NewLogNotifier(logger))
+	taskV2.NewCommentHTTPHandler(logger, router, authMiddleware, validator, taskV2.NewCommentUsecase(logger, commentService))
+
+	viewService := core.NewViewService(logger, cfg.Application.Timezone, a.viewRepository())
+	taskV2.NewViewHTTPHandler(logger, router, authMiddleware, validator, taskV2.NewViewUsecase(logger, viewService, taskService))
+
+	// set attachment garbage collector, every replica runs it and the lock picks the one that reconciles.
+	reconciler := attachment.NewReconciler(logger, gcs, attachmentPolicy, attachmentRepository, uploadSessionStore, scanWorker, taskRepository, locker, cfg.Attachment.GC.Interval, cfg.Attachment.GC.GracePeriod, cfg.Attachment.GC.DryRun)
+	if cfg.Attachment.GC.Enabled {
+		reconciler.Start(cfg.Attachment.GC.Interval)
+	}
+
+	// set task reminders, every replica runs the scheduler and the lock picks the sender.
+	reminderScheduler := core.NewReminderScheduler(logger, cfg.Application.Timezone, taskRepository, notifier.NewLogNotifier(logger), locker, core.ReminderOptions{
+		LockTTL:   cfg.Reminder.LockTTL,
+		BatchSize: cfg.Reminder.BatchSize,
+	})
+	if cfg.Reminder.Enabled {
+		reminderScheduler.Start(cfg.Reminder.Interval)
+	}
+
+	// set task rank rebalancing, every replica runs the balancer and the lock picks the one that rebalances.
+	rankBalancer := core.NewRankBalancer(logger, taskRepository, locker, core.RankOptions{
+		MaxLength: cfg.Task.RankMaxLength,
+		LockTTL:   cfg.Task.RankRebalanceInterval,
+	})
+	rankBalancer.Start(cfg.Task.RankRebalanceInterval)
+
+	user.NewUserHTTPHandler(logger, router, authMiddleware, validator, a.userUsecase())
+
+	handler := middleware.ClientDeviceMiddleware(router)
+	// set cors
+	handler = cors.New(cors.Options{
+		AllowedOrigins:   cfg.Application.AllowedOrigins,
+		AllowedMethods:   []string{http.MethodPost, http.MethodGet, http.MethodPut, http.MethodDelete, http.MethodPatch, http.MethodHead},
+		AllowedHeaders:   []string{"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization", "X-API-Key", "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Metadata"},
+		ExposedHeaders:   []string{"Location", "Tus-Resumable", "Tus-Version", "Upload-Offset", "Upload-Length", "Upload-Expires"},
+		AllowCredentials: true,
+	}).Handler(handler)
+	handler = middleware.NewRecovery(logger, true).Handler(handler)
+
+	// initiate server
+	srv := server.NewServer(logger, handler, cfg.Application.Port)
+	srv.Start()
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+	<-sigterm
+
+	srv.Close()
+	if cfg.Attachment.GC.Enabled {
+		reconciler.Close()
+	}
+	if cfg.Reminder.Enabled {
+		reminderScheduler.Close()
+	}
+	rankBalancer.Close()
+	scanWorker.Close()
+	thumbnailer.Close()
+	redisClient.Close()
+
+	return
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	resp := response.NewSuccessResponse(nil, response.StatOK, indexMessage)
+	response.JSON(w, resp)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+	taskV2 "todo-app-api/cmd/task/v2"
+)
+
+// exportTasks writes the tasks of the v2 api to stdout or to a file.
+func exportTasks(a *app, args []string) (err error) {
+	flags := flag.NewFlagSet("task export", flag.ContinueOnError)
+	format := flags.String("format", "json", "json or csv")
+	name := flags.String("name", "", "export the tasks of this name only")
+	output := flags.String("o", "", "output file, stdout by default")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("invalid format %s", *format)
+	}
+
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	var filter taskV2.GetManyTaskRequest
+	if *name != "" {
+		filter.Name = name
+	}
+
+	resp := a.taskUsecaseV2().GetManyTasks(context.Background(), filter)
+	if err = responseError(resp); err != nil {
+		return
+	}
+	tasks := resp.Data().([]taskV2.TaskResponse)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "json" {
+		return printJSON(w, tasks)
+	}
+	return writeTasksCSV(w, tasks)
+}
+
+func writeTasksCSV(w io.Writer, tasks []taskV2.TaskResponse) (err error) {
+	writer := csv.NewWriter(w)
+	if err = writer.Write([]string{"id", "name", "description", "status", "attachment", "created_at", "updated_at"}); err != nil {
+		return
+	}
+
+	for _, task := range tasks {
+		record := []string{strconv.FormatInt(task.ID, 10), task.Name, "", "", "", task.CreatedAt.Format(time.RFC3339), ""}
+		if task.Description != nil {
+			record[2] = *task.Description
+		}
+		if task.Status != nil {
+			record[3] = strconv.Itoa(*task.Status)
+		}
+		if task.Attachment != nil {
+			record[4] = *task.Attachment
+		}
+		if task.UpdatedAt != nil {
+			record[6] = task.UpdatedAt.Format(time.RFC3339)
+		}
+		if err = writer.Write(record); err != nil {
+			return
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"todo-app-api/cmd/user/v1"
+)
+
+// createUser creates a user through the same usecase as the http api.
+func createUser(a *app, args []string) (err error) {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	name := flags.String("name", "", "name of the user")
+	email := flags.String("email", "", "email of the user")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	payload := user.UserRequest{Name: *name, Email: *email}
+	if err = a.getValidator().Struct(payload); err != nil {
+		return
+	}
+
+	if err = a.openDatabases(); err != nil {
+		return
+	}
+
+	resp := a.userUsecase().CreateUser(context.Background(), payload)
+	if err = responseError(resp); err != nil {
+		return
+	}
+	return printJSON(os.Stdout, resp.Data())
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+)
+
+// activityFields are the fields of a task whose changes are recorded, by their name in the task entity.
+var activityFields = []struct {
+	name  string
+	value func(task entity.Task) interface{}
+}{
+	{"name", func(task entity.Task) interface{} { return task.Name }},
+	{"description", func(task entity.Task) interface{} { return task.Description }},
+	{"status", func(task entity.Task) interface{} { return task.Status }},
+	{"priority", func(task entity.Task) interface{} { return task.Priority }},
+	{"attachment", func(task entity.Task) interface{} { return task.Attachment }},
+	{"due_at", func(task entity.Task) interface{} { return activityTime(task.DueAt) }},
+	{"remind_at", func(task entity.Task) interface{} { return activityTime(task.RemindAt) }},
+	{"recurrence", func(task entity.Task) interface{} { return task.Recurrence }},
+	{"label_ids", func(task entity.Task) interface{} { return sortedIDs(task.LabelIDs) }},
+	{"assignees", func(task entity.Task) interface{} { return sortedUsers(task.Assignees) }},
+	{"watchers", func(task entity.Task) interface{} { return sortedUsers(task.Watchers) }},
+	{"parent_id", func(task entity.Task) interface{} { return task.ParentID }},
+	{"project_id", func(task entity.Task) interface{} { return task.ProjectID }},
+}
+
+func (s *taskService) GetActivities(ctx context.Context, id int64, page ActivityPage) (activities []entity.TaskActivity, err error) {
+	if _, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	if activities, err = s.activityRepository.FindByTaskId(ctx, id, page.BeforeID, page.Limit); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+	return
+}
+
+// record writes the activity of a change of the task in the transaction of the change, a created task has no previous state.
+// The actor and the client device are the ones of the request, an update that changes none of the activity fields isn't recorded.
+func (s *taskService) record(ctx context.Context, tx database.Tx, previous *entity.Task, task entity.Task) (err error) {
+	changes := diffTask(previous, task)
+	if previous != nil && len(changes) == 0 {
+		return
+	}
+
+	activity := entity.TaskActivity{
+		TaskID:    task.ID,
+		Action:    activityAction(previous, changes),
+		Actor:     actorOf(ctx),
+		Changes:   changes,
+		CreatedAt: time.Now().In(s.location),
+	}
+	activity.ClientDevice, _ = ctx.Value(entity.ClientContextKey{}).(entity.ClientDevice)
+
+	_, err = s.activityRepository.Save(ctx, activity, tx)
+	return
+}
+
+// diffTask returns the activity fields that differ, every field that is set when the task is created.
+func diffTask(previous *entity.Task, task entity.Task) (changes []entity.FieldChange) {
+	before := entity.Task{}
+	if previous != nil {
+		before = *previous
+	}
+
+	changes = make([]entity.FieldChange, 0)
+	for _, field := range activityFields {
+		from, _ := json.Marshal(field.value(before))
+		to, _ := json.Marshal(field.value(task))
+		if bytes.Equal(from, to) {
+			continue
+		}
+		if previous == nil {
+			from = []byte("null")
+		}
+		changes = append(changes, entity.FieldChange{Field: field.name, From: from, To: to})
+	}
+	return
+}
+
+// activityAction names a change by its most significant field, a status change wins over an attachment change.
+func activityAction(previous *entity.Task, changes []entity.FieldChange) string {
+	if previous == nil {
+		return entity.TaskActivityCreate
+	}
+
+	action := entity.TaskActivityUpdate
+	for _, change := range changes {
+		switch change.Field {
+		case "status":
+			return entity.TaskActivityStatus
+		case "attachment":
+			action = entity.TaskActivityAttachment
+		}
+	}
+	return action
+}
+
+// actorOf returns the subject of the principal of the request, empty outside of a request.
+func actorOf(ctx context.Context) string {
+	principal, _ := ctx.Value(entity.PrincipalContextKey{}).(entity.Principal)
+	return principal.Subject
+}
+
+// activityTime compares the dates by the second in UTC, the precision of the sql columns.
+func activityTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+func sortedIDs(ids []int64) []int64 {
+	if len(ids) == 0 {
+		return nil
+	}
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func sortedUsers(userUUIDs []string) []string {
+	if len(userUUIDs) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), userUUIDs...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// ActivityRepository stores the history of the tasks, an activity is never changed once it is written.
+// Save takes the transaction of the change that the activity records.
+type ActivityRepository interface {
+	Save(ctx context.Context, activity entity.TaskActivity, tx database.Tx) (id int64, err error)
+	// FindByTaskId returns at most limit activities of the task before the activity beforeID, the newest first.
+	// A zero beforeID starts from the newest activity and a zero limit returns every activity.
+	FindByTaskId(ctx context.Context, taskID int64, beforeID int64, limit int) (activities []entity.TaskActivity, err error)
+}
+
+const activityColumns = "a.id, a.task_id, a.action, a.actor, a.remote_address, a.x_forwarded_for, a.x_real_ip, a.user_agent, a.changes, a.created_at"
+
+type activityRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewActivityRepository is a constructor
+func NewActivityRepository(logger *logrus.Logger, db *database.Router, tableName string) ActivityRepository {
+	return &activityRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *activityRepository) Save(ctx context.Context, activity entity.TaskActivity, tx database.Tx) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+	if sqlTx, ok := tx.(*sql.Tx); ok {
+		cmd = sqlTx
+	}
+
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return
+	}
+
+	device := activity.ClientDevice
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("task_id", "action", "actor", "remote_address", "x_forwarded_for", "x_real_ip", "user_agent", "changes", "created_at").
+		Values(activity.TaskID, activity.Action, activity.Actor, device.RemoteAddress, device.XForwardedFor, device.XRealIP, device.UserAgent, string(changes), activity.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *activityRepository) FindByTaskId(ctx context.Context, taskID int64, beforeID int64, limit int) (activities []entity.TaskActivity, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	builder := r.builder.Select(activityColumns).From(r.tableName + " a").Where(sq.Eq{"a.task_id": taskID}).OrderBy("a.id DESC")
+	if beforeID > 0 {
+		builder = builder.Where(sq.Lt{"a.id": beforeID})
+	}
+	if limit > 0 {
+		builder = builder.Limit(uint64(limit))
+	}
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	activities = make([]entity.TaskActivity, 0)
+	for rows.Next() {
+		var activity entity.TaskActivity
+		var changes string
+		device := &activity.ClientDevice
+		if err = rows.Scan(&activity.ID, &activity.TaskID, &activity.Action, &activity.Actor, &device.RemoteAddress, &device.XForwardedFor, &device.XRealIP, &device.UserAgent, &changes, &activity.CreatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		if err = json.Unmarshal([]byte(changes), &activity.Changes); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		activities = append(activities, activity)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
@@ -0,0 +1,50 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestActivities(t *testing.T) {
+	ctx := context.WithValue(context.Background(), entity.PrincipalContextKey{}, entity.Principal{Subject: "u-1", Method: "api_key"})
+	ctx = context.WithValue(ctx, entity.ClientContextKey{}, entity.ClientDevice{RemoteAddress: "10.0.0.1:5000", UserAgent: "cli"})
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, newTestRepositories(), core.TaskOptions{})
+
+	task, err := service.CreateTask(ctx, core.TaskRequest{Name: "draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := entity.TaskStatusOnProgress
+	if _, err = service.UpdateTask(ctx, task.ID, core.TaskRequest{Name: "release", Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	// an update that changes nothing isn't recorded.
+	if _, err = service.UpdateTask(ctx, task.ID, core.TaskRequest{Name: "release", Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+
+	activities, err := service.GetActivities(ctx, task.ID, core.ActivityPage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 2 || activities[0].Action != entity.TaskActivityStatus || activities[1].Action != entity.TaskActivityCreate {
+		t.Fatalf("expected a status change after the creation, got %+v", activities)
+	}
+	latest := activities[0]
+	if latest.Actor != "u-1" || latest.ClientDevice.UserAgent != "cli" {
+		t.Errorf("expected the actor and the device of the request, got %+v", latest)
+	}
+	if len(latest.Changes) != 2 || latest.Changes[0].Field != "name" || string(latest.Changes[0].From) != `"draft"` || string(latest.Changes[1].To) != "1" {
+		t.Errorf("unexpected changes %+v", latest.Changes)
+	}
+
+	page, err := service.GetActivities(ctx, task.ID, core.ActivityPage{BeforeID: latest.ID, Limit: 1})
+	if err != nil || len(page) != 1 || page[0].ID != activities[1].ID || string(page[0].Changes[0].From) != "null" {
+		t.Errorf("unexpected page %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/notifier"
+)
+
+// Assign adds the user to the assignees of the task, assigning an assignee again changes nothing.
+func (s *taskService) Assign(ctx context.Context, id int64, userUUID string) (task entity.Task, err error) {
+	return s.addUser(ctx, id, userUUID, assigneesOf)
+}
+
+// Unassign takes the user off the assignees of the task, unassigning a user that isn't assigned changes nothing.
+func (s *taskService) Unassign(ctx context.Context, id int64, userUUID string) (task entity.Task, err error) {
+	return s.removeUser(ctx, id, userUUID, assigneesOf)
+}
+
+// Watch adds the user to the watchers of the task, the watchers are notified of its changes.
+func (s *taskService) Watch(ctx context.Context, id int64, userUUID string) (task entity.Task, err error) {
+	return s.addUser(ctx, id, userUUID, watchersOf)
+}
+
+// Unwatch takes the user off the watchers of the task.
+func (s *taskService) Unwatch(ctx context.Context, id int64, userUUID string) (task entity.Task, err error) {
+	return s.removeUser(ctx, id, userUUID, watchersOf)
+}
+
+// addUser adds the user to the users of the task that users returns, ErrUnknownUser is returned for a user that doesn't exist.
+func (s *taskService) addUser(ctx context.Context, id int64, userUUID string, users func(taskRequest *TaskRequest) *[]string) (task entity.Task, err error) {
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+	if _, err = s.users.FindOneUserByUUID(ctx, userUUID); err != nil {
+		if err == exception.ErrNotFound {
+			return task, ErrUnknownUser
+		}
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	taskRequest := requestOf(task)
+	field := users(&taskRequest)
+	if containsString(*field, userUUID) {
+		return task, nil
+	}
+	*field = append(append([]string(nil), *field...), userUUID)
+	return s.update(ctx, task, taskRequest, nil)
+}
+
+func (s *taskService) removeUser(ctx context.Context, id int64, userUUID string, users func(taskRequest *TaskRequest) *[]string) (task entity.Task, err error) {
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	taskRequest := requestOf(task)
+	field := users(&taskRequest)
+	if !containsString(*field, userUUID) {
+		return task, nil
+	}
+	remaining := make([]string, 0, len(*field))
+	for _, existing := range *field {
+		if existing != userUUID {
+			remaining = append(remaining, existing)
+		}
+	}
+	*field = remaining
+	return s.update(ctx, task, taskRequest, nil)
+}
+
+// notifyWatchers notifies the watchers of the task but the actor of the fields that changed, a change of the watchers
+// alone isn't notified. The task is written even when the notifications fail, the failures are logged.
+func (s *taskService) notifyWatchers(ctx context.Context, previous entity.Task, task entity.Task) {
+	if s.notifier == nil || len(task.Watchers) == 0 {
+		return
+	}
+
+	fields := make([]string, 0)
+	for _, change := range diffTask(&previous, task) {
+		if change.Field != "watchers" {
+			fields = append(fields, change.Field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	actor := actorOf(ctx)
+	for _, watcher := range task.Watchers {
+		if watcher == actor {
+			continue
+		}
+		user, err := s.users.FindOneUserByUUID(ctx, watcher)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(err)
+			continue
+		}
+
+		err = s.notifier.Notify(ctx, notifier.Notification{
+			Recipient: user.Email,
+			Subject:   "A task you watch changed",
+			Message:   fmt.Sprintf("%s changed the %s of %s", actor, strings.Join(fields, ", "), task.Name),
+			Data: map[string]string{
+				"task.id": strconv.FormatInt(task.ID, 10),
+				"fields":  strings.Join(fields, ","),
+			},
+		})
+		if err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+	}
+}
+
+func assigneesOf(taskRequest *TaskRequest) *[]string {
+	return &taskRequest.Assignees
+}
+
+func watchersOf(taskRequest *TaskRequest) *[]string {
+	return &taskRequest.Watchers
+}
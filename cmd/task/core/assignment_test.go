@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAssignments(t *testing.T) {
+	ctx := context.WithValue(context.Background(), entity.PrincipalContextKey{}, entity.Principal{Subject: "u-1", Method: "api_key"})
+	repositories := newTestRepositories()
+	repositories.Users = fakeUsers{
+		{UUID: "u-1", Name: "Jane", Email: "jane@example.com"},
+		{UUID: "u-2", Name: "Bob", Email: "bob@example.com"},
+	}
+	notifications := &fakeNotifier{}
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, notifications, repositories, core.TaskOptions{})
+
+	assigned, _ := service.CreateTask(ctx, core.TaskRequest{Name: "assigned"})
+	service.CreateTask(ctx, core.TaskRequest{Name: "open"})
+
+	if _, err := service.Assign(ctx, assigned.ID, "u-404"); err != core.ErrUnknownUser {
+		t.Errorf("expected an unknown user, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := service.Assign(ctx, assigned.ID, "u-2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	task, err := service.Watch(ctx, assigned.ID, "u-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.Watch(ctx, assigned.ID, "u-1")
+	if len(task.Assignees) != 1 || task.Assignees[0] != "u-2" || len(task.Watchers) != 1 {
+		t.Errorf("unexpected users %v and %v", task.Assignees, task.Watchers)
+	}
+
+	assignee := "u-2"
+	mine, _ := service.GetManyTasks(ctx, core.Filter{Assignee: &assignee})
+	unassigned, _ := service.GetManyTasks(ctx, core.Filter{Unassigned: true})
+	if len(mine) != 1 || mine[0].ID != assigned.ID || len(unassigned) != 1 || unassigned[0].ID == assigned.ID {
+		t.Errorf("unexpected filters %+v and %+v", mine, unassigned)
+	}
+
+	// an update keeps the users, the watchers but the actor are notified.
+	if task, err = service.UpdateTask(ctx, assigned.ID, core.TaskRequest{Name: "renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Assignees) != 1 || len(task.Watchers) != 2 {
+		t.Errorf("expected the update to keep the users, got %v and %v", task.Assignees, task.Watchers)
+	}
+	if len(notifications.notifications) != 1 || notifications.notifications[0].Recipient != "bob@example.com" {
+		t.Errorf("expected bob to be notified, got %+v", notifications.notifications)
+	}
+
+	if task, err = service.Unassign(ctx, assigned.ID, "u-2"); err != nil || len(task.Assignees) != 0 {
+		t.Errorf("expected the task to be unassigned, got %v, %v", task.Assignees, err)
+	}
+}
+
+// reversedTasks returns the users of a task in reverse, like a database that keeps no order.
+type reversedTasks struct {
+	core.TaskRepository
+}
+
+func (r reversedTasks) FindOneById(ctx context.Context, id int64) (task entity.Task, err error) {
+	if task, err = r.TaskRepository.FindOneById(ctx, id); err != nil {
+		return
+	}
+	assignees := make([]string, 0, len(task.Assignees))
+	for i := len(task.Assignees) - 1; i >= 0; i-- {
+		assignees = append(assignees, task.Assignees[i])
+	}
+	task.Assignees = assignees
+	return
+}
+
+func TestReorderedAssigneesAreNoChange(t *testing.T) {
+	ctx := context.WithValue(context.Background(), entity.PrincipalContextKey{}, entity.Principal{Subject: "u-1", Method: "api_key"})
+	repositories := newTestRepositories()
+	repositories.Tasks = reversedTasks{repositories.Tasks}
+	repositories.Users = fakeUsers{
+		{UUID: "u-1", Name: "Jane", Email: "jane@example.com"},
+		{UUID: "u-2", Name: "Bob", Email: "bob@example.com"},
+		{UUID: "u-3", Name: "Ann", Email: "ann@example.com"},
+	}
+	notifications := &fakeNotifier{}
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, notifications, repositories, core.TaskOptions{})
+
+	task, _ := service.CreateTask(ctx, core.TaskRequest{Name: "shared"})
+	service.Assign(ctx, task.ID, "u-2")
+	service.Assign(ctx, task.ID, "u-3")
+	service.Watch(ctx, task.ID, "u-2")
+	before, err := service.GetActivities(ctx, task.ID, core.ActivityPage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notified := len(notifications.notifications)
+
+	if _, err = service.UpdateTask(ctx, task.ID, core.TaskRequest{Name: "shared"}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := service.GetActivities(ctx, task.ID, core.ActivityPage{})
+	if len(after) != len(before) {
+		t.Errorf("expected no activity, got %+v", after[0])
+	}
+	if len(notifications.notifications) != notified {
+		t.Errorf("expected no notification, got %+v", notifications.notifications[notified:])
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"todo-app-api/entity"
+)
+
+// BoardStatuses are the columns of the board in order, a column holds the tasks of its status.
+var BoardStatuses = []int{entity.TaskStatusInitiate, entity.TaskStatusOnProgress, entity.TaskStatusDone}
+
+// GetBoard returns a page of the tasks of every column in the manual order, and the number of tasks of the column.
+// The tasks of the archived projects are left out like in GetManyTasks.
+func (s *taskService) GetBoard(ctx context.Context, boardRequest BoardRequest) (board Board, err error) {
+	filter, err := s.resolveFilter(ctx, boardRequest.Filter)
+	if err != nil {
+		return
+	}
+	filter.Sort, filter.After = SortManual, boardRequest.After
+	if boardRequest.Limit > 0 {
+		// the task past the page tells that there is a next page.
+		filter.Limit = boardRequest.Limit + 1
+	}
+
+	statuses := boardRequest.Statuses
+	if len(statuses) == 0 {
+		statuses = BoardStatuses
+	}
+	board.Columns = make([]BoardColumn, 0, len(statuses))
+	for _, status := range statuses {
+		column := BoardColumn{Status: status}
+		filter.Statuses = []int{status}
+		if column.Tasks, err = s.findTasks(ctx, filter); err != nil {
+			return
+		}
+		if filter.Query != "" {
+			highlightMatches(column.Tasks, filter.Query)
+		}
+		if boardRequest.Limit > 0 && len(column.Tasks) > boardRequest.Limit {
+			column.Tasks = column.Tasks[:boardRequest.Limit]
+			last := column.Tasks[len(column.Tasks)-1]
+			column.Next = &Position{Rank: last.Rank, ID: last.ID}
+		}
+		if column.Count, err = s.taskRepository.Count(ctx, filter); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+			return board, s.wrapError(err)
+		}
+		board.Columns = append(board.Columns, column)
+	}
+	return
+}
+
+// MoveOnBoard changes the status and the rank of the task in a transaction. The status follows the rules of UpdateTask,
+// the neighbours must be in the column of the status.
+func (s *taskService) MoveOnBoard(ctx context.Context, id int64, boardMove BoardMoveRequest) (task entity.Task, err error) {
+	if !containsInt(BoardStatuses, boardMove.Status) {
+		return task, fmt.Errorf("%w: the column doesn't exist", ErrInvalidMove)
+	}
+
+	var between string
+	if boardMove.AfterID != nil || boardMove.BeforeID != nil {
+		if between, err = s.moveRank(ctx, id, boardMove.MoveRequest, &boardMove.Status); err != nil {
+			return
+		}
+	}
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	taskRequest := requestOf(task)
+	taskRequest.Status, taskRequest.Rank = &boardMove.Status, between
+	return s.update(ctx, task, taskRequest, nil)
+}
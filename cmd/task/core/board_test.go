@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, newTestRepositories(), core.TaskOptions{})
+
+	var ids []int64
+	for _, name := range []string{"a", "b", "c", "d"} {
+		task, _ := service.CreateTask(ctx, core.TaskRequest{Name: name})
+		ids = append(ids, task.ID)
+	}
+	column := func(board core.Board, status int) (names string, count int) {
+		for _, column := range board.Columns {
+			if column.Status != status {
+				continue
+			}
+			for _, task := range column.Tasks {
+				names += task.Name
+			}
+			return names, column.Count
+		}
+		t.Fatalf("expected the column %d, got %+v", status, board.Columns)
+		return
+	}
+
+	progress := entity.TaskStatusOnProgress
+	if _, err := service.MoveOnBoard(ctx, ids[2], core.BoardMoveRequest{Status: progress}); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := service.MoveOnBoard(ctx, ids[3], core.BoardMoveRequest{Status: progress, MoveRequest: core.MoveRequest{BeforeID: &ids[2]}})
+	if err != nil || moved.Status != progress {
+		t.Fatalf("unexpected move %+v %v", moved, err)
+	}
+
+	board, err := service.GetBoard(ctx, core.BoardRequest{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Columns) != len(core.BoardStatuses) {
+		t.Fatalf("expected a column per status, got %+v", board.Columns)
+	}
+	if names, count := column(board, entity.TaskStatusInitiate); names != "a" || count != 2 {
+		t.Errorf("expected the first page of the initial tasks, got %s of %d", names, count)
+	}
+	if names, count := column(board, progress); names != "d" || count != 2 {
+		t.Errorf("expected the moved task first, got %s of %d", names, count)
+	}
+	if names, count := column(board, entity.TaskStatusDone); names != "" || count != 0 || board.Columns[2].Next != nil {
+		t.Errorf("expected an empty column, got %s of %d", names, count)
+	}
+
+	next, err := service.GetBoard(ctx, core.BoardRequest{Statuses: []int{progress}, After: board.Columns[1].Next, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := column(next, progress); names != "c" || len(next.Columns) != 1 || next.Columns[0].Next != nil {
+		t.Errorf("expected the last page of the column, got %s %+v", names, next.Columns)
+	}
+
+	// a neighbour in another column and a blocked task are rejected, the task stays where it was.
+	if _, err := service.MoveOnBoard(ctx, ids[0], core.BoardMoveRequest{Status: progress, MoveRequest: core.MoveRequest{AfterID: &ids[1]}}); !errors.Is(err, core.ErrInvalidMove) {
+		t.Errorf("expected a neighbour in another column to be rejected, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, ids[0], ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.MoveOnBoard(ctx, ids[0], core.BoardMoveRequest{Status: progress, MoveRequest: core.MoveRequest{AfterID: &ids[2]}}); err != core.ErrBlocked {
+		t.Errorf("expected the blocked task to stay, got %v", err)
+	}
+	board, _ = service.GetBoard(ctx, core.BoardRequest{})
+	if names, _ := column(board, entity.TaskStatusInitiate); names != "ab" {
+		t.Errorf("expected the initial tasks to be kept, got %s", names)
+	}
+	if names, _ := column(board, progress); names != "dc" {
+		t.Errorf("expected the started tasks to be kept, got %s", names)
+	}
+}
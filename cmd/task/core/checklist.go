@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+)
+
+// GetChecklist returns the checklist items of the task in order.
+func (s *taskService) GetChecklist(ctx context.Context, id int64) (items []entity.ChecklistItem, err error) {
+	if _, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	if items, err = s.checklistRepository.FindByTaskId(ctx, id); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+	return
+}
+
+// AddChecklistItem adds an item at the end of the checklist of the task.
+func (s *taskService) AddChecklistItem(ctx context.Context, id int64, itemRequest ChecklistItemRequest) (item entity.ChecklistItem, err error) {
+	items, err := s.GetChecklist(ctx, id)
+	if err != nil {
+		return
+	}
+
+	item = entity.ChecklistItem{
+		TaskID:    id,
+		Name:      strings.TrimSpace(itemRequest.Name),
+		Done:      itemRequest.Done != nil && *itemRequest.Done,
+		Position:  len(items),
+		CreatedAt: time.Now().In(s.location),
+	}
+	if len(items) > 0 {
+		item.Position = items[len(items)-1].Position + 1
+	}
+	if item.ID, err = s.checklistRepository.Save(ctx, item); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return item, exception.ErrInternalServer
+	}
+	return
+}
+
+// UpdateChecklistItem renames or toggles an item of the task, an empty name and a nil done are kept.
+func (s *taskService) UpdateChecklistItem(ctx context.Context, id int64, itemID int64, itemRequest ChecklistItemRequest) (item entity.ChecklistItem, err error) {
+	if item, err = s.checklistItem(ctx, id, itemID); err != nil {
+		return
+	}
+
+	if name := strings.TrimSpace(itemRequest.Name); name != "" {
+		item.Name = name
+	}
+	if itemRequest.Done != nil {
+		item.Done = *itemRequest.Done
+	}
+	updatedAt := time.Now().In(s.location)
+	item.UpdatedAt = &updatedAt
+
+	if err = s.checklistRepository.UpdateById(ctx, itemID, item); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		err = exception.ErrInternalServer
+	}
+	return
+}
+
+// ReorderChecklist puts the items of the task in the order of itemIDs, which lists every item once.
+func (s *taskService) ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (items []entity.ChecklistItem, err error) {
+	if items, err = s.GetChecklist(ctx, id); err != nil {
+		return
+	}
+
+	if len(itemIDs) != len(items) || len(uniqueIDs(itemIDs)) != len(items) {
+		return nil, ErrInvalidChecklistOrder
+	}
+	positions := make(map[int64]int, len(items))
+	for position, itemID := range itemIDs {
+		positions[itemID] = position
+	}
+	for i := range items {
+		position, ok := positions[items[i].ID]
+		if !ok {
+			return nil, ErrInvalidChecklistOrder
+		}
+		items[i].Position = position
+	}
+
+	if err = s.checklistRepository.UpdatePositions(ctx, id, itemIDs); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+
+	ordered := make([]entity.ChecklistItem, len(items))
+	for _, item := range items {
+		ordered[item.Position] = item
+	}
+	return ordered, nil
+}
+
+func (s *taskService) DeleteChecklistItem(ctx context.Context, id int64, itemID int64) (err error) {
+	if _, err = s.checklistItem(ctx, id, itemID); err != nil {
+		return
+	}
+
+	if err = s.checklistRepository.DeleteById(ctx, itemID); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		err = exception.ErrInternalServer
+	}
+	return
+}
+
+// checklistItem returns the item, exception.ErrNotFound is returned for an item of another task.
+func (s *taskService) checklistItem(ctx context.Context, id int64, itemID int64) (item entity.ChecklistItem, err error) {
+	item, err = s.checklistRepository.FindOneById(ctx, itemID)
+	if err == nil && item.TaskID != id {
+		err = exception.ErrNotFound
+	}
+	if err != nil && err != exception.ErrNotFound {
+		s.logger.WithContext(ctx).Error(err)
+		err = exception.ErrInternalServer
+	}
+	return
+}
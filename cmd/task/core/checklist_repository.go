@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// ChecklistRepository stores the checklist items of the tasks, the items of a task are ordered by position.
+type ChecklistRepository interface {
+	Save(ctx context.Context, item entity.ChecklistItem) (id int64, err error)
+	UpdateById(ctx context.Context, id int64, item entity.ChecklistItem) (err error)
+	DeleteById(ctx context.Context, id int64) (err error)
+	// UpdatePositions moves every item of ids to its index, at once.
+	UpdatePositions(ctx context.Context, taskID int64, ids []int64) (err error)
+	FindByTaskId(ctx context.Context, taskID int64) (items []entity.ChecklistItem, err error)
+	FindOneById(ctx context.Context, id int64) (item entity.ChecklistItem, err error)
+	// CountByTaskIds returns the progress of the tasks, a task without items is left out.
+	CountByTaskIds(ctx context.Context, taskIDs []int64) (progress map[int64]entity.TaskProgress, err error)
+}
+
+const checklistColumns = "c.id, c.task_id, c.name, c.done, c.position, c.created_at, c.updated_at"
+
+type checklistRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewChecklistRepository is a constructor
+func NewChecklistRepository(logger *logrus.Logger, db *database.Router, tableName string) ChecklistRepository {
+	return &checklistRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *checklistRepository) Save(ctx context.Context, item entity.ChecklistItem) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("task_id", "name", "done", "position", "created_at").
+		Values(item.TaskID, item.Name, item.Done, item.Position, item.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *checklistRepository) UpdateById(ctx context.Context, id int64, item entity.ChecklistItem) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("name", item.Name).
+		Set("done", item.Done).
+		Set("position", item.Position).
+		Set("updated_at", item.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *checklistRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *checklistRepository) UpdatePositions(ctx context.Context, taskID int64, ids []int64) (err error) {
+	tx, err := r.db.Writer(ctx).BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		return wrapError(err)
+	}
+
+	for position, id := range ids {
+		stmt, args, err := r.builder.Update(r.tableName).Set("position", position).Where(sq.Eq{"id": id, "task_id": taskID}).ToSql()
+		if err == nil {
+			_, err = tx.ExecContext(ctx, stmt, args...)
+		}
+		if err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			if err := tx.Rollback(); err != nil {
+				r.logger.WithContext(ctx).Error(err)
+			}
+			return wrapError(err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *checklistRepository) FindByTaskId(ctx context.Context, taskID int64) (items []entity.ChecklistItem, err error) {
+	return r.find(ctx, r.builder.Select(checklistColumns).From(r.tableName+" c").Where(sq.Eq{"c.task_id": taskID}).OrderBy("c.position", "c.id"))
+}
+
+func (r *checklistRepository) FindOneById(ctx context.Context, id int64) (item entity.ChecklistItem, err error) {
+	items, err := r.find(ctx, r.builder.Select(checklistColumns).From(r.tableName+" c").Where(sq.Eq{"c.id": id}))
+	if err != nil {
+		return
+	}
+	if len(items) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+	return items[0], nil
+}
+
+func (r *checklistRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (progress map[int64]entity.TaskProgress, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	progress = make(map[int64]entity.TaskProgress)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	stmt, args, err := r.builder.Select("c.task_id, SUM(CASE WHEN c.done THEN 1 ELSE 0 END), COUNT(*)").
+		From(r.tableName + " c").
+		Where(sq.Eq{"c.task_id": taskIDs}).
+		GroupBy("c.task_id").ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int64
+		var count entity.TaskProgress
+		if err = rows.Scan(&taskID, &count.Done, &count.Total); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		progress[taskID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *checklistRepository) find(ctx context.Context, builder sq.SelectBuilder) (items []entity.ChecklistItem, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	items = make([]entity.ChecklistItem, 0)
+	for rows.Next() {
+		var item entity.ChecklistItem
+		var updatedAt sql.NullTime
+		if err = rows.Scan(&item.ID, &item.TaskID, &item.Name, &item.Done, &item.Position, &item.CreatedAt, &updatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		if updatedAt.Valid {
+			item.UpdatedAt = &updatedAt.Time
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+)
+
+func TestChecklist(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	task, _ := service.CreateTask(ctx, core.TaskRequest{Name: "trip"})
+	other, _ := service.CreateTask(ctx, core.TaskRequest{Name: "other"})
+	var items []entity.ChecklistItem
+	for _, name := range []string{"passport", "tickets", "charger"} {
+		item, err := service.AddChecklistItem(ctx, task.ID, core.ChecklistItemRequest{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+	}
+	if _, err := service.AddChecklistItem(ctx, 42, core.ChecklistItemRequest{Name: "missing"}); err != exception.ErrNotFound {
+		t.Errorf("expected a missing task, got %v", err)
+	}
+
+	done := true
+	if toggled, err := service.UpdateChecklistItem(ctx, task.ID, items[1].ID, core.ChecklistItemRequest{Done: &done}); err != nil || !toggled.Done || toggled.Name != "tickets" {
+		t.Errorf("unexpected item %+v %v", toggled, err)
+	}
+	if _, err := service.UpdateChecklistItem(ctx, other.ID, items[1].ID, core.ChecklistItemRequest{Done: &done}); err != exception.ErrNotFound {
+		t.Errorf("expected the item of another task to be missing, got %v", err)
+	}
+	if task, _ = service.GetOneTask(ctx, task.ID); task.Progress != (entity.TaskProgress{Done: 1, Total: 3}) {
+		t.Errorf("unexpected progress %+v", task.Progress)
+	}
+
+	if _, err := service.ReorderChecklist(ctx, task.ID, []int64{items[2].ID, items[0].ID}); err != core.ErrInvalidChecklistOrder {
+		t.Errorf("expected an incomplete order, got %v", err)
+	}
+	if _, err := service.ReorderChecklist(ctx, task.ID, []int64{items[2].ID, items[0].ID, items[1].ID}); err != nil {
+		t.Fatal(err)
+	}
+	checklist, _ := service.GetChecklist(ctx, task.ID)
+	if len(checklist) != 3 || checklist[0].Name != "charger" || checklist[2].Name != "tickets" {
+		t.Errorf("unexpected order %+v", checklist)
+	}
+
+	if err := service.DeleteChecklistItem(ctx, task.ID, items[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ = service.GetOneTask(ctx, task.ID); task.Progress != (entity.TaskProgress{Done: 1, Total: 2}) {
+		t.Errorf("unexpected progress %+v", task.Progress)
+	}
+}
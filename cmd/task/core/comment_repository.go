@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// CommentRepository stores the comments of the tasks, the comments of a task are ordered by id, the oldest first.
+type CommentRepository interface {
+	Save(ctx context.Context, comment entity.Comment) (id int64, err error)
+	// UpdateById writes the body and the update date of the comment.
+	UpdateById(ctx context.Context, id int64, comment entity.Comment) (err error)
+	DeleteById(ctx context.Context, id int64) (err error)
+	FindOneById(ctx context.Context, id int64) (comment entity.Comment, err error)
+	// FindByTaskId returns at most limit comments of the task after the comment afterID, a zero limit returns every comment.
+	FindByTaskId(ctx context.Context, taskID int64, afterID int64, limit int) (comments []entity.Comment, err error)
+	// CountByTaskIds returns the number of comments of the tasks, a task without comments is left out.
+	CountByTaskIds(ctx context.Context, taskIDs []int64) (counts map[int64]int, err error)
+}
+
+const commentColumns = "c.id, c.task_id, c.author, c.body, c.created_at, c.updated_at"
+
+type commentRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewCommentRepository is a constructor
+func NewCommentRepository(logger *logrus.Logger, db *database.Router, tableName string) CommentRepository {
+	return &commentRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *commentRepository) Save(ctx context.Context, comment entity.Comment) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("task_id", "author", "body", "created_at").
+		Values(comment.TaskID, comment.Author, comment.Body, comment.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *commentRepository) UpdateById(ctx context.Context, id int64, comment entity.Comment) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("body", comment.Body).
+		Set("updated_at", comment.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *commentRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *commentRepository) FindOneById(ctx context.Context, id int64) (comment entity.Comment, err error) {
+	comments, err := r.find(ctx, r.builder.Select(commentColumns).From(r.tableName+" c").Where(sq.Eq{"c.id": id}))
+	if err != nil {
+		return
+	}
+	if len(comments) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+	return comments[0], nil
+}
+
+func (r *commentRepository) FindByTaskId(ctx context.Context, taskID int64, afterID int64, limit int) (comments []entity.Comment, err error) {
+	stmt := r.builder.Select(commentColumns).From(r.tableName + " c").Where(sq.Eq{"c.task_id": taskID}).OrderBy("c.id")
+	if afterID > 0 {
+		stmt = stmt.Where(sq.Gt{"c.id": afterID})
+	}
+	if limit > 0 {
+		stmt = stmt.Limit(uint64(limit))
+	}
+	return r.find(ctx, stmt)
+}
+
+func (r *commentRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (counts map[int64]int, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	counts = make(map[int64]int)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	stmt, args, err := r.builder.Select("c.task_id, COUNT(*)").
+		From(r.tableName + " c").
+		Where(sq.Eq{"c.task_id": taskIDs}).
+		GroupBy("c.task_id").ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int64
+		var count int
+		if err = rows.Scan(&taskID, &count); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		counts[taskID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *commentRepository) find(ctx context.Context, builder sq.SelectBuilder) (comments []entity.Comment, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	comments = make([]entity.Comment, 0)
+	for rows.Next() {
+		var comment entity.Comment
+		var updatedAt sql.NullTime
+		if err = rows.Scan(&comment.ID, &comment.TaskID, &comment.Author, &comment.Body, &comment.CreatedAt, &updatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		if updatedAt.Valid {
+			comment.UpdatedAt = &updatedAt.Time
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
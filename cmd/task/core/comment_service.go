@@ -0,0 +1,212 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrEmptyComment is returned for a comment without a body.
+var ErrEmptyComment = errors.New("comment must not be empty")
+
+// mentionPattern matches an @handle that doesn't follow a word, so an email address isn't a mention.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w][\w.-]*)`)
+
+// UserFinder looks the users up for the mentions, the assignees and the watchers, the user repository implements it.
+type UserFinder interface {
+	// FindManyUserByHandles returns the users whose name or the local part of whose email is one of the lower case handles.
+	FindManyUserByHandles(ctx context.Context, handles []string) (bunchOfUsers []entity.User, err error)
+	// FindOneUserByUUID returns exception.ErrNotFound for a user that doesn't exist.
+	FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error)
+}
+
+// CommentService manages the comments of the tasks. An @handle in a comment mentions the user whose name or
+// the local part of whose email is the handle, case insensitive, and the mentioned users are notified.
+// The errors are exception errors or ErrEmptyComment, exception.ErrForbidden is returned when a comment is changed by another author.
+type CommentService interface {
+	// GetComments returns a page of the comments of the task, the oldest first, and the number of its comments.
+	GetComments(ctx context.Context, taskID int64, page CommentPage) (comments []entity.Comment, total int, err error)
+	AddComment(ctx context.Context, taskID int64, commentRequest CommentRequest) (comment entity.Comment, err error)
+	// UpdateComment replaces the body of the comment, only the users that weren't mentioned yet are notified.
+	UpdateComment(ctx context.Context, taskID int64, id int64, commentRequest CommentRequest) (comment entity.Comment, err error)
+	DeleteComment(ctx context.Context, taskID int64, id int64, author string) (err error)
+}
+
+type commentService struct {
+	logger            *logrus.Logger
+	location          *time.Location
+	commentRepository CommentRepository
+	taskRepository    TaskRepository
+	users             UserFinder
+	notifier          notifier.Notifier
+}
+
+// NewCommentService is a constructor
+func NewCommentService(logger *logrus.Logger, location *time.Location, commentRepository CommentRepository, taskRepository TaskRepository, users UserFinder, notifier notifier.Notifier) CommentService {
+	return &commentService{
+		logger:            logger,
+		location:          location,
+		commentRepository: commentRepository,
+		taskRepository:    taskRepository,
+		users:             users,
+		notifier:          notifier,
+	}
+}
+
+func (s *commentService) GetComments(ctx context.Context, taskID int64, page CommentPage) (comments []entity.Comment, total int, err error) {
+	if _, err = s.taskRepository.FindOneById(ctx, taskID); err != nil {
+		return nil, 0, s.wrapError(ctx, err)
+	}
+	if comments, err = s.commentRepository.FindByTaskId(ctx, taskID, page.AfterID, page.Limit); err != nil {
+		return nil, 0, s.wrapError(ctx, err)
+	}
+
+	counts, err := s.commentRepository.CountByTaskIds(ctx, []int64{taskID})
+	if err != nil {
+		return nil, 0, s.wrapError(ctx, err)
+	}
+	return comments, counts[taskID], nil
+}
+
+func (s *commentService) AddComment(ctx context.Context, taskID int64, commentRequest CommentRequest) (comment entity.Comment, err error) {
+	if strings.TrimSpace(commentRequest.Body) == "" {
+		return comment, ErrEmptyComment
+	}
+	task, err := s.taskRepository.FindOneById(ctx, taskID)
+	if err != nil {
+		return comment, s.wrapError(ctx, err)
+	}
+
+	comment = entity.Comment{
+		TaskID:    taskID,
+		Author:    commentRequest.Author,
+		Body:      strings.TrimSpace(commentRequest.Body),
+		CreatedAt: time.Now().In(s.location),
+	}
+	if comment.ID, err = s.commentRepository.Save(ctx, comment); err != nil {
+		return comment, s.wrapError(ctx, err)
+	}
+
+	s.notifyMentions(ctx, task, comment, "")
+	return
+}
+
+func (s *commentService) UpdateComment(ctx context.Context, taskID int64, id int64, commentRequest CommentRequest) (comment entity.Comment, err error) {
+	if strings.TrimSpace(commentRequest.Body) == "" {
+		return comment, ErrEmptyComment
+	}
+	task, err := s.taskRepository.FindOneById(ctx, taskID)
+	if err != nil {
+		return comment, s.wrapError(ctx, err)
+	}
+	if comment, err = s.authored(ctx, taskID, id, commentRequest.Author); err != nil {
+		return
+	}
+
+	previous := comment.Body
+	updatedAt := time.Now().In(s.location)
+	comment.Body, comment.UpdatedAt = strings.TrimSpace(commentRequest.Body), &updatedAt
+	if err = s.commentRepository.UpdateById(ctx, id, comment); err != nil {
+		return comment, s.wrapError(ctx, err)
+	}
+
+	s.notifyMentions(ctx, task, comment, previous)
+	return
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, taskID int64, id int64, author string) (err error) {
+	if _, err = s.authored(ctx, taskID, id, author); err != nil {
+		return
+	}
+	if err = s.commentRepository.DeleteById(ctx, id); err != nil {
+		return s.wrapError(ctx, err)
+	}
+	return
+}
+
+// authored returns the comment of the task, exception.ErrForbidden is returned when the author didn't write it.
+func (s *commentService) authored(ctx context.Context, taskID int64, id int64, author string) (comment entity.Comment, err error) {
+	if comment, err = s.commentRepository.FindOneById(ctx, id); err != nil {
+		return comment, s.wrapError(ctx, err)
+	}
+	if comment.TaskID != taskID {
+		return comment, exception.ErrNotFound
+	}
+	if comment.Author != author {
+		return comment, exception.ErrForbidden
+	}
+	return
+}
+
+// notifyMentions notifies the users that the comment mentions and the previous body didn't, the author excluded.
+// A comment is written even when the mentions fail, the failures are logged.
+func (s *commentService) notifyMentions(ctx context.Context, task entity.Task, comment entity.Comment, previous string) {
+	handles := mentions(comment.Body)
+	if len(handles) == 0 {
+		return
+	}
+	notified := mentions(previous)
+
+	wanted := make([]string, 0, len(handles))
+	for handle := range handles {
+		wanted = append(wanted, handle)
+	}
+	sort.Strings(wanted)
+
+	users, err := s.users.FindManyUserByHandles(ctx, wanted)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return
+	}
+
+	for _, user := range users {
+		if user.UUID == comment.Author || !mentioned(user, handles) || mentioned(user, notified) {
+			continue
+		}
+
+		err := s.notifier.Notify(ctx, notifier.Notification{
+			Recipient: user.Email,
+			Subject:   "You were mentioned",
+			Message:   fmt.Sprintf("%s mentioned you on %s: %s", comment.Author, task.Name, comment.Body),
+			Data: map[string]string{
+				"task.id":    strconv.FormatInt(task.ID, 10),
+				"comment.id": strconv.FormatInt(comment.ID, 10),
+			},
+		})
+		if err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+	}
+}
+
+// mentions returns the lower case handles of the body, a trailing dot ends a sentence rather than a handle.
+func mentions(body string) (handles map[string]bool) {
+	handles = make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handles[strings.ToLower(strings.TrimRight(match[1], ".-"))] = true
+	}
+	return
+}
+
+func mentioned(user entity.User, handles map[string]bool) bool {
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	return handles[strings.ToLower(user.Name)] || handles[strings.ToLower(localPart)]
+}
+
+func (s *commentService) wrapError(ctx context.Context, err error) error {
+	if err == exception.ErrNotFound {
+		return err
+	}
+	s.logger.WithContext(ctx).Error(err)
+	return exception.ErrInternalServer
+}
@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fakeUsers []entity.User
+
+func (u fakeUsers) FindManyUserByHandles(ctx context.Context, handles []string) (bunchOfUsers []entity.User, err error) {
+	for _, user := range u {
+		localPart, _, _ := strings.Cut(user.Email, "@")
+		for _, handle := range handles {
+			if handle == strings.ToLower(user.Name) || handle == strings.ToLower(localPart) {
+				bunchOfUsers = append(bunchOfUsers, user)
+				break
+			}
+		}
+	}
+	return
+}
+
+func (u fakeUsers) FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error) {
+	for _, user := range u {
+		if user.UUID == uuid {
+			return user, nil
+		}
+	}
+	return user, exception.ErrNotFound
+}
+
+func TestComments(t *testing.T) {
+	ctx := context.Background()
+	repositories := newTestRepositories()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, core.TaskOptions{})
+	users := fakeUsers{
+		{UUID: "u-1", Name: "Jane", Email: "jane.doe@example.com"},
+		{UUID: "u-2", Name: "Bob", Email: "bob@example.com"},
+	}
+	notifications := &fakeNotifier{}
+	comments := core.NewCommentService(logrus.New(), time.UTC, repositories.Comments, repositories.Tasks, users, notifications)
+
+	task, _ := service.CreateTask(ctx, core.TaskRequest{Name: "release"})
+	first, err := comments.AddComment(ctx, task.ID, core.CommentRequest{Author: "u-1", Body: " @jane.doe and @BOB, please review. mail me at jane@example.com "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Body != "@jane.doe and @BOB, please review. mail me at jane@example.com" {
+		t.Errorf("expected a trimmed body, got %q", first.Body)
+	}
+	if len(notifications.notifications) != 1 || notifications.notifications[0].Recipient != "bob@example.com" {
+		t.Errorf("expected bob to be notified and not the author, got %+v", notifications.notifications)
+	}
+
+	// an edit notifies the new mentions only.
+	if _, err := comments.UpdateComment(ctx, task.ID, first.ID, core.CommentRequest{Author: "u-1", Body: "@bob @unknown"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications.notifications) != 1 {
+		t.Errorf("expected no new notification, got %+v", notifications.notifications)
+	}
+	if _, err := comments.UpdateComment(ctx, task.ID, first.ID, core.CommentRequest{Author: "u-2", Body: "hijacked"}); err != exception.ErrForbidden {
+		t.Errorf("expected another author to be forbidden, got %v", err)
+	}
+	if _, err := comments.AddComment(ctx, task.ID, core.CommentRequest{Author: "u-2", Body: "  "}); err != core.ErrEmptyComment {
+		t.Errorf("expected an empty comment, got %v", err)
+	}
+	if _, err := comments.AddComment(ctx, 42, core.CommentRequest{Author: "u-2", Body: "lost"}); err != exception.ErrNotFound {
+		t.Errorf("expected a missing task, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		comments.AddComment(ctx, task.ID, core.CommentRequest{Author: "u-2", Body: "ok"})
+	}
+	page, total, err := comments.GetComments(ctx, task.ID, core.CommentPage{AfterID: first.ID, Limit: 1})
+	if err != nil || total != 3 || len(page) != 1 || page[0].ID <= first.ID {
+		t.Errorf("unexpected page %+v of %d, %v", page, total, err)
+	}
+	if loaded, _ := service.GetOneTask(ctx, task.ID); loaded.CommentCount != 3 {
+		t.Errorf("expected the comment count, got %d", loaded.CommentCount)
+	}
+
+	if err := comments.DeleteComment(ctx, task.ID, first.ID, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, total, _ := comments.GetComments(ctx, task.ID, core.CommentPage{}); total != 2 {
+		t.Errorf("expected the comment to be deleted, got %d comments", total)
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+)
+
+const (
+	// dependencyLockKey serializes the cycle checks with the writes of the edges, a cycle may span any tasks.
+	dependencyLockKey  = "task:dependency"
+	dependencyLockTTL  = time.Second * 10
+	dependencyLockWait = time.Second * 5
+)
+
+// AddDependency marks the task as blocked by the blocker, an edge that closes a cycle is rejected.
+// The edges are checked and written one at a time, exception.ErrConflict is returned when the wait for the others is too long.
+func (s *taskService) AddDependency(ctx context.Context, id int64, blockerID int64) (dependency entity.Dependency, err error) {
+	if _, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+	if id == blockerID {
+		return dependency, fmt.Errorf("%w: a task can't block itself", ErrDependencyCycle)
+	}
+	if _, err = s.GetOneTask(ctx, blockerID); err == exception.ErrNotFound {
+		return dependency, fmt.Errorf("%w: the blocker doesn't exist", ErrInvalidDependency)
+	}
+	if err != nil {
+		return
+	}
+
+	release, err := s.lockDependencies(ctx)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	// the edge closes a cycle when the blocker is already blocked by the task, transitively, as the primary knows it.
+	upstream, _, err := s.walk(database.WithPrimary(ctx), blockerID, s.dependencyRepository.FindBlockers, func(edge entity.Dependency) int64 { return edge.BlockerID })
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return dependency, exception.ErrInternalServer
+	}
+	if containsID(upstream, id) {
+		return dependency, fmt.Errorf("%w: the blocker is blocked by the task", ErrDependencyCycle)
+	}
+
+	dependency = entity.Dependency{TaskID: id, BlockerID: blockerID, CreatedAt: time.Now().In(s.location)}
+	if err = s.dependencyRepository.Save(ctx, dependency); err != nil && err != exception.ErrConflict {
+		s.logger.WithContext(ctx).Error(err)
+		return dependency, exception.ErrInternalServer
+	}
+	return dependency, nil
+}
+
+// lockDependencies waits for the lock of the dependencies, release gives it back.
+func (s *taskService) lockDependencies(ctx context.Context) (release func(), err error) {
+	waitCtx, cancel := context.WithTimeout(ctx, dependencyLockWait)
+	defer cancel()
+
+	for {
+		token, acquired, err := s.options.Locker.Acquire(waitCtx, dependencyLockKey, dependencyLockTTL)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(err)
+			return nil, exception.ErrInternalServer
+		}
+		if acquired {
+			return func() {
+				if err := s.options.Locker.Release(context.Background(), dependencyLockKey, token); err != nil {
+					s.logger.WithContext(ctx).Error(err)
+				}
+			}, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return nil, exception.ErrConflict
+		case <-time.After(time.Millisecond * 20):
+		}
+	}
+}
+
+func (s *taskService) RemoveDependency(ctx context.Context, id int64, blockerID int64) (err error) {
+	if _, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	if err = s.dependencyRepository.Delete(ctx, id, blockerID); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		err = exception.ErrInternalServer
+	}
+	return
+}
+
+// GetGraph returns the blockers of the task and the tasks that it blocks, transitively.
+func (s *taskService) GetGraph(ctx context.Context, id int64) (graph Graph, err error) {
+	if _, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	if graph.Upstream, err = s.dependencyGraph(ctx, id, s.dependencyRepository.FindBlockers, func(edge entity.Dependency) int64 { return edge.BlockerID }); err != nil {
+		return
+	}
+	graph.Downstream, err = s.dependencyGraph(ctx, id, s.dependencyRepository.FindDependents, func(edge entity.Dependency) int64 { return edge.TaskID })
+	return
+}
+
+func (s *taskService) dependencyGraph(ctx context.Context, id int64, next func(context.Context, []int64) ([]entity.Dependency, error), far func(entity.Dependency) int64) (graph DependencyGraph, err error) {
+	ids, edges, err := s.walk(ctx, id, next, far)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return graph, exception.ErrInternalServer
+	}
+
+	graph.Tasks, graph.Edges = make([]entity.Task, 0), edges
+	if len(ids) == 0 {
+		return
+	}
+
+	// the graph shows the tasks of the archived projects too.
+	if graph.Tasks, err = s.taskRepository.FindMany(ctx, Filter{IDs: ids}); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return graph, exception.ErrInternalServer
+	}
+	err = s.load(ctx, graph.Tasks)
+	return
+}
+
+// walk follows the edges from the task breadth first, next returns the edges of the tasks and far the task at the other end of an edge.
+func (s *taskService) walk(ctx context.Context, id int64, next func(context.Context, []int64) ([]entity.Dependency, error), far func(entity.Dependency) int64) (ids []int64, edges []entity.Dependency, err error) {
+	seen := map[int64]bool{id: true}
+	edges = make([]entity.Dependency, 0)
+	for frontier := []int64{id}; len(frontier) > 0; {
+		found, err := next(ctx, frontier)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		frontier = nil
+		for _, edge := range found {
+			edges = append(edges, edge)
+			if other := far(edge); !seen[other] {
+				seen[other] = true
+				frontier = append(frontier, other)
+				ids = append(ids, other)
+			}
+		}
+	}
+	return
+}
+
+// checkBlockersDone returns ErrBlocked while a blocker of the task is open.
+func (s *taskService) checkBlockersDone(ctx context.Context, id int64) (err error) {
+	dependencies, err := s.dependencyRepository.FindBlockers(ctx, []int64{id})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	if len(dependencies) == 0 {
+		return
+	}
+
+	blockerIDs := make([]int64, len(dependencies))
+	for i, dependency := range dependencies {
+		blockerIDs[i] = dependency.BlockerID
+	}
+	open, err := s.taskRepository.FindMany(ctx, Filter{IDs: blockerIDs, Pending: true})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	if len(open) > 0 {
+		return ErrBlocked
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// DependencyRepository stores the "blocked by" edges between the tasks.
+type DependencyRepository interface {
+	// Save adds the edge, exception.ErrConflict is returned when it exists.
+	Save(ctx context.Context, dependency entity.Dependency) (err error)
+	Delete(ctx context.Context, taskID int64, blockerID int64) (err error)
+	// FindBlockers returns the edges of the tasks to their blockers.
+	FindBlockers(ctx context.Context, taskIDs []int64) (dependencies []entity.Dependency, err error)
+	// FindDependents returns the edges of the tasks that the blockers block.
+	FindDependents(ctx context.Context, blockerIDs []int64) (dependencies []entity.Dependency, err error)
+}
+
+type dependencyRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewDependencyRepository is a constructor
+func NewDependencyRepository(logger *logrus.Logger, db *database.Router, tableName string) DependencyRepository {
+	return &dependencyRepository{
+		logger:    logger,
+		db:        db,
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *dependencyRepository) Save(ctx context.Context, dependency entity.Dependency) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("task_id", "blocker_id", "created_at").
+		Values(dependency.TaskID, dependency.BlockerID, dependency.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		if !database.IsUniqueViolation(err) {
+			r.logger.WithContext(ctx).Error(stmt, err)
+		}
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *dependencyRepository) Delete(ctx context.Context, taskID int64, blockerID int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"task_id": taskID, "blocker_id": blockerID}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *dependencyRepository) FindBlockers(ctx context.Context, taskIDs []int64) (dependencies []entity.Dependency, err error) {
+	return r.find(ctx, "d.task_id", taskIDs)
+}
+
+func (r *dependencyRepository) FindDependents(ctx context.Context, blockerIDs []int64) (dependencies []entity.Dependency, err error) {
+	return r.find(ctx, "d.blocker_id", blockerIDs)
+}
+
+// find returns the edges whose column is one of the ids.
+func (r *dependencyRepository) find(ctx context.Context, column string, ids []int64) (dependencies []entity.Dependency, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	dependencies = make([]entity.Dependency, 0)
+	if len(ids) == 0 {
+		return
+	}
+
+	stmt, args, err := r.builder.Select("d.task_id, d.blocker_id, d.created_at").From(r.tableName+" d").Where(sq.Eq{column: ids}).OrderBy("d.task_id", "d.blocker_id").ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dependency entity.Dependency
+		if err = rows.Scan(&dependency.TaskID, &dependency.BlockerID, &dependency.CreatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
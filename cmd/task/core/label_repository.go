@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// LabelRepository stores the labels, a label name is unique.
+type LabelRepository interface {
+	Save(ctx context.Context, label entity.Label) (id int64, err error)
+	UpdateById(ctx context.Context, id int64, label entity.Label) (err error)
+	DeleteById(ctx context.Context, id int64) (err error)
+	FindMany(ctx context.Context) (labels []entity.Label, err error)
+	FindByIds(ctx context.Context, ids []int64) (labels []entity.Label, err error)
+	FindOneById(ctx context.Context, id int64) (label entity.Label, err error)
+}
+
+type labelRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewLabelRepository is a constructor
+func NewLabelRepository(logger *logrus.Logger, db *database.Router, tableName string) LabelRepository {
+	return &labelRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *labelRepository) Save(ctx context.Context, label entity.Label) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).Columns("name", "color", "created_at").Values(label.Name, label.Color, label.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		if !database.IsUniqueViolation(err) {
+			r.logger.WithContext(ctx).Error(stmt, err)
+		}
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *labelRepository) UpdateById(ctx context.Context, id int64, label entity.Label) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("name", label.Name).
+		Set("color", label.Color).
+		Set("updated_at", label.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		if !database.IsUniqueViolation(err) {
+			r.logger.WithContext(ctx).Error(stmt, err)
+		}
+		err = wrapError(err)
+	}
+	return
+}
+
+// DeleteById deletes the label, the tasks lose it by the foreign key.
+func (r *labelRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *labelRepository) FindMany(ctx context.Context) (labels []entity.Label, err error) {
+	return r.find(ctx, r.builder.Select("l.id, l.name, l.color, l.created_at, l.updated_at").From(r.tableName+" l").OrderBy("l.name"))
+}
+
+func (r *labelRepository) FindByIds(ctx context.Context, ids []int64) (labels []entity.Label, err error) {
+	if len(ids) == 0 {
+		return make([]entity.Label, 0), nil
+	}
+	return r.find(ctx, r.builder.Select("l.id, l.name, l.color, l.created_at, l.updated_at").From(r.tableName+" l").Where(sq.Eq{"l.id": ids}).OrderBy("l.name"))
+}
+
+func (r *labelRepository) FindOneById(ctx context.Context, id int64) (label entity.Label, err error) {
+	labels, err := r.find(ctx, r.builder.Select("l.id, l.name, l.color, l.created_at, l.updated_at").From(r.tableName+" l").Where(sq.Eq{"l.id": id}))
+	if err != nil {
+		return
+	}
+	if len(labels) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+	return labels[0], nil
+}
+
+func (r *labelRepository) find(ctx context.Context, builder sq.SelectBuilder) (labels []entity.Label, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	labels = make([]entity.Label, 0)
+	for rows.Next() {
+		var label entity.Label
+		var updatedAt sql.NullTime
+		if err = rows.Scan(&label.ID, &label.Name, &label.Color, &label.CreatedAt, &updatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		if updatedAt.Valid {
+			label.UpdatedAt = &updatedAt.Time
+		}
+		labels = append(labels, label)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
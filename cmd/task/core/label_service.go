@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LabelService manages the labels of the tasks.
+// The errors are exception errors, exception.ErrConflict is returned for a name that is taken.
+type LabelService interface {
+	GetManyLabels(ctx context.Context) (labels []entity.Label, err error)
+	GetOneLabel(ctx context.Context, id int64) (label entity.Label, err error)
+	CreateLabel(ctx context.Context, labelRequest LabelRequest) (label entity.Label, err error)
+	UpdateLabel(ctx context.Context, id int64, labelRequest LabelRequest) (label entity.Label, err error)
+	DeleteLabel(ctx context.Context, id int64) (err error)
+}
+
+type labelService struct {
+	logger          *logrus.Logger
+	location        *time.Location
+	labelRepository LabelRepository
+	taskRepository  TaskRepository
+}
+
+// NewLabelService is a constructor
+func NewLabelService(logger *logrus.Logger, location *time.Location, labelRepository LabelRepository, taskRepository TaskRepository) LabelService {
+	return &labelService{
+		logger:          logger,
+		location:        location,
+		labelRepository: labelRepository,
+		taskRepository:  taskRepository,
+	}
+}
+
+// GetManyLabels returns the labels sorted by name, an empty slice when there is none.
+func (s *labelService) GetManyLabels(ctx context.Context) (labels []entity.Label, err error) {
+	if labels, err = s.labelRepository.FindMany(ctx); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+	return
+}
+
+func (s *labelService) GetOneLabel(ctx context.Context, id int64) (label entity.Label, err error) {
+	if label, err = s.labelRepository.FindOneById(ctx, id); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *labelService) CreateLabel(ctx context.Context, labelRequest LabelRequest) (label entity.Label, err error) {
+	label = entity.Label{
+		Name:      strings.TrimSpace(labelRequest.Name),
+		Color:     strings.ToLower(labelRequest.Color),
+		CreatedAt: time.Now().In(s.location),
+	}
+	if label.ID, err = s.labelRepository.Save(ctx, label); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *labelService) UpdateLabel(ctx context.Context, id int64, labelRequest LabelRequest) (label entity.Label, err error) {
+	if label, err = s.GetOneLabel(ctx, id); err != nil {
+		return
+	}
+
+	updatedAt := time.Now().In(s.location)
+	label.Name = strings.TrimSpace(labelRequest.Name)
+	label.Color = strings.ToLower(labelRequest.Color)
+	label.UpdatedAt = &updatedAt
+	if err = s.labelRepository.UpdateById(ctx, id, label); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+// DeleteLabel removes the label from its tasks before it is deleted.
+func (s *labelService) DeleteLabel(ctx context.Context, id int64) (err error) {
+	if _, err = s.GetOneLabel(ctx, id); err != nil {
+		return
+	}
+
+	if err = s.taskRepository.RemoveLabel(ctx, id); err != nil {
+		return s.wrapError(ctx, err)
+	}
+	if err = s.labelRepository.DeleteById(ctx, id); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *labelService) wrapError(ctx context.Context, err error) error {
+	if err == exception.ErrNotFound || err == exception.ErrConflict {
+		return err
+	}
+	s.logger.WithContext(ctx).Error(err)
+	return exception.ErrInternalServer
+}
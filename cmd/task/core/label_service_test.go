@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLabelsAndPriorities(t *testing.T) {
+	ctx := context.Background()
+	repositories := newTestRepositories()
+	taskService := core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, core.TaskOptions{})
+	labelService := core.NewLabelService(logrus.New(), time.UTC, repositories.Labels, repositories.Tasks)
+
+	home, err := labelService.CreateLabel(ctx, core.LabelRequest{Name: "home", Color: "#00FF00"})
+	if err != nil || home.Color != "#00ff00" {
+		t.Fatalf("unexpected label %+v %v", home, err)
+	}
+	work, _ := labelService.CreateLabel(ctx, core.LabelRequest{Name: "work", Color: "#0000ff"})
+	if _, err := labelService.CreateLabel(ctx, core.LabelRequest{Name: "home", Color: "#ffffff"}); err != exception.ErrConflict {
+		t.Errorf("expected a taken name, got %v", err)
+	}
+
+	high, urgent := entity.TaskPriorityHigh, entity.TaskPriorityUrgent
+	created, err := taskService.CreateTask(ctx, core.TaskRequest{Name: "both", Priority: &high, LabelIDs: []int64{work.ID, home.ID, home.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Labels) != 2 || created.Labels[0].Name != "home" || created.Priority != high {
+		t.Errorf("unexpected task %+v", created)
+	}
+	taskService.CreateTask(ctx, core.TaskRequest{Name: "home", Priority: &urgent, LabelIDs: []int64{home.ID}})
+	taskService.CreateTask(ctx, core.TaskRequest{Name: "none"})
+	if _, err := taskService.CreateTask(ctx, core.TaskRequest{Name: "unknown", LabelIDs: []int64{42}}); err != core.ErrUnknownLabel {
+		t.Errorf("expected an unknown label, got %v", err)
+	}
+
+	names := func(filter core.Filter) (names []string) {
+		tasks, err := taskService.GetManyTasks(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		return
+	}
+	for _, test := range []struct {
+		filter core.Filter
+		want   []string
+	}{
+		{core.Filter{LabelIDs: []int64{home.ID, work.ID}}, []string{"both", "home"}},
+		{core.Filter{LabelIDs: []int64{home.ID, work.ID}, AllLabels: true}, []string{"both"}},
+		{core.Filter{Priorities: []int{entity.TaskPriorityLow, entity.TaskPriorityUrgent}}, []string{"home", "none"}},
+		{core.Filter{Sort: core.SortPriorityDesc}, []string{"home", "both", "none"}},
+		{core.Filter{Sort: core.SortPriority}, []string{"none", "both", "home"}},
+	} {
+		if got := names(test.filter); len(got) != len(test.want) || got[0] != test.want[0] || got[len(got)-1] != test.want[len(test.want)-1] {
+			t.Errorf("filter %+v: expected %v, got %v", test.filter, test.want, got)
+		}
+	}
+
+	if err := labelService.DeleteLabel(ctx, home.ID); err != nil {
+		t.Fatal(err)
+	}
+	task, _ := taskService.GetOneTask(ctx, created.ID)
+	if len(task.LabelIDs) != 1 || task.Labels[0].ID != work.ID {
+		t.Errorf("expected the deleted label to be removed, got %+v", task)
+	}
+	if err := labelService.DeleteLabel(ctx, home.ID); err != exception.ErrNotFound {
+		t.Errorf("expected a missing label, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"context"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryActivityRepository struct {
+	activities *memstore.Table[int64, entity.TaskActivity]
+}
+
+// NewMemoryActivityRepository is a constructor of the in-memory repository.
+func NewMemoryActivityRepository(activities *memstore.Table[int64, entity.TaskActivity]) ActivityRepository {
+	return &memoryActivityRepository{activities: activities}
+}
+
+func (r *memoryActivityRepository) Save(ctx context.Context, activity entity.TaskActivity, tx database.Tx) (id int64, err error) {
+	id = r.activities.NextID()
+	activity.ID = id
+	activity.Changes = append([]entity.FieldChange(nil), activity.Changes...)
+	err = r.activities.Insert(tx, id, activity)
+	return
+}
+
+func (r *memoryActivityRepository) FindByTaskId(ctx context.Context, taskID int64, beforeID int64, limit int) (activities []entity.TaskActivity, err error) {
+	found := r.activities.List(func(activity entity.TaskActivity) bool {
+		return activity.TaskID == taskID && (beforeID <= 0 || activity.ID < beforeID)
+	})
+
+	activities = make([]entity.TaskActivity, 0, len(found))
+	for i := len(found) - 1; i >= 0 && (limit <= 0 || len(activities) < limit); i-- {
+		activities = append(activities, found[i])
+	}
+	return
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryChecklistRepository struct {
+	items *memstore.Table[int64, entity.ChecklistItem]
+}
+
+// NewMemoryChecklistRepository is a constructor of the in-memory repository.
+func NewMemoryChecklistRepository(items *memstore.Table[int64, entity.ChecklistItem]) ChecklistRepository {
+	return &memoryChecklistRepository{items: items}
+}
+
+func (r *memoryChecklistRepository) Save(ctx context.Context, item entity.ChecklistItem) (id int64, err error) {
+	id = r.items.NextID()
+	item.ID = id
+	err = r.items.Insert(nil, id, item)
+	return
+}
+
+func (r *memoryChecklistRepository) UpdateById(ctx context.Context, id int64, item entity.ChecklistItem) (err error) {
+	existing, ok := r.items.Get(id)
+	if !ok {
+		return
+	}
+
+	existing.Name = item.Name
+	existing.Done = item.Done
+	existing.Position = item.Position
+	existing.UpdatedAt = copyTime(item.UpdatedAt)
+	if err = r.items.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryChecklistRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if err = r.items.Delete(nil, id); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryChecklistRepository) UpdatePositions(ctx context.Context, taskID int64, ids []int64) (err error) {
+	tx := memstore.Begin()
+	for position, id := range ids {
+		item, ok := r.items.Get(id)
+		if !ok || item.TaskID != taskID {
+			continue
+		}
+		item.Position = position
+		if err = r.items.Update(tx, id, item); err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *memoryChecklistRepository) FindByTaskId(ctx context.Context, taskID int64) (items []entity.ChecklistItem, err error) {
+	items = r.items.List(func(item entity.ChecklistItem) bool { return item.TaskID == taskID })
+	if items == nil {
+		items = make([]entity.ChecklistItem, 0)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].ID < items[j].ID
+	})
+	return
+}
+
+func (r *memoryChecklistRepository) FindOneById(ctx context.Context, id int64) (item entity.ChecklistItem, err error) {
+	item, ok := r.items.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (r *memoryChecklistRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (progress map[int64]entity.TaskProgress, err error) {
+	wanted := make(map[int64]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		wanted[id] = true
+	}
+
+	progress = make(map[int64]entity.TaskProgress)
+	for _, item := range r.items.List(func(item entity.ChecklistItem) bool { return wanted[item.TaskID] }) {
+		count := progress[item.TaskID]
+		count.Total++
+		if item.Done {
+			count.Done++
+		}
+		progress[item.TaskID] = count
+	}
+	return
+}
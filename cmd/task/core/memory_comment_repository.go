@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryCommentRepository struct {
+	comments *memstore.Table[int64, entity.Comment]
+}
+
+// NewMemoryCommentRepository is a constructor of the in-memory repository.
+func NewMemoryCommentRepository(comments *memstore.Table[int64, entity.Comment]) CommentRepository {
+	return &memoryCommentRepository{comments: comments}
+}
+
+func (r *memoryCommentRepository) Save(ctx context.Context, comment entity.Comment) (id int64, err error) {
+	id = r.comments.NextID()
+	comment.ID = id
+	err = r.comments.Insert(nil, id, comment)
+	return
+}
+
+func (r *memoryCommentRepository) UpdateById(ctx context.Context, id int64, comment entity.Comment) (err error) {
+	existing, ok := r.comments.Get(id)
+	if !ok {
+		return
+	}
+
+	existing.Body = comment.Body
+	existing.UpdatedAt = copyTime(comment.UpdatedAt)
+	if err = r.comments.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryCommentRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if err = r.comments.Delete(nil, id); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryCommentRepository) FindOneById(ctx context.Context, id int64) (comment entity.Comment, err error) {
+	comment, ok := r.comments.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (r *memoryCommentRepository) FindByTaskId(ctx context.Context, taskID int64, afterID int64, limit int) (comments []entity.Comment, err error) {
+	comments = r.comments.List(func(comment entity.Comment) bool { return comment.TaskID == taskID && comment.ID > afterID })
+	if comments == nil {
+		comments = make([]entity.Comment, 0)
+	}
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return
+}
+
+func (r *memoryCommentRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (counts map[int64]int, err error) {
+	wanted := make(map[int64]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		wanted[id] = true
+	}
+
+	counts = make(map[int64]int)
+	for _, comment := range r.comments.List(func(comment entity.Comment) bool { return wanted[comment.TaskID] }) {
+		counts[comment.TaskID]++
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+// DependencyKey is the key of an edge in the in-memory table.
+type DependencyKey struct {
+	TaskID    int64
+	BlockerID int64
+}
+
+type memoryDependencyRepository struct {
+	dependencies *memstore.Table[DependencyKey, entity.Dependency]
+}
+
+// NewMemoryDependencyRepository is a constructor of the in-memory repository.
+func NewMemoryDependencyRepository(dependencies *memstore.Table[DependencyKey, entity.Dependency]) DependencyRepository {
+	return &memoryDependencyRepository{dependencies: dependencies}
+}
+
+func (r *memoryDependencyRepository) Save(ctx context.Context, dependency entity.Dependency) (err error) {
+	key := DependencyKey{TaskID: dependency.TaskID, BlockerID: dependency.BlockerID}
+	if _, ok := r.dependencies.Get(key); ok {
+		return exception.ErrConflict
+	}
+	return r.dependencies.Insert(nil, key, dependency)
+}
+
+func (r *memoryDependencyRepository) Delete(ctx context.Context, taskID int64, blockerID int64) (err error) {
+	if err = r.dependencies.Delete(nil, DependencyKey{TaskID: taskID, BlockerID: blockerID}); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryDependencyRepository) FindBlockers(ctx context.Context, taskIDs []int64) (dependencies []entity.Dependency, err error) {
+	return sortDependencies(r.dependencies.List(func(dependency entity.Dependency) bool { return containsID(taskIDs, dependency.TaskID) })), nil
+}
+
+func (r *memoryDependencyRepository) FindDependents(ctx context.Context, blockerIDs []int64) (dependencies []entity.Dependency, err error) {
+	return sortDependencies(r.dependencies.List(func(dependency entity.Dependency) bool { return containsID(blockerIDs, dependency.BlockerID) })), nil
+}
+
+// sortDependencies sorts like the sql repository.
+func sortDependencies(dependencies []entity.Dependency) []entity.Dependency {
+	if dependencies == nil {
+		dependencies = make([]entity.Dependency, 0)
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].TaskID != dependencies[j].TaskID {
+			return dependencies[i].TaskID < dependencies[j].TaskID
+		}
+		return dependencies[i].BlockerID < dependencies[j].BlockerID
+	})
+	return dependencies
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryLabelRepository struct {
+	labels *memstore.Table[int64, entity.Label]
+}
+
+// NewMemoryLabelRepository is a constructor of the in-memory repository.
+func NewMemoryLabelRepository(labels *memstore.Table[int64, entity.Label]) LabelRepository {
+	return &memoryLabelRepository{labels: labels}
+}
+
+func (r *memoryLabelRepository) Save(ctx context.Context, label entity.Label) (id int64, err error) {
+	id = r.labels.NextID()
+	label.ID = id
+	err = r.labels.Insert(nil, id, label, func(existing entity.Label) bool {
+		return existing.Name == label.Name
+	})
+	return
+}
+
+func (r *memoryLabelRepository) UpdateById(ctx context.Context, id int64, label entity.Label) (err error) {
+	existing, ok := r.labels.Get(id)
+	if !ok {
+		return
+	}
+	if taken := r.labels.List(func(other entity.Label) bool { return other.ID != id && other.Name == label.Name }); len(taken) > 0 {
+		return exception.ErrConflict
+	}
+
+	existing.Name = label.Name
+	existing.Color = label.Color
+	existing.UpdatedAt = copyTime(label.UpdatedAt)
+	if err = r.labels.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryLabelRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if err = r.labels.Delete(nil, id); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryLabelRepository) FindMany(ctx context.Context) (labels []entity.Label, err error) {
+	return sortLabels(r.labels.List(nil)), nil
+}
+
+func (r *memoryLabelRepository) FindByIds(ctx context.Context, ids []int64) (labels []entity.Label, err error) {
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return sortLabels(r.labels.List(func(label entity.Label) bool { return wanted[label.ID] })), nil
+}
+
+func (r *memoryLabelRepository) FindOneById(ctx context.Context, id int64) (label entity.Label, err error) {
+	label, ok := r.labels.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+// sortLabels sorts by name like the sql repository.
+func sortLabels(labels []entity.Label) []entity.Label {
+	if labels == nil {
+		labels = make([]entity.Label, 0)
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
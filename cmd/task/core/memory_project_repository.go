@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryProjectRepository struct {
+	projects *memstore.Table[int64, entity.Project]
+}
+
+// NewMemoryProjectRepository is a constructor of the in-memory repository.
+func NewMemoryProjectRepository(projects *memstore.Table[int64, entity.Project]) ProjectRepository {
+	return &memoryProjectRepository{projects: projects}
+}
+
+func (r *memoryProjectRepository) Save(ctx context.Context, project entity.Project) (id int64, err error) {
+	id = r.projects.NextID()
+	project.ID = id
+	err = r.projects.Insert(nil, id, project, func(existing entity.Project) bool {
+		return existing.Name == project.Name
+	})
+	return
+}
+
+func (r *memoryProjectRepository) UpdateById(ctx context.Context, id int64, project entity.Project) (err error) {
+	existing, ok := r.projects.Get(id)
+	if !ok {
+		return
+	}
+	if taken := r.projects.List(func(other entity.Project) bool { return other.ID != id && other.Name == project.Name }); len(taken) > 0 {
+		return exception.ErrConflict
+	}
+
+	existing.Name = project.Name
+	existing.Description = project.Description
+	existing.ArchivedAt = copyTime(project.ArchivedAt)
+	existing.UpdatedAt = copyTime(project.UpdatedAt)
+	if err = r.projects.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryProjectRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if err = r.projects.Delete(nil, id); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+// FindMany sorts by name like the sql repository.
+func (r *memoryProjectRepository) FindMany(ctx context.Context, filter ProjectFilter) (projects []entity.Project, err error) {
+	projects = r.projects.List(func(project entity.Project) bool {
+		return filter.Archived == nil || *filter.Archived == (project.ArchivedAt != nil)
+	})
+	if projects == nil {
+		projects = make([]entity.Project, 0)
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return
+}
+
+func (r *memoryProjectRepository) FindOneById(ctx context.Context, id int64) (project entity.Project, err error) {
+	project, ok := r.projects.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
@@ -0,0 +1,401 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryTaskRepository struct {
+	tasks *memstore.Table[int64, entity.Task]
+}
+
+// NewMemoryTaskRepository is a constructor of the in-memory repository.
+func NewMemoryTaskRepository(tasks *memstore.Table[int64, entity.Task]) TaskRepository {
+	return &memoryTaskRepository{tasks: tasks}
+}
+
+// BeginTx returns a fake transaction.
+func (r *memoryTaskRepository) BeginTx(ctx context.Context) (tx database.Tx, err error) {
+	return memstore.Begin(), nil
+}
+
+// CommitTx will commit the transaction that has began.
+func (r *memoryTaskRepository) CommitTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Commit()
+}
+
+// RollbackTx will undo the writes of the transaction.
+func (r *memoryTaskRepository) RollbackTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r *memoryTaskRepository) FindMany(ctx context.Context, filter Filter) (bunchOfTasks []entity.Task, err error) {
+	bunchOfTasks = r.tasks.List(func(task entity.Task) bool {
+		return taskMatches(task, filter) && (filter.After == nil || isAfter(task, *filter.After))
+	})
+
+	switch filter.Sort {
+	case SortPriority:
+		sort.SliceStable(bunchOfTasks, func(i, j int) bool { return bunchOfTasks[i].Priority < bunchOfTasks[j].Priority })
+	case SortPriorityDesc:
+		sort.SliceStable(bunchOfTasks, func(i, j int) bool { return bunchOfTasks[i].Priority > bunchOfTasks[j].Priority })
+	case SortManual:
+		sort.SliceStable(bunchOfTasks, func(i, j int) bool { return bunchOfTasks[i].Rank < bunchOfTasks[j].Rank })
+	}
+	if filter.Limit > 0 && len(bunchOfTasks) > filter.Limit {
+		bunchOfTasks = bunchOfTasks[:filter.Limit]
+	}
+	return
+}
+
+func (r *memoryTaskRepository) Count(ctx context.Context, filter Filter) (count int, err error) {
+	return len(r.tasks.List(func(task entity.Task) bool { return taskMatches(task, filter) })), nil
+}
+
+// taskMatches reports whether the task is one of the tasks of the filter, the paging is left to the caller.
+func taskMatches(task entity.Task, filter Filter) bool {
+	if filter.Name != nil && task.Name != *filter.Name {
+		return false
+	}
+	if filter.Attachment != nil && (task.Attachment == nil || *task.Attachment != *filter.Attachment) {
+		return false
+	}
+	if (filter.DueFrom != nil || filter.DueUntil != nil) && task.DueAt == nil {
+		return false
+	}
+	if filter.DueFrom != nil && task.DueAt.Before(*filter.DueFrom) {
+		return false
+	}
+	if filter.DueUntil != nil && !task.DueAt.Before(*filter.DueUntil) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !containsInt(filter.Statuses, task.Status) {
+		return false
+	}
+	if filter.Pending && task.Status == entity.TaskStatusDone {
+		return false
+	}
+	if filter.IDs != nil && !containsID(filter.IDs, task.ID) {
+		return false
+	}
+	if filter.SeriesID != nil && (task.SeriesID == nil || *task.SeriesID != *filter.SeriesID) {
+		return false
+	}
+	if filter.ParentID != nil && (task.ParentID == nil || *task.ParentID != *filter.ParentID) {
+		return false
+	}
+	if filter.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *filter.ProjectID) {
+		return false
+	}
+	if task.ProjectID != nil && containsID(filter.ExcludedProjectIDs, *task.ProjectID) {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !containsInt(filter.Priorities, task.Priority) {
+		return false
+	}
+	if len(filter.LabelIDs) > 0 && !hasLabels(task.LabelIDs, filter.LabelIDs, filter.AllLabels) {
+		return false
+	}
+	if filter.Assignee != nil && !containsString(task.Assignees, *filter.Assignee) {
+		return false
+	}
+	if filter.Unassigned && len(task.Assignees) > 0 {
+		return false
+	}
+	return true
+}
+
+// isAfter reports whether the task comes after the position in the manual order.
+func isAfter(task entity.Task, position Position) bool {
+	return task.Rank > position.Rank || task.Rank == position.Rank && task.ID > position.ID
+}
+
+func (r *memoryTaskRepository) FindOneById(ctx context.Context, id int64) (task entity.Task, err error) {
+	task, ok := r.tasks.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+// FindAttachments returns every attachment that is linked to a task.
+func (r *memoryTaskRepository) FindAttachments(ctx context.Context) (attachments []string, err error) {
+	seen := make(map[string]bool)
+	attachments = make([]string, 0)
+	for _, task := range r.tasks.List(nil) {
+		if task.Attachment != nil && !seen[*task.Attachment] {
+			seen[*task.Attachment] = true
+			attachments = append(attachments, *task.Attachment)
+		}
+	}
+	return
+}
+
+func (r *memoryTaskRepository) FindDueReminders(ctx context.Context, until time.Time, limit int) (bunchOfTasks []entity.Task, err error) {
+	bunchOfTasks = r.tasks.List(func(task entity.Task) bool {
+		return task.RemindAt != nil && !task.RemindAt.After(until) && task.RemindedAt == nil && task.Status != entity.TaskStatusDone
+	})
+	sort.SliceStable(bunchOfTasks, func(i, j int) bool {
+		return bunchOfTasks[i].RemindAt.Before(*bunchOfTasks[j].RemindAt)
+	})
+	if len(bunchOfTasks) > limit {
+		bunchOfTasks = bunchOfTasks[:limit]
+	}
+	return
+}
+
+func (r *memoryTaskRepository) MarkReminded(ctx context.Context, id int64, remindedAt time.Time) (err error) {
+	existing, ok := r.tasks.Get(id)
+	if !ok || existing.RemindedAt != nil {
+		return
+	}
+
+	existing.RemindedAt = &remindedAt
+	if err = r.tasks.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryTaskRepository) RemoveLabel(ctx context.Context, labelID int64) (err error) {
+	for _, task := range r.tasks.List(func(task entity.Task) bool { return hasLabels(task.LabelIDs, []int64{labelID}, false) }) {
+		labelIDs := make([]int64, 0, len(task.LabelIDs))
+		for _, id := range task.LabelIDs {
+			if id != labelID {
+				labelIDs = append(labelIDs, id)
+			}
+		}
+		task.LabelIDs = labelIDs
+		if err = r.tasks.Update(nil, task.ID, task); err != nil && err != exception.ErrNotFound {
+			return
+		}
+	}
+	return nil
+}
+
+func (r *memoryTaskRepository) RemoveProject(ctx context.Context, projectID int64) (err error) {
+	for _, task := range r.tasks.List(func(task entity.Task) bool { return task.ProjectID != nil && *task.ProjectID == projectID }) {
+		task.ProjectID = nil
+		if err = r.tasks.Update(nil, task.ID, task); err != nil && err != exception.ErrNotFound {
+			return
+		}
+	}
+	return nil
+}
+
+func (r *memoryTaskRepository) CountByProjectIds(ctx context.Context, projectIDs []int64) (counts map[int64]entity.StatusCounts, err error) {
+	counts = make(map[int64]entity.StatusCounts)
+	for _, task := range r.tasks.List(func(task entity.Task) bool { return task.ProjectID != nil && containsID(projectIDs, *task.ProjectID) }) {
+		projectCounts := counts[*task.ProjectID]
+		projectCounts.Add(task.Status, 1)
+		counts[*task.ProjectID] = projectCounts
+	}
+	return
+}
+
+func (r *memoryTaskRepository) FindLastRank(ctx context.Context) (rank string, err error) {
+	for _, task := range r.tasks.List(nil) {
+		if task.Rank > rank {
+			rank = task.Rank
+		}
+	}
+	return
+}
+
+func (r *memoryTaskRepository) FindAdjacentRank(ctx context.Context, rank string, after bool) (adjacent string, err error) {
+	for _, task := range r.tasks.List(nil) {
+		if after && task.Rank > rank && (adjacent == "" || task.Rank < adjacent) {
+			adjacent = task.Rank
+		}
+		if !after && task.Rank < rank && task.Rank > adjacent {
+			adjacent = task.Rank
+		}
+	}
+	return
+}
+
+func (r *memoryTaskRepository) UpdateRank(ctx context.Context, id int64, rank string, tx database.Tx) (err error) {
+	existing, ok := r.tasks.Get(id)
+	if !ok {
+		return
+	}
+
+	existing.Rank = rank
+	if err = r.tasks.Update(tx, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+// Save mirrors the sql repository, the attachment is only set by an update.
+func (r *memoryTaskRepository) Save(ctx context.Context, task TaskRequest, tx database.Tx) (id int64, err error) {
+	id = r.tasks.NextID()
+	err = r.tasks.Insert(tx, id, entity.Task{
+		ID:          id,
+		Name:        task.Name,
+		Description: stringValue(task.Description),
+		Status:      intValue(task.Status),
+		Priority:    intValue(task.Priority),
+		DueAt:       copyTime(task.DueAt),
+		RemindAt:    copyTime(task.RemindAt),
+		Recurrence:  copyString(task.Recurrence),
+		SeriesID:    copyInt64(task.SeriesID),
+		SeriesStart: copyTime(task.SeriesStart),
+		Occurrence:  task.Occurrence,
+		LabelIDs:    uniqueIDs(task.LabelIDs),
+		Assignees:   uniqueStrings(task.Assignees),
+		Watchers:    uniqueStrings(task.Watchers),
+		ParentID:    copyInt64(task.ParentID),
+		ProjectID:   copyInt64(task.ProjectID),
+		Rank:        task.Rank,
+		CreatedAt:   task.CreatedAt,
+	})
+	return
+}
+
+// UpdateById mirrors the sql repository, updating a missing task is not an error.
+func (r *memoryTaskRepository) UpdateById(ctx context.Context, id int64, task TaskRequest, tx database.Tx) (err error) {
+	existing, ok := r.tasks.Get(id)
+	if !ok {
+		return
+	}
+
+	existing.Name = task.Name
+	existing.Description = stringValue(task.Description)
+	existing.Status = intValue(task.Status)
+	existing.Priority = intValue(task.Priority)
+	existing.Attachment = task.Attachment
+	existing.DueAt = copyTime(task.DueAt)
+	existing.RemindAt = copyTime(task.RemindAt)
+	existing.RemindedAt = copyTime(task.RemindedAt)
+	existing.Recurrence = copyString(task.Recurrence)
+	existing.SeriesID = copyInt64(task.SeriesID)
+	existing.SeriesStart = copyTime(task.SeriesStart)
+	existing.Occurrence = task.Occurrence
+	existing.LabelIDs = uniqueIDs(task.LabelIDs)
+	existing.Assignees = uniqueStrings(task.Assignees)
+	existing.Watchers = uniqueStrings(task.Watchers)
+	existing.ParentID = copyInt64(task.ParentID)
+	existing.ProjectID = copyInt64(task.ProjectID)
+	existing.UpdatedAt = copyTime(task.UpdatedAt)
+
+	if err = r.tasks.Update(tx, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func copyTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func copyString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func copyInt64(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// uniqueIDs returns the sorted ids without the duplicates, never nil.
+func uniqueIDs(ids []int64) []int64 {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
+
+// uniqueStrings returns the sorted values without the duplicates, never nil.
+func uniqueStrings(values []string) []string {
+	unique := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(values []int64, value int64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLabels reports whether the labels hold any of the wanted labels, or all of them.
+func hasLabels(labelIDs []int64, wanted []int64, all bool) bool {
+	held := make(map[int64]bool, len(labelIDs))
+	for _, id := range labelIDs {
+		held[id] = true
+	}
+	for _, id := range wanted {
+		if held[id] && !all {
+			return true
+		}
+		if !held[id] && all {
+			return false
+		}
+	}
+	return all
+}
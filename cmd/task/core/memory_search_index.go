@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"todo-app-api/entity"
+)
+
+// InvertedIndex is the SearchIndex of the memory driver. It is held in the process, so it only sees the writes of
+// its own replica, and scores the tasks by tf-idf, a word of the name weighs twice a word of the description.
+type InvertedIndex struct {
+	mu sync.RWMutex
+	// postings are the weights of the tasks by word, words are the words of each task.
+	postings map[string]map[int64]float64
+	words    map[int64][]string
+}
+
+// NewInvertedIndex is a constructor of an empty index.
+func NewInvertedIndex() *InvertedIndex {
+	return &InvertedIndex{
+		postings: make(map[string]map[int64]float64),
+		words:    make(map[int64][]string),
+	}
+}
+
+func (i *InvertedIndex) Index(ctx context.Context, task entity.Task) (err error) {
+	weights := make(map[string]float64)
+	for _, word := range searchTerms(task.Name) {
+		weights[word] += 2
+	}
+	for _, word := range searchTerms(task.Description) {
+		weights[word]++
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, word := range i.words[task.ID] {
+		delete(i.postings[word], task.ID)
+		if len(i.postings[word]) == 0 {
+			delete(i.postings, word)
+		}
+	}
+
+	words := make([]string, 0, len(weights))
+	for word, weight := range weights {
+		if i.postings[word] == nil {
+			i.postings[word] = make(map[int64]float64)
+		}
+		i.postings[word][task.ID] = weight
+		words = append(words, word)
+	}
+	i.words[task.ID] = words
+	return
+}
+
+func (i *InvertedIndex) Search(ctx context.Context, query string, limit int) (hits []SearchHit, err error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	scores := make(map[int64]float64)
+	seen := make(map[string]bool)
+	for _, word := range searchTerms(query) {
+		if seen[word] || len(i.postings[word]) == 0 {
+			continue
+		}
+		seen[word] = true
+
+		idf := math.Log(1 + float64(len(i.words))/float64(len(i.postings[word])))
+		for id, weight := range i.postings[word] {
+			scores[id] += weight * idf
+		}
+	}
+
+	hits = make([]SearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, SearchHit{TaskID: id, Score: score})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].TaskID < hits[b].TaskID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryViewRepository struct {
+	views *memstore.Table[int64, entity.View]
+}
+
+// NewMemoryViewRepository is a constructor of the in-memory repository.
+func NewMemoryViewRepository(views *memstore.Table[int64, entity.View]) ViewRepository {
+	return &memoryViewRepository{views: views}
+}
+
+func (r *memoryViewRepository) Save(ctx context.Context, view entity.View) (id int64, err error) {
+	id = r.views.NextID()
+	view.ID = id
+	err = r.views.Insert(nil, id, view)
+	return
+}
+
+func (r *memoryViewRepository) UpdateById(ctx context.Context, id int64, view entity.View) (err error) {
+	existing, ok := r.views.Get(id)
+	if !ok {
+		return
+	}
+
+	existing.Name = view.Name
+	existing.Query = view.Query
+	existing.Sort = view.Sort
+	existing.Shared = view.Shared
+	existing.UpdatedAt = copyTime(view.UpdatedAt)
+	if err = r.views.Update(nil, id, existing); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryViewRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if err = r.views.Delete(nil, id); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryViewRepository) FindOneById(ctx context.Context, id int64) (view entity.View, err error) {
+	view, ok := r.views.Get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+// FindVisible sorts by name then id like the sql repository.
+func (r *memoryViewRepository) FindVisible(ctx context.Context, owner string) (views []entity.View, err error) {
+	views = r.views.List(func(view entity.View) bool { return view.Owner == owner || view.Shared })
+	if views == nil {
+		views = make([]entity.View, 0)
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].ID < views[j].ID
+	})
+	return
+}
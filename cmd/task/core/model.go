@@ -0,0 +1,210 @@
+package core
+
+import (
+	"time"
+	"todo-app-api/entity"
+)
+
+// Filter narrows down the tasks of FindMany, a nil field matches every task.
+type Filter struct {
+	// IDs matches the tasks with the ids, a non nil empty slice matches no task.
+	IDs  []int64
+	Name *string
+	// Query is a full-text search of the name and the description, the service resolves it into IDs.
+	Query      string
+	Attachment *string
+	// Overdue, DueToday and DueBefore are resolved by the service into DueFrom, DueUntil and Pending,
+	// the repositories only read the latter.
+	Overdue   bool
+	DueToday  bool
+	DueBefore *time.Time
+	// DuePeriod matches the tasks due within a relative period and DueBeforePeriod the tasks due before the period
+	// starts, see Periods. They are resolved too, in the location of the service.
+	DuePeriod       string
+	DueBeforePeriod string
+	// DueFrom is inclusive and DueUntil is exclusive, a bounded filter leaves out the tasks without a due date.
+	DueFrom  *time.Time
+	DueUntil *time.Time
+	// Pending leaves out the done tasks.
+	Pending  bool
+	SeriesID *int64
+	ParentID *int64
+	// ProjectID matches the tasks of the project, ExcludedProjectIDs leaves out the tasks of the projects.
+	ProjectID          *int64
+	ExcludedProjectIDs []int64
+	// Priorities matches any of the priorities.
+	Priorities []int
+	// LabelIDs matches the tasks with any of the labels, or with all of them when AllLabels is set.
+	LabelIDs  []int64
+	AllLabels bool
+	// Assignee matches the tasks assigned to the user, Unassigned the tasks without assignees.
+	Assignee   *string
+	Unassigned bool
+	// Statuses matches any of the statuses.
+	Statuses []int
+	Sort     string
+	// After and Limit page through the manual order, After is the position of the last task of the
+	// previous page and a zero Limit returns every task. Count ignores both.
+	After *Position
+	Limit int
+}
+
+// Position is the place of a task in the manual order, the id breaks the ties of the ranks.
+type Position struct {
+	Rank string
+	ID   int64
+}
+
+// The relative periods of a filter, a week starts on monday.
+const (
+	PeriodToday     = "today"
+	PeriodTomorrow  = "tomorrow"
+	PeriodThisWeek  = "this_week"
+	PeriodNextWeek  = "next_week"
+	PeriodThisMonth = "this_month"
+	PeriodNextMonth = "next_month"
+)
+
+// Periods are the relative periods of a filter.
+var Periods = []string{PeriodToday, PeriodTomorrow, PeriodThisWeek, PeriodNextWeek, PeriodThisMonth, PeriodNextMonth}
+
+// The sorts of FindMany, the tasks are sorted by id otherwise.
+const (
+	SortPriority     = "priority"
+	SortPriorityDesc = "-priority"
+	SortManual       = "manual"
+)
+
+// TaskRequest is the write model of a task, the versioned apis map their payloads onto it.
+type TaskRequest struct {
+	Name        string
+	Description *string
+	Status      *int
+	Priority    *int
+	Attachment  *string
+	DueAt       *time.Time
+	RemindAt    *time.Time
+	// RemindedAt is kept by the service, it is reset when the reminder moves.
+	RemindedAt *time.Time
+	// Recurrence is an RRULE, the series fields are kept by the service.
+	Recurrence  *string
+	SeriesID    *int64
+	SeriesStart *time.Time
+	Occurrence  int
+	LabelIDs    []int64
+	// Assignees and Watchers are kept by the service, Assign and Watch change them.
+	Assignees []string
+	Watchers  []string
+	ParentID  *int64
+	ProjectID *int64
+	// Rank is set by the service on a new task, an update keeps the rank, MoveTask and MoveOnBoard change it.
+	Rank string
+	// Force completes a task whose subtasks are open, it isn't stored.
+	Force     bool
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// LabelRequest is the write model of a label, the color is a hex color like #ff0000.
+type LabelRequest struct {
+	Name  string
+	Color string
+}
+
+// MoveRequest places a task between its new neighbours in the manual order, a missing neighbour
+// is looked up next to the other one.
+type MoveRequest struct {
+	// AfterID is the task that comes right before the moved task, BeforeID the one that comes right after it.
+	AfterID  *int64
+	BeforeID *int64
+}
+
+// ProjectRequest is the write model of a project.
+type ProjectRequest struct {
+	Name        string
+	Description string
+}
+
+// ProjectFilter narrows down the projects of FindMany, a nil Archived matches every project.
+type ProjectFilter struct {
+	Archived *bool
+}
+
+// ChecklistItemRequest is the write model of a checklist item, a nil Done keeps the item as it is.
+type ChecklistItemRequest struct {
+	Name string
+	Done *bool
+}
+
+// Graph is the dependency graph around a task, Upstream holds its blockers and Downstream the tasks that it blocks.
+type Graph struct {
+	Upstream   DependencyGraph
+	Downstream DependencyGraph
+}
+
+// DependencyGraph is a set of tasks and their edges, an edge points from a task to its blocker.
+type DependencyGraph struct {
+	Tasks []entity.Task
+	Edges []entity.Dependency
+}
+
+// BoardRequest reads the board, every column is paged on its own.
+type BoardRequest struct {
+	// Filter narrows the tasks of every column down, the board sets its statuses, sort and paging.
+	Filter Filter
+	// Statuses are the columns to read, every column of BoardStatuses when it is empty.
+	Statuses []int
+	// After is the position of the last task of the previous page of the columns, Limit is the size of a page.
+	// A zero Limit returns every task.
+	After *Position
+	Limit int
+}
+
+// Board is the tasks by column.
+type Board struct {
+	Columns []BoardColumn
+}
+
+// BoardColumn is a page of the tasks of a status in the manual order.
+type BoardColumn struct {
+	Status int
+	Tasks  []entity.Task
+	// Count is the number of tasks of the column, every page included.
+	Count int
+	// Next is the position to read the next page after, nil on the last page.
+	Next *Position
+}
+
+// BoardMoveRequest moves a task to the column of the status, between its neighbours in the column.
+// A move without neighbours keeps the rank of the task.
+type BoardMoveRequest struct {
+	Status int
+	MoveRequest
+}
+
+// CommentRequest is the write model of a comment, Author is the subject of the principal that writes it.
+type CommentRequest struct {
+	Author string
+	Body   string
+}
+
+// CommentPage reads the comments after the comment AfterID, Limit comments at most.
+type CommentPage struct {
+	AfterID int64
+	Limit   int
+}
+
+// ActivityPage reads the activities before the activity BeforeID, the newest first, Limit activities at most.
+type ActivityPage struct {
+	BeforeID int64
+	Limit    int
+}
+
+// ViewRequest is the write model of a view, Owner is the subject of the principal that saves it.
+type ViewRequest struct {
+	Owner  string
+	Name   string
+	Query  string
+	Sort   string
+	Shared bool
+}
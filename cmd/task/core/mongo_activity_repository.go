@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type activityDocument struct {
+	ID            int64                 `bson:"_id"`
+	TaskID        int64                 `bson:"task_id"`
+	Action        string                `bson:"action"`
+	Actor         string                `bson:"actor"`
+	RemoteAddress string                `bson:"remote_address"`
+	XForwardedFor string                `bson:"x_forwarded_for"`
+	XRealIP       string                `bson:"x_real_ip"`
+	UserAgent     string                `bson:"user_agent"`
+	Changes       []fieldChangeDocument `bson:"changes"`
+	CreatedAt     time.Time             `bson:"created_at"`
+}
+
+// fieldChangeDocument keeps the JSON values of a change as strings, so they read back unchanged.
+type fieldChangeDocument struct {
+	Field string `bson:"field"`
+	From  string `bson:"from"`
+	To    string `bson:"to"`
+}
+
+type mongoActivityRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoActivityRepository is a constructor
+func NewMongoActivityRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) ActivityRepository {
+	return &mongoActivityRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoActivityIndexes creates the indexes of the activity collection.
+func CreateMongoActivityIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("idx_task_activity_task_id"),
+	})
+	return
+}
+
+func (r *mongoActivityRepository) Save(ctx context.Context, activity entity.TaskActivity, tx database.Tx) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err == nil {
+		changes := make([]fieldChangeDocument, 0, len(activity.Changes))
+		for _, change := range activity.Changes {
+			changes = append(changes, fieldChangeDocument{Field: change.Field, From: string(change.From), To: string(change.To)})
+		}
+
+		device := activity.ClientDevice
+		_, err = r.collection().InsertOne(sessionContext(ctx, tx), activityDocument{
+			ID:            id,
+			TaskID:        activity.TaskID,
+			Action:        activity.Action,
+			Actor:         activity.Actor,
+			RemoteAddress: device.RemoteAddress,
+			XForwardedFor: device.XForwardedFor,
+			XRealIP:       device.XRealIP,
+			UserAgent:     device.UserAgent,
+			Changes:       changes,
+			CreatedAt:     activity.CreatedAt,
+		})
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoActivityRepository) FindByTaskId(ctx context.Context, taskID int64, beforeID int64, limit int) (activities []entity.TaskActivity, err error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	query := bson.M{"task_id": taskID}
+	if beforeID > 0 {
+		query["_id"] = bson.M{"$lt": beforeID}
+	}
+
+	cursor, err := r.collection().Find(ctx, query, findOptions)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []activityDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	activities = make([]entity.TaskActivity, 0, len(documents))
+	for _, document := range documents {
+		activities = append(activities, document.entity())
+	}
+	return
+}
+
+func (r *mongoActivityRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d activityDocument) entity() entity.TaskActivity {
+	activity := entity.TaskActivity{
+		ID:     d.ID,
+		TaskID: d.TaskID,
+		Action: d.Action,
+		Actor:  d.Actor,
+		ClientDevice: entity.ClientDevice{
+			RemoteAddress: d.RemoteAddress,
+			XForwardedFor: d.XForwardedFor,
+			XRealIP:       d.XRealIP,
+			UserAgent:     d.UserAgent,
+		},
+		Changes:   make([]entity.FieldChange, 0, len(d.Changes)),
+		CreatedAt: d.CreatedAt,
+	}
+	for _, change := range d.Changes {
+		activity.Changes = append(activity.Changes, entity.FieldChange{Field: change.Field, From: []byte(change.From), To: []byte(change.To)})
+	}
+	return activity
+}
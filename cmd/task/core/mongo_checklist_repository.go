@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type checklistItemDocument struct {
+	ID        int64      `bson:"_id"`
+	TaskID    int64      `bson:"task_id"`
+	Name      string     `bson:"name"`
+	Done      bool       `bson:"done"`
+	Position  int        `bson:"position"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt *time.Time `bson:"updated_at"`
+}
+
+type mongoChecklistRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoChecklistRepository is a constructor
+func NewMongoChecklistRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) ChecklistRepository {
+	return &mongoChecklistRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoChecklistIndexes creates the indexes of the checklist collection.
+func CreateMongoChecklistIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "position", Value: 1}},
+		Options: options.Index().SetName("idx_task_checklist_item_task_id"),
+	})
+	return
+}
+
+func (r *mongoChecklistRepository) Save(ctx context.Context, item entity.ChecklistItem) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err == nil {
+		_, err = r.collection().InsertOne(ctx, checklistItemDocument{
+			ID:        id,
+			TaskID:    item.TaskID,
+			Name:      item.Name,
+			Done:      item.Done,
+			Position:  item.Position,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) UpdateById(ctx context.Context, id int64, item entity.ChecklistItem) (err error) {
+	_, err = r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"name":       item.Name,
+		"done":       item.Done,
+		"position":   item.Position,
+		"updated_at": item.UpdatedAt,
+	}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) UpdatePositions(ctx context.Context, taskID int64, ids []int64) (err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	models := make([]mongo.WriteModel, len(ids))
+	for position, id := range ids {
+		models[position] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "task_id": taskID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": position}})
+	}
+	if _, err = r.collection().BulkWrite(ctx, models); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) FindByTaskId(ctx context.Context, taskID int64) (items []entity.ChecklistItem, err error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"task_id": taskID}, options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []checklistItemDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	items = make([]entity.ChecklistItem, 0, len(documents))
+	for _, document := range documents {
+		items = append(items, document.entity())
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) FindOneById(ctx context.Context, id int64) (item entity.ChecklistItem, err error) {
+	var document checklistItemDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+	return document.entity(), nil
+}
+
+func (r *mongoChecklistRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (progress map[int64]entity.TaskProgress, err error) {
+	progress = make(map[int64]entity.TaskProgress)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	cursor, err := r.collection().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"task_id": bson.M{"$in": taskIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$task_id",
+			"total": bson.M{"$sum": 1},
+			"done":  bson.M{"$sum": bson.M{"$cond": bson.A{"$done", 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var counts []struct {
+		TaskID int64 `bson:"_id"`
+		Done   int   `bson:"done"`
+		Total  int   `bson:"total"`
+	}
+	if err = cursor.All(ctx, &counts); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	for _, count := range counts {
+		progress[count.TaskID] = entity.TaskProgress{Done: count.Done, Total: count.Total}
+	}
+	return
+}
+
+func (r *mongoChecklistRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d checklistItemDocument) entity() entity.ChecklistItem {
+	return entity.ChecklistItem{
+		ID:        d.ID,
+		TaskID:    d.TaskID,
+		Name:      d.Name,
+		Done:      d.Done,
+		Position:  d.Position,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
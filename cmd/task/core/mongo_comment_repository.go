@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type commentDocument struct {
+	ID        int64      `bson:"_id"`
+	TaskID    int64      `bson:"task_id"`
+	Author    string     `bson:"author"`
+	Body      string     `bson:"body"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt *time.Time `bson:"updated_at"`
+}
+
+type mongoCommentRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoCommentRepository is a constructor
+func NewMongoCommentRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) CommentRepository {
+	return &mongoCommentRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoCommentIndexes creates the indexes of the comment collection.
+func CreateMongoCommentIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("idx_task_comment_task_id"),
+	})
+	return
+}
+
+func (r *mongoCommentRepository) Save(ctx context.Context, comment entity.Comment) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err == nil {
+		_, err = r.collection().InsertOne(ctx, commentDocument{
+			ID:        id,
+			TaskID:    comment.TaskID,
+			Author:    comment.Author,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoCommentRepository) UpdateById(ctx context.Context, id int64, comment entity.Comment) (err error) {
+	_, err = r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"body":       comment.Body,
+		"updated_at": comment.UpdatedAt,
+	}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoCommentRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoCommentRepository) FindOneById(ctx context.Context, id int64) (comment entity.Comment, err error) {
+	var document commentDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+	return document.entity(), nil
+}
+
+func (r *mongoCommentRepository) FindByTaskId(ctx context.Context, taskID int64, afterID int64, limit int) (comments []entity.Comment, err error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection().Find(ctx, bson.M{"task_id": taskID, "_id": bson.M{"$gt": afterID}}, findOptions)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []commentDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	comments = make([]entity.Comment, 0, len(documents))
+	for _, document := range documents {
+		comments = append(comments, document.entity())
+	}
+	return
+}
+
+func (r *mongoCommentRepository) CountByTaskIds(ctx context.Context, taskIDs []int64) (counts map[int64]int, err error) {
+	counts = make(map[int64]int)
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	cursor, err := r.collection().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"task_id": bson.M{"$in": taskIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$task_id", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var results []struct {
+		TaskID int64 `bson:"_id"`
+		Count  int   `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	for _, result := range results {
+		counts[result.TaskID] = result.Count
+	}
+	return
+}
+
+func (r *mongoCommentRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d commentDocument) entity() entity.Comment {
+	return entity.Comment{
+		ID:        d.ID,
+		TaskID:    d.TaskID,
+		Author:    d.Author,
+		Body:      d.Body,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
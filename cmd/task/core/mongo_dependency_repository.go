@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type dependencyDocument struct {
+	TaskID    int64     `bson:"task_id"`
+	BlockerID int64     `bson:"blocker_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type mongoDependencyRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoDependencyRepository is a constructor
+func NewMongoDependencyRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) DependencyRepository {
+	return &mongoDependencyRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoDependencyIndexes creates the indexes of the dependency collection, an edge is unique.
+func CreateMongoDependencyIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "blocker_id", Value: 1}}, Options: options.Index().SetName("uq_task_dependency").SetUnique(true)},
+		{Keys: bson.D{{Key: "blocker_id", Value: 1}}, Options: options.Index().SetName("idx_task_dependency_blocker_id")},
+	})
+	return
+}
+
+func (r *mongoDependencyRepository) Save(ctx context.Context, dependency entity.Dependency) (err error) {
+	_, err = r.collection().InsertOne(ctx, dependencyDocument{TaskID: dependency.TaskID, BlockerID: dependency.BlockerID, CreatedAt: dependency.CreatedAt})
+	if err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoDependencyRepository) Delete(ctx context.Context, taskID int64, blockerID int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"task_id": taskID, "blocker_id": blockerID}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoDependencyRepository) FindBlockers(ctx context.Context, taskIDs []int64) (dependencies []entity.Dependency, err error) {
+	return r.find(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+}
+
+func (r *mongoDependencyRepository) FindDependents(ctx context.Context, blockerIDs []int64) (dependencies []entity.Dependency, err error) {
+	return r.find(ctx, bson.M{"blocker_id": bson.M{"$in": blockerIDs}})
+}
+
+func (r *mongoDependencyRepository) find(ctx context.Context, filter bson.M) (dependencies []entity.Dependency, err error) {
+	cursor, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "task_id", Value: 1}, {Key: "blocker_id", Value: 1}}))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []dependencyDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	dependencies = make([]entity.Dependency, 0, len(documents))
+	for _, document := range documents {
+		dependencies = append(dependencies, entity.Dependency{TaskID: document.TaskID, BlockerID: document.BlockerID, CreatedAt: document.CreatedAt})
+	}
+	return
+}
+
+func (r *mongoDependencyRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
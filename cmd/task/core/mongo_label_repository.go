@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type labelDocument struct {
+	ID        int64      `bson:"_id"`
+	Name      string     `bson:"name"`
+	Color     string     `bson:"color"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt *time.Time `bson:"updated_at"`
+}
+
+type mongoLabelRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoLabelRepository is a constructor
+func NewMongoLabelRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) LabelRepository {
+	return &mongoLabelRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoLabelIndexes creates the indexes of the label collection, the names are unique.
+func CreateMongoLabelIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("uq_label_name").SetUnique(true),
+	})
+	return
+}
+
+func (r *mongoLabelRepository) Save(ctx context.Context, label entity.Label) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	_, err = r.collection().InsertOne(ctx, labelDocument{ID: id, Name: label.Name, Color: label.Color, CreatedAt: label.CreatedAt})
+	if err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoLabelRepository) UpdateById(ctx context.Context, id int64, label entity.Label) (err error) {
+	_, err = r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"name":       label.Name,
+		"color":      label.Color,
+		"updated_at": label.UpdatedAt,
+	}})
+	if err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoLabelRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoLabelRepository) FindMany(ctx context.Context) (labels []entity.Label, err error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *mongoLabelRepository) FindByIds(ctx context.Context, ids []int64) (labels []entity.Label, err error) {
+	if len(ids) == 0 {
+		return make([]entity.Label, 0), nil
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (r *mongoLabelRepository) FindOneById(ctx context.Context, id int64) (label entity.Label, err error) {
+	var document labelDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+	return document.entity(), nil
+}
+
+func (r *mongoLabelRepository) find(ctx context.Context, filter bson.M) (labels []entity.Label, err error) {
+	cursor, err := r.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []labelDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	labels = make([]entity.Label, 0, len(documents))
+	for _, document := range documents {
+		labels = append(labels, document.entity())
+	}
+	return
+}
+
+func (r *mongoLabelRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d labelDocument) entity() entity.Label {
+	return entity.Label{ID: d.ID, Name: d.Name, Color: d.Color, CreatedAt: d.CreatedAt, UpdatedAt: d.UpdatedAt}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type projectDocument struct {
+	ID          int64      `bson:"_id"`
+	Name        string     `bson:"name"`
+	Description string     `bson:"description"`
+	ArchivedAt  *time.Time `bson:"archived_at"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   *time.Time `bson:"updated_at"`
+}
+
+type mongoProjectRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoProjectRepository is a constructor
+func NewMongoProjectRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) ProjectRepository {
+	return &mongoProjectRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoProjectIndexes creates the indexes of the project collection, the names are unique.
+func CreateMongoProjectIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("uq_project_name").SetUnique(true),
+	})
+	return
+}
+
+func (r *mongoProjectRepository) Save(ctx context.Context, project entity.Project) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	_, err = r.collection().InsertOne(ctx, projectDocument{
+		ID:          id,
+		Name:        project.Name,
+		Description: project.Description,
+		ArchivedAt:  project.ArchivedAt,
+		CreatedAt:   project.CreatedAt,
+	})
+	if err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoProjectRepository) UpdateById(ctx context.Context, id int64, project entity.Project) (err error) {
+	_, err = r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"name":        project.Name,
+		"description": project.Description,
+		"archived_at": project.ArchivedAt,
+		"updated_at":  project.UpdatedAt,
+	}})
+	if err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoProjectRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoProjectRepository) FindMany(ctx context.Context, filter ProjectFilter) (projects []entity.Project, err error) {
+	query := bson.M{}
+	if filter.Archived != nil && *filter.Archived {
+		query["archived_at"] = bson.M{"$ne": nil}
+	}
+	if filter.Archived != nil && !*filter.Archived {
+		query["archived_at"] = nil
+	}
+
+	cursor, err := r.collection().Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []projectDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	projects = make([]entity.Project, 0, len(documents))
+	for _, document := range documents {
+		projects = append(projects, document.entity())
+	}
+	return
+}
+
+func (r *mongoProjectRepository) FindOneById(ctx context.Context, id int64) (project entity.Project, err error) {
+	var document projectDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+	return document.entity(), nil
+}
+
+func (r *mongoProjectRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d projectDocument) entity() entity.Project {
+	return entity.Project{
+		ID:          d.ID,
+		Name:        d.Name,
+		Description: d.Description,
+		ArchivedAt:  d.ArchivedAt,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
@@ -0,0 +1,542 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// counterCollectionName holds the sequences that give the documents an int64 id, like an auto increment column.
+const counterCollectionName = "counters"
+
+type taskDocument struct {
+	ID          int64      `bson:"_id"`
+	Name        string     `bson:"name"`
+	Description *string    `bson:"description"`
+	Status      *int       `bson:"status"`
+	Priority    int        `bson:"priority"`
+	Attachment  *string    `bson:"attachment"`
+	DueAt       *time.Time `bson:"due_at"`
+	RemindAt    *time.Time `bson:"remind_at"`
+	RemindedAt  *time.Time `bson:"reminded_at"`
+	Recurrence  *string    `bson:"recurrence"`
+	SeriesID    *int64     `bson:"series_id"`
+	SeriesStart *time.Time `bson:"series_start"`
+	Occurrence  int        `bson:"occurrence"`
+	LabelIDs    []int64    `bson:"label_ids"`
+	Assignees   []string   `bson:"assignees"`
+	Watchers    []string   `bson:"watchers"`
+	ParentID    *int64     `bson:"parent_id"`
+	ProjectID   *int64     `bson:"project_id"`
+	Rank        string     `bson:"rank"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   *time.Time `bson:"updated_at"`
+}
+
+type mongoTaskRepository struct {
+	logger         *logrus.Logger
+	client         *mongo.Client
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoTaskRepository is a constructor
+func NewMongoTaskRepository(logger *logrus.Logger, client *mongo.Client, database *mongo.Database, collectionName string) TaskRepository {
+	return &mongoTaskRepository{
+		logger:         logger,
+		client:         client,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoTaskIndexes creates the indexes of the task collection, existing indexes are kept.
+func CreateMongoTaskIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("idx_task_name")},
+		{Keys: bson.D{{Key: "attachment", Value: 1}}, Options: options.Index().SetName("idx_task_attachment").SetSparse(true)},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetName("idx_task_created_at")},
+		{Keys: bson.D{{Key: "due_at", Value: 1}}, Options: options.Index().SetName("idx_task_due_at").SetSparse(true)},
+		{Keys: bson.D{{Key: "remind_at", Value: 1}}, Options: options.Index().SetName("idx_task_remind_at").SetSparse(true)},
+		{Keys: bson.D{{Key: "series_id", Value: 1}}, Options: options.Index().SetName("idx_task_series_id").SetSparse(true)},
+		{Keys: bson.D{{Key: "priority", Value: 1}}, Options: options.Index().SetName("idx_task_priority")},
+		{Keys: bson.D{{Key: "label_ids", Value: 1}}, Options: options.Index().SetName("idx_task_label_ids")},
+		{Keys: bson.D{{Key: "assignees", Value: 1}}, Options: options.Index().SetName("idx_task_assignees")},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}, Options: options.Index().SetName("idx_task_parent_id").SetSparse(true)},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index().SetName("idx_task_project_id")},
+		{Keys: bson.D{{Key: "rank", Value: 1}}, Options: options.Index().SetName("idx_task_rank")},
+		// the text index of the search, a word of the name weighs twice a word of the description.
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetName("idx_task_text").SetWeights(bson.D{{Key: "name", Value: 2}, {Key: "description", Value: 1}}),
+		},
+	})
+	return
+}
+
+// mongoTx is a transaction of a mongo session, transactions need a replica set.
+type mongoTx struct {
+	session mongo.Session
+}
+
+func (tx *mongoTx) Commit() error {
+	defer tx.session.EndSession(context.Background())
+	return tx.session.CommitTransaction(context.Background())
+}
+
+func (tx *mongoTx) Rollback() error {
+	defer tx.session.EndSession(context.Background())
+	return tx.session.AbortTransaction(context.Background())
+}
+
+// BeginTx starts a session with a transaction.
+func (r *mongoTaskRepository) BeginTx(ctx context.Context) (tx database.Tx, err error) {
+	session, err := r.client.StartSession()
+	if err != nil {
+		return
+	}
+	if err = session.StartTransaction(); err != nil {
+		session.EndSession(ctx)
+		return
+	}
+	return &mongoTx{session: session}, nil
+}
+
+// CommitTx will commit the transaction that has began.
+func (r *mongoTaskRepository) CommitTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Commit()
+}
+
+// RollbackTx will rollback the transaction to achieve the consistency.
+func (r *mongoTaskRepository) RollbackTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r *mongoTaskRepository) FindMany(ctx context.Context, filter Filter) (bunchOfTasks []entity.Task, err error) {
+	query := taskFilter(filter)
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"rank": bson.M{"$gt": filter.After.Rank}},
+			bson.M{"rank": filter.After.Rank, "_id": bson.M{"$gt": filter.After.ID}},
+		}
+	}
+	findOptions := options.Find().SetSort(taskSort(filter.Sort))
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection().Find(ctx, query, findOptions)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []taskDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	for _, document := range documents {
+		bunchOfTasks = append(bunchOfTasks, document.entity())
+	}
+	return
+}
+
+func (r *mongoTaskRepository) Count(ctx context.Context, filter Filter) (count int, err error) {
+	total, err := r.collection().CountDocuments(ctx, taskFilter(filter))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		return 0, wrapMongoError(err)
+	}
+	return int(total), nil
+}
+
+func (r *mongoTaskRepository) FindOneById(ctx context.Context, id int64) (task entity.Task, err error) {
+	var document taskDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+
+	task = document.entity()
+	return
+}
+
+// FindAttachments returns every attachment that is linked to a task.
+func (r *mongoTaskRepository) FindAttachments(ctx context.Context) (attachments []string, err error) {
+	values, err := r.collection().Distinct(ctx, "attachment", bson.M{"attachment": bson.M{"$ne": nil}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	attachments = make([]string, 0, len(values))
+	for _, value := range values {
+		if attachment, ok := value.(string); ok {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return
+}
+
+func (r *mongoTaskRepository) FindDueReminders(ctx context.Context, until time.Time, limit int) (bunchOfTasks []entity.Task, err error) {
+	filter := bson.M{
+		"remind_at":   bson.M{"$lte": until},
+		"reminded_at": nil,
+		"status":      bson.M{"$ne": entity.TaskStatusDone},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "remind_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := r.collection().Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []taskDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	for _, document := range documents {
+		bunchOfTasks = append(bunchOfTasks, document.entity())
+	}
+	return
+}
+
+func (r *mongoTaskRepository) MarkReminded(ctx context.Context, id int64, remindedAt time.Time) (err error) {
+	_, err = r.collection().UpdateOne(ctx, bson.M{"_id": id, "reminded_at": nil}, bson.M{"$set": bson.M{"reminded_at": remindedAt}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoTaskRepository) RemoveLabel(ctx context.Context, labelID int64) (err error) {
+	if _, err = r.collection().UpdateMany(ctx, bson.M{"label_ids": labelID}, bson.M{"$pull": bson.M{"label_ids": labelID}}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoTaskRepository) RemoveProject(ctx context.Context, projectID int64) (err error) {
+	if _, err = r.collection().UpdateMany(ctx, bson.M{"project_id": projectID}, bson.M{"$set": bson.M{"project_id": nil}}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoTaskRepository) CountByProjectIds(ctx context.Context, projectIDs []int64) (counts map[int64]entity.StatusCounts, err error) {
+	counts = make(map[int64]entity.StatusCounts)
+	if len(projectIDs) == 0 {
+		return
+	}
+
+	cursor, err := r.collection().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": bson.M{"$in": projectIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"project_id": "$project_id", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var groups []struct {
+		Key struct {
+			ProjectID int64 `bson:"project_id"`
+			Status    int   `bson:"status"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	for _, group := range groups {
+		projectCounts := counts[group.Key.ProjectID]
+		projectCounts.Add(group.Key.Status, group.Count)
+		counts[group.Key.ProjectID] = projectCounts
+	}
+	return
+}
+
+func (r *mongoTaskRepository) FindLastRank(ctx context.Context) (rank string, err error) {
+	return r.findRank(ctx, bson.M{}, -1)
+}
+
+func (r *mongoTaskRepository) FindAdjacentRank(ctx context.Context, rank string, after bool) (adjacent string, err error) {
+	if after {
+		return r.findRank(ctx, bson.M{"rank": bson.M{"$gt": rank}}, 1)
+	}
+	return r.findRank(ctx, bson.M{"rank": bson.M{"$lt": rank}}, -1)
+}
+
+// findRank returns the rank of the first task of the filter in the direction, an empty rank when there is none.
+func (r *mongoTaskRepository) findRank(ctx context.Context, filter bson.M, direction int) (rank string, err error) {
+	var document struct {
+		Rank string `bson:"rank"`
+	}
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "rank", Value: direction}}).SetProjection(bson.M{"rank": 1})
+	if err = r.collection().FindOne(ctx, filter, findOptions).Decode(&document); err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		return "", wrapMongoError(err)
+	}
+	return document.Rank, nil
+}
+
+func (r *mongoTaskRepository) UpdateRank(ctx context.Context, id int64, rank string, tx database.Tx) (err error) {
+	if _, err = r.collection().UpdateByID(sessionContext(ctx, tx), id, bson.M{"$set": bson.M{"rank": rank}}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+// Save mirrors the sql repository, the attachment is only set by an update.
+func (r *mongoTaskRepository) Save(ctx context.Context, task TaskRequest, tx database.Tx) (id int64, err error) {
+	if id, err = r.nextID(ctx); err != nil {
+		return
+	}
+
+	_, err = r.collection().InsertOne(sessionContext(ctx, tx), taskDocument{
+		ID:          id,
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    intValue(task.Priority),
+		DueAt:       task.DueAt,
+		RemindAt:    task.RemindAt,
+		Recurrence:  task.Recurrence,
+		SeriesID:    task.SeriesID,
+		SeriesStart: task.SeriesStart,
+		Occurrence:  task.Occurrence,
+		LabelIDs:    uniqueIDs(task.LabelIDs),
+		Assignees:   uniqueStrings(task.Assignees),
+		Watchers:    uniqueStrings(task.Watchers),
+		ParentID:    task.ParentID,
+		ProjectID:   task.ProjectID,
+		Rank:        task.Rank,
+		CreatedAt:   task.CreatedAt,
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoTaskRepository) UpdateById(ctx context.Context, id int64, task TaskRequest, tx database.Tx) (err error) {
+	_, err = r.collection().UpdateByID(sessionContext(ctx, tx), id, bson.M{"$set": bson.M{
+		"name":         task.Name,
+		"description":  task.Description,
+		"status":       task.Status,
+		"priority":     intValue(task.Priority),
+		"attachment":   task.Attachment,
+		"due_at":       task.DueAt,
+		"remind_at":    task.RemindAt,
+		"reminded_at":  task.RemindedAt,
+		"recurrence":   task.Recurrence,
+		"series_id":    task.SeriesID,
+		"series_start": task.SeriesStart,
+		"occurrence":   task.Occurrence,
+		"label_ids":    uniqueIDs(task.LabelIDs),
+		"assignees":    uniqueStrings(task.Assignees),
+		"watchers":     uniqueStrings(task.Watchers),
+		"parent_id":    task.ParentID,
+		"project_id":   task.ProjectID,
+		"updated_at":   task.UpdatedAt,
+	}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoTaskRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+// nextID increments the sequence of the collection outside of any transaction,
+// so an aborted insert leaves a gap like an auto increment column does.
+func (r *mongoTaskRepository) nextID(ctx context.Context) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+// nextSequence increments the sequence of a collection, the sequences give the documents an int64 id.
+func nextSequence(ctx context.Context, database *mongo.Database, collectionName string) (id int64, err error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	err = database.Collection(counterCollectionName).FindOneAndUpdate(ctx,
+		bson.M{"_id": collectionName},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	return counter.Seq, err
+}
+
+// taskFilter translates the filter of the request into a mongo filter.
+func taskFilter(filter Filter) bson.M {
+	query := bson.M{}
+	if filter.Name != nil {
+		query["name"] = *filter.Name
+	}
+	if filter.Attachment != nil {
+		query["attachment"] = *filter.Attachment
+	}
+	if filter.DueFrom != nil || filter.DueUntil != nil {
+		// a date comparison never matches a missing due date.
+		dueAt := bson.M{}
+		if filter.DueFrom != nil {
+			dueAt["$gte"] = *filter.DueFrom
+		}
+		if filter.DueUntil != nil {
+			dueAt["$lt"] = *filter.DueUntil
+		}
+		query["due_at"] = dueAt
+	}
+	status := bson.M{}
+	if len(filter.Statuses) > 0 {
+		status["$in"] = filter.Statuses
+	}
+	if filter.Pending {
+		status["$ne"] = entity.TaskStatusDone
+	}
+	if len(status) > 0 {
+		query["status"] = status
+	}
+	if filter.SeriesID != nil {
+		query["series_id"] = *filter.SeriesID
+	}
+	if filter.IDs != nil {
+		query["_id"] = bson.M{"$in": filter.IDs}
+	}
+	if filter.ParentID != nil {
+		query["parent_id"] = *filter.ParentID
+	}
+	// $nin matches the tasks without a project too.
+	project := bson.M{}
+	if filter.ProjectID != nil {
+		project["$eq"] = *filter.ProjectID
+	}
+	if len(filter.ExcludedProjectIDs) > 0 {
+		project["$nin"] = filter.ExcludedProjectIDs
+	}
+	if len(project) > 0 {
+		query["project_id"] = project
+	}
+	if len(filter.Priorities) > 0 {
+		query["priority"] = bson.M{"$in": filter.Priorities}
+	}
+	if len(filter.LabelIDs) > 0 {
+		operator := "$in"
+		if filter.AllLabels {
+			operator = "$all"
+		}
+		query["label_ids"] = bson.M{operator: filter.LabelIDs}
+	}
+	if filter.Assignee != nil {
+		query["assignees"] = *filter.Assignee
+	}
+	if filter.Unassigned {
+		query["assignees.0"] = bson.M{"$exists": false}
+	}
+	return query
+}
+
+// taskSort translates the sort of the request into a mongo sort.
+func taskSort(sort string) bson.D {
+	switch sort {
+	case SortPriority:
+		return bson.D{{Key: "priority", Value: 1}, {Key: "_id", Value: 1}}
+	case SortPriorityDesc:
+		return bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: 1}}
+	case SortManual:
+		return bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}
+	}
+	return bson.D{{Key: "_id", Value: 1}}
+}
+
+func sessionContext(ctx context.Context, tx database.Tx) context.Context {
+	if mongoTx, ok := tx.(*mongoTx); ok {
+		return mongo.NewSessionContext(ctx, mongoTx.session)
+	}
+	return ctx
+}
+
+func (d taskDocument) entity() (task entity.Task) {
+	task = entity.Task{
+		ID:          d.ID,
+		Name:        d.Name,
+		Attachment:  d.Attachment,
+		DueAt:       d.DueAt,
+		RemindAt:    d.RemindAt,
+		RemindedAt:  d.RemindedAt,
+		Recurrence:  d.Recurrence,
+		SeriesID:    d.SeriesID,
+		SeriesStart: d.SeriesStart,
+		Occurrence:  d.Occurrence,
+		Priority:    d.Priority,
+		LabelIDs:    uniqueIDs(d.LabelIDs),
+		Assignees:   uniqueStrings(d.Assignees),
+		Watchers:    uniqueStrings(d.Watchers),
+		ParentID:    d.ParentID,
+		ProjectID:   d.ProjectID,
+		Rank:        d.Rank,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+	if d.Description != nil {
+		task.Description = *d.Description
+	}
+	if d.Status != nil {
+		task.Status = *d.Status
+	}
+	return
+}
+
+func wrapMongoError(e error) (err error) {
+	if errors.Is(e, mongo.ErrNoDocuments) {
+		return exception.ErrNotFound
+	}
+	if mongo.IsDuplicateKeyError(e) {
+		return exception.ErrConflict
+	}
+	return exception.ErrInternalServer
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo-app-api/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTaskFilter(t *testing.T) {
+	name, attachment := "groceries", "https://storage.googleapis.com/bucket/wr/todo_attachment/a.png"
+	from, until := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		filter   Filter
+		expected bson.M
+	}{
+		{Filter{}, bson.M{}},
+		{Filter{Name: &name}, bson.M{"name": name}},
+		{Filter{Name: &name, Attachment: &attachment}, bson.M{"name": name, "attachment": attachment}},
+		{Filter{DueFrom: &from, DueUntil: &until, Pending: true}, bson.M{
+			"due_at": bson.M{"$gte": from, "$lt": until},
+			"status": bson.M{"$ne": entity.TaskStatusDone},
+		}},
+	}
+
+	for _, c := range cases {
+		if query := taskFilter(c.filter); !reflect.DeepEqual(query, c.expected) {
+			t.Errorf("expected %v, got %v", c.expected, query)
+		}
+	}
+}
+
+func TestTaskDocumentEntity(t *testing.T) {
+	task := taskDocument{ID: 7, Name: "untouched"}.entity()
+	if task.ID != 7 || task.Description != "" || task.Status != 0 || task.Attachment != nil {
+		t.Errorf("expected the missing fields to be zero, got %+v", task)
+	}
+}
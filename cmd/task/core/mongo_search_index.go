@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type searchDocument struct {
+	ID    int64   `bson:"_id"`
+	Score float64 `bson:"score"`
+}
+
+type mongoSearchIndex struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoSearchIndex is a constructor of the index of the mongo driver, it reads the text index of the task collection.
+func NewMongoSearchIndex(logger *logrus.Logger, database *mongo.Database, collectionName string) SearchIndex {
+	return &mongoSearchIndex{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// Index does nothing, mongo keeps the text index up to date with the collection.
+func (i *mongoSearchIndex) Index(ctx context.Context, task entity.Task) (err error) {
+	return
+}
+
+func (i *mongoSearchIndex) Search(ctx context.Context, query string, limit int) (hits []SearchHit, err error) {
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.D{{Key: "_id", Value: 1}, {Key: "score", Value: score}}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := i.database.Collection(i.collectionName).Find(ctx, bson.M{"$text": bson.M{"$search": query}}, findOptions)
+	if err != nil {
+		i.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []searchDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		i.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	hits = make([]SearchHit, 0, len(documents))
+	for _, document := range documents {
+		hits = append(hits, SearchHit{TaskID: document.ID, Score: document.Score})
+	}
+	return
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type viewDocument struct {
+	ID        int64      `bson:"_id"`
+	Owner     string     `bson:"owner"`
+	Name      string     `bson:"name"`
+	Query     string     `bson:"query_string"`
+	Sort      string     `bson:"sort"`
+	Shared    bool       `bson:"shared"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt *time.Time `bson:"updated_at"`
+}
+
+type mongoViewRepository struct {
+	logger         *logrus.Logger
+	database       *mongo.Database
+	collectionName string
+}
+
+// NewMongoViewRepository is a constructor
+func NewMongoViewRepository(logger *logrus.Logger, database *mongo.Database, collectionName string) ViewRepository {
+	return &mongoViewRepository{
+		logger:         logger,
+		database:       database,
+		collectionName: collectionName,
+	}
+}
+
+// CreateMongoViewIndexes creates the indexes of the view collection.
+func CreateMongoViewIndexes(ctx context.Context, database *mongo.Database, collectionName string) (err error) {
+	_, err = database.Collection(collectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}}, Options: options.Index().SetName("idx_task_view_owner")},
+		{Keys: bson.D{{Key: "shared", Value: 1}}, Options: options.Index().SetName("idx_task_view_shared")},
+	})
+	return
+}
+
+func (r *mongoViewRepository) Save(ctx context.Context, view entity.View) (id int64, err error) {
+	if id, err = nextSequence(ctx, r.database, r.collectionName); err == nil {
+		_, err = r.collection().InsertOne(ctx, viewDocument{
+			ID:        id,
+			Owner:     view.Owner,
+			Name:      view.Name,
+			Query:     view.Query,
+			Sort:      view.Sort,
+			Shared:    view.Shared,
+			CreatedAt: view.CreatedAt,
+		})
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoViewRepository) UpdateById(ctx context.Context, id int64, view entity.View) (err error) {
+	_, err = r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"name":         view.Name,
+		"query_string": view.Query,
+		"sort":         view.Sort,
+		"shared":       view.Shared,
+		"updated_at":   view.UpdatedAt,
+	}})
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoViewRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	if _, err = r.collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+	}
+	return
+}
+
+func (r *mongoViewRepository) FindOneById(ctx context.Context, id int64) (view entity.View, err error) {
+	var document viewDocument
+	if err = r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err != mongo.ErrNoDocuments {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		err = wrapMongoError(err)
+		return
+	}
+	return document.entity(), nil
+}
+
+func (r *mongoViewRepository) FindVisible(ctx context.Context, owner string) (views []entity.View, err error) {
+	query := bson.M{"$or": bson.A{bson.M{"owner": owner}, bson.M{"shared": true}}}
+	cursor, err := r.collection().Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	var documents []viewDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		r.logger.WithContext(ctx).Error(err)
+		err = wrapMongoError(err)
+		return
+	}
+
+	views = make([]entity.View, 0, len(documents))
+	for _, document := range documents {
+		views = append(views, document.entity())
+	}
+	return
+}
+
+func (r *mongoViewRepository) collection() *mongo.Collection {
+	return r.database.Collection(r.collectionName)
+}
+
+func (d viewDocument) entity() entity.View {
+	return entity.View{
+		ID:        d.ID,
+		Owner:     d.Owner,
+		Name:      d.Name,
+		Query:     d.Query,
+		Sort:      d.Sort,
+		Shared:    d.Shared,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
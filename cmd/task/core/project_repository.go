@@ -0,0 +1,169 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectRepository stores the projects, a project name is unique.
+type ProjectRepository interface {
+	Save(ctx context.Context, project entity.Project) (id int64, err error)
+	UpdateById(ctx context.Context, id int64, project entity.Project) (err error)
+	DeleteById(ctx context.Context, id int64) (err error)
+	FindMany(ctx context.Context, filter ProjectFilter) (projects []entity.Project, err error)
+	FindOneById(ctx context.Context, id int64) (project entity.Project, err error)
+}
+
+const projectColumns = "p.id, p.name, p.description, p.archived_at, p.created_at, p.updated_at"
+
+type projectRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewProjectRepository is a constructor
+func NewProjectRepository(logger *logrus.Logger, db *database.Router, tableName string) ProjectRepository {
+	return &projectRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *projectRepository) Save(ctx context.Context, project entity.Project) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("name", "description", "archived_at", "created_at").
+		Values(project.Name, project.Description, project.ArchivedAt, project.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		if !database.IsUniqueViolation(err) {
+			r.logger.WithContext(ctx).Error(stmt, err)
+		}
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *projectRepository) UpdateById(ctx context.Context, id int64, project entity.Project) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("name", project.Name).
+		Set("description", project.Description).
+		Set("archived_at", project.ArchivedAt).
+		Set("updated_at", project.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		if !database.IsUniqueViolation(err) {
+			r.logger.WithContext(ctx).Error(stmt, err)
+		}
+		err = wrapError(err)
+	}
+	return
+}
+
+// DeleteById deletes the project, its tasks leave it by the foreign key.
+func (r *projectRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+// FindMany returns the projects sorted by name.
+func (r *projectRepository) FindMany(ctx context.Context, filter ProjectFilter) (projects []entity.Project, err error) {
+	stmt := r.builder.Select(projectColumns).From(r.tableName + " p").OrderBy("p.name")
+	if filter.Archived != nil && *filter.Archived {
+		stmt = stmt.Where(sq.NotEq{"p.archived_at": nil})
+	}
+	if filter.Archived != nil && !*filter.Archived {
+		stmt = stmt.Where(sq.Eq{"p.archived_at": nil})
+	}
+	return r.find(ctx, stmt)
+}
+
+func (r *projectRepository) FindOneById(ctx context.Context, id int64) (project entity.Project, err error) {
+	projects, err := r.find(ctx, r.builder.Select(projectColumns).From(r.tableName+" p").Where(sq.Eq{"p.id": id}))
+	if err != nil {
+		return
+	}
+	if len(projects) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+	return projects[0], nil
+}
+
+func (r *projectRepository) find(ctx context.Context, builder sq.SelectBuilder) (projects []entity.Project, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	projects = make([]entity.Project, 0)
+	for rows.Next() {
+		var project entity.Project
+		var description sql.NullString
+		var archivedAt, updatedAt sql.NullTime
+		if err = rows.Scan(&project.ID, &project.Name, &description, &archivedAt, &project.CreatedAt, &updatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		project.Description = description.String
+		if archivedAt.Valid {
+			project.ArchivedAt = &archivedAt.Time
+		}
+		if updatedAt.Valid {
+			project.UpdatedAt = &updatedAt.Time
+		}
+		projects = append(projects, project)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectService manages the projects that group the tasks.
+// The errors are exception errors, exception.ErrConflict is returned for a name that is taken.
+type ProjectService interface {
+	// GetManyProjects returns the projects sorted by name along with the number of their tasks by status.
+	GetManyProjects(ctx context.Context, filter ProjectFilter) (projects []entity.Project, counts map[int64]entity.StatusCounts, err error)
+	GetOneProject(ctx context.Context, id int64) (project entity.Project, err error)
+	CreateProject(ctx context.Context, projectRequest ProjectRequest) (project entity.Project, err error)
+	UpdateProject(ctx context.Context, id int64, projectRequest ProjectRequest) (project entity.Project, err error)
+	// ArchiveProject archives or restores the project, archiving an archived project keeps its archive date.
+	ArchiveProject(ctx context.Context, id int64, archived bool) (project entity.Project, err error)
+	DeleteProject(ctx context.Context, id int64) (err error)
+}
+
+type projectService struct {
+	logger            *logrus.Logger
+	location          *time.Location
+	projectRepository ProjectRepository
+	taskRepository    TaskRepository
+}
+
+// NewProjectService is a constructor
+func NewProjectService(logger *logrus.Logger, location *time.Location, projectRepository ProjectRepository, taskRepository TaskRepository) ProjectService {
+	return &projectService{
+		logger:            logger,
+		location:          location,
+		projectRepository: projectRepository,
+		taskRepository:    taskRepository,
+	}
+}
+
+func (s *projectService) GetManyProjects(ctx context.Context, filter ProjectFilter) (projects []entity.Project, counts map[int64]entity.StatusCounts, err error) {
+	if projects, err = s.projectRepository.FindMany(ctx, filter); err != nil {
+		return nil, nil, s.wrapError(ctx, err)
+	}
+
+	ids := make([]int64, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+	if counts, err = s.taskRepository.CountByProjectIds(ctx, ids); err != nil {
+		return nil, nil, s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *projectService) GetOneProject(ctx context.Context, id int64) (project entity.Project, err error) {
+	if project, err = s.projectRepository.FindOneById(ctx, id); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *projectService) CreateProject(ctx context.Context, projectRequest ProjectRequest) (project entity.Project, err error) {
+	project = entity.Project{
+		Name:        strings.TrimSpace(projectRequest.Name),
+		Description: projectRequest.Description,
+		CreatedAt:   time.Now().In(s.location),
+	}
+	if project.ID, err = s.projectRepository.Save(ctx, project); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *projectService) UpdateProject(ctx context.Context, id int64, projectRequest ProjectRequest) (project entity.Project, err error) {
+	if project, err = s.GetOneProject(ctx, id); err != nil {
+		return
+	}
+
+	updatedAt := time.Now().In(s.location)
+	project.Name = strings.TrimSpace(projectRequest.Name)
+	project.Description = projectRequest.Description
+	project.UpdatedAt = &updatedAt
+	if err = s.projectRepository.UpdateById(ctx, id, project); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *projectService) ArchiveProject(ctx context.Context, id int64, archived bool) (project entity.Project, err error) {
+	if project, err = s.GetOneProject(ctx, id); err != nil {
+		return
+	}
+	if archived == (project.ArchivedAt != nil) {
+		return
+	}
+
+	now := time.Now().In(s.location)
+	project.ArchivedAt = nil
+	if archived {
+		project.ArchivedAt = &now
+	}
+	project.UpdatedAt = &now
+	if err = s.projectRepository.UpdateById(ctx, id, project); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+// DeleteProject takes the tasks out of the project before it is deleted, the tasks are kept.
+func (s *projectService) DeleteProject(ctx context.Context, id int64) (err error) {
+	if _, err = s.GetOneProject(ctx, id); err != nil {
+		return
+	}
+
+	if err = s.taskRepository.RemoveProject(ctx, id); err != nil {
+		return s.wrapError(ctx, err)
+	}
+	if err = s.projectRepository.DeleteById(ctx, id); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *projectService) wrapError(ctx context.Context, err error) error {
+	if err == exception.ErrNotFound || err == exception.ErrConflict {
+		return err
+	}
+	s.logger.WithContext(ctx).Error(err)
+	return exception.ErrInternalServer
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestProjects(t *testing.T) {
+	ctx := context.Background()
+	repositories := newTestRepositories()
+	taskService := core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, core.TaskOptions{})
+	projectService := core.NewProjectService(logrus.New(), time.UTC, repositories.Projects, repositories.Tasks)
+
+	work, err := projectService.CreateProject(ctx, core.ProjectRequest{Name: " work "})
+	if err != nil || work.Name != "work" {
+		t.Fatalf("unexpected project %+v %v", work, err)
+	}
+	home, _ := projectService.CreateProject(ctx, core.ProjectRequest{Name: "home"})
+	if _, err := projectService.CreateProject(ctx, core.ProjectRequest{Name: "work"}); err != exception.ErrConflict {
+		t.Errorf("expected a taken name, got %v", err)
+	}
+
+	done := entity.TaskStatusDone
+	report, _ := taskService.CreateTask(ctx, core.TaskRequest{Name: "report", ProjectID: &work.ID})
+	review, _ := taskService.CreateTask(ctx, core.TaskRequest{Name: "review", ProjectID: &work.ID})
+	taskService.UpdateTask(ctx, review.ID, core.TaskRequest{Name: "review", Status: &done, ProjectID: &work.ID})
+	taskService.CreateTask(ctx, core.TaskRequest{Name: "laundry", ProjectID: &home.ID})
+	taskService.CreateTask(ctx, core.TaskRequest{Name: "inbox"})
+	missing := int64(42)
+	if _, err := taskService.CreateTask(ctx, core.TaskRequest{Name: "lost", ProjectID: &missing}); err != core.ErrUnknownProject {
+		t.Errorf("expected an unknown project, got %v", err)
+	}
+
+	projects, counts, err := projectService.GetManyProjects(ctx, core.ProjectFilter{})
+	if err != nil || len(projects) != 2 || projects[0].ID != home.ID {
+		t.Fatalf("unexpected projects %+v %v", projects, err)
+	}
+	if counts[work.ID] != (entity.StatusCounts{Initiate: 1, Done: 1}) || counts[home.ID] != (entity.StatusCounts{Initiate: 1}) {
+		t.Errorf("unexpected counts %+v", counts)
+	}
+
+	count := func(filter core.Filter) int {
+		tasks, err := taskService.GetManyTasks(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(tasks)
+	}
+	if got := count(core.Filter{ProjectID: &work.ID}); got != 2 {
+		t.Errorf("expected the tasks of the project, got %d", got)
+	}
+
+	// the tasks of an archived project are only listed by project.
+	if _, err := projectService.ArchiveProject(ctx, work.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := count(core.Filter{}); got != 2 {
+		t.Errorf("expected the tasks of the archived project to be hidden, got %d", got)
+	}
+	if got := count(core.Filter{ProjectID: &work.ID}); got != 2 {
+		t.Errorf("expected the tasks of the archived project by project, got %d", got)
+	}
+	archived := true
+	if projects, _, _ := projectService.GetManyProjects(ctx, core.ProjectFilter{Archived: &archived}); len(projects) != 1 || projects[0].ID != work.ID {
+		t.Errorf("expected the archived project, got %+v", projects)
+	}
+	if _, err := projectService.ArchiveProject(ctx, work.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := count(core.Filter{}); got != 4 {
+		t.Errorf("expected the tasks of the restored project, got %d", got)
+	}
+
+	if err := projectService.DeleteProject(ctx, work.ID); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := taskService.GetOneTask(ctx, report.ID); task.ProjectID != nil {
+		t.Errorf("expected the task to leave the deleted project, got %+v", task)
+	}
+}
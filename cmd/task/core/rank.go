@@ -0,0 +1,219 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/rank"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MoveTask places the task between its new neighbours in the manual order, only the rank of the task is written.
+func (s *taskService) MoveTask(ctx context.Context, id int64, moveRequest MoveRequest) (task entity.Task, err error) {
+	between, err := s.moveRank(ctx, id, moveRequest, nil)
+	if err != nil {
+		return
+	}
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+	if err = s.taskRepository.UpdateRank(ctx, id, between, nil); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	task.Rank = between
+	return
+}
+
+// moveRank returns the rank of the task between the neighbours of the move, the neighbours must have the status when it is set.
+func (s *taskService) moveRank(ctx context.Context, id int64, moveRequest MoveRequest, status *int) (between string, err error) {
+	if moveRequest.AfterID == nil && moveRequest.BeforeID == nil {
+		return "", fmt.Errorf("%w: a neighbour is required", ErrInvalidMove)
+	}
+	if sameID(&id, moveRequest.AfterID) || sameID(&id, moveRequest.BeforeID) {
+		return "", fmt.Errorf("%w: a task can't be its own neighbour", ErrInvalidMove)
+	}
+
+	lower, err := s.neighbourRank(ctx, moveRequest.AfterID, status)
+	if err != nil {
+		return
+	}
+	upper, err := s.neighbourRank(ctx, moveRequest.BeforeID, status)
+	if err != nil {
+		return
+	}
+	if moveRequest.BeforeID == nil {
+		upper, err = s.taskRepository.FindAdjacentRank(ctx, lower, true)
+	} else if moveRequest.AfterID == nil {
+		lower, err = s.taskRepository.FindAdjacentRank(ctx, upper, false)
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return "", exception.ErrInternalServer
+	}
+
+	// the moved task can be the adjacent one, a rank between its old rank and the neighbour is still right.
+	// The adjacent task can be in another column of the board, a column keeps the manual order either way.
+	if between, err = rank.Between(lower, upper); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidMove, err)
+	}
+	return
+}
+
+// neighbourRank returns the rank of a neighbour of a move, ErrInvalidMove is returned when it doesn't exist
+// or when it doesn't have the status.
+func (s *taskService) neighbourRank(ctx context.Context, id *int64, status *int) (neighbourRank string, err error) {
+	if id == nil {
+		return
+	}
+
+	neighbour, err := s.taskRepository.FindOneById(ctx, *id)
+	if err == exception.ErrNotFound {
+		return "", fmt.Errorf("%w: the neighbour doesn't exist", ErrInvalidMove)
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return "", exception.ErrInternalServer
+	}
+	if status != nil && neighbour.Status != *status {
+		return "", fmt.Errorf("%w: the neighbour is in another column", ErrInvalidMove)
+	}
+	return neighbour.Rank, nil
+}
+
+// appendRank returns the rank of a task that goes at the end of the manual order.
+func (s *taskService) appendRank(ctx context.Context) (appended string, err error) {
+	last, err := s.taskRepository.FindLastRank(ctx)
+	if err != nil {
+		return
+	}
+	return rank.Between(last, "")
+}
+
+// RankOptions are the settings of a RankBalancer.
+type RankOptions struct {
+	// MaxLength is the length of a rank above which the ranks are spread again.
+	MaxLength int
+	// LockTTL keeps the other replicas from rebalancing in the meantime.
+	LockTTL time.Duration
+}
+
+// RankBalancer spreads the ranks of the tasks evenly when they grow too long, when two tasks share
+// a rank, or when a task has none. Every replica runs it, the lock lets a single replica rebalance.
+type RankBalancer struct {
+	logger         *logrus.Logger
+	taskRepository TaskRepository
+	locker         lock.Locker
+	options        RankOptions
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewRankBalancer is a constructor.
+func NewRankBalancer(logger *logrus.Logger, taskRepository TaskRepository, locker lock.Locker, options RankOptions) *RankBalancer {
+	if options.MaxLength <= 0 {
+		options.MaxLength = 16
+	}
+	if options.LockTTL <= 0 {
+		options.LockTTL = time.Minute * 5
+	}
+	return &RankBalancer{
+		logger:         logger,
+		taskRepository: taskRepository,
+		locker:         locker,
+		options:        options,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start rebalances the ranks on every interval until Close is called.
+func (b *RankBalancer) Start(interval time.Duration) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stop:
+				return
+			case <-ticker.C:
+				rebalanced, err := b.Run(context.Background())
+				if err != nil {
+					b.logger.Errorf("failed to rebalance the task ranks: %v", err)
+					continue
+				}
+				if rebalanced > 0 {
+					b.logger.WithField("rank.rebalanced", rebalanced).Info("task ranks are rebalanced")
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the balancer.
+func (b *RankBalancer) Close() {
+	close(b.stop)
+	b.wg.Wait()
+}
+
+// Run rebalances the ranks once when they need it, the tasks keep their order. It returns the number of ranks
+// that are written, in a single transaction.
+func (b *RankBalancer) Run(ctx context.Context) (rebalanced int, err error) {
+	token, acquired, err := b.locker.Acquire(ctx, "rank:rebalance", b.options.LockTTL)
+	if err != nil || !acquired {
+		return
+	}
+	defer func() {
+		if err := b.locker.Release(ctx, "rank:rebalance", token); err != nil {
+			b.logger.WithContext(ctx).Error(err)
+		}
+	}()
+
+	tasks, err := b.taskRepository.FindMany(ctx, Filter{Sort: SortManual})
+	if err != nil || !b.unbalanced(tasks) {
+		return
+	}
+
+	tx, err := b.taskRepository.BeginTx(ctx)
+	if err != nil {
+		return
+	}
+	for i, spread := range rank.Spread(len(tasks)) {
+		if tasks[i].Rank == spread {
+			continue
+		}
+		if err = b.taskRepository.UpdateRank(ctx, tasks[i].ID, spread, tx); err != nil {
+			if err := b.taskRepository.RollbackTx(ctx, tx); err != nil {
+				b.logger.WithContext(ctx).Error(err)
+			}
+			return 0, err
+		}
+		rebalanced++
+	}
+	if err = b.taskRepository.CommitTx(ctx, tx); err != nil {
+		return 0, err
+	}
+	return
+}
+
+// unbalanced tells whether a rank is too long, missing or shared, the tasks are in the manual order.
+func (b *RankBalancer) unbalanced(tasks []entity.Task) bool {
+	for i, task := range tasks {
+		if task.Rank == "" || len(task.Rank) > b.options.MaxLength || rank.Validate(task.Rank) != nil {
+			return true
+		}
+		if i > 0 && tasks[i-1].Rank == task.Rank {
+			return true
+		}
+	}
+	return false
+}
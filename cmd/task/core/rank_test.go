@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/pkg/lock"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMoveTask(t *testing.T) {
+	ctx := context.Background()
+	repositories := newTestRepositories()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, core.TaskOptions{})
+
+	var ids []int64
+	for _, name := range []string{"a", "b", "c", "d"} {
+		task, _ := service.CreateTask(ctx, core.TaskRequest{Name: name})
+		ids = append(ids, task.ID)
+	}
+	order := func() (names string) {
+		tasks, err := service.GetManyTasks(ctx, core.Filter{Sort: core.SortManual})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tasks {
+			names += task.Name
+		}
+		return
+	}
+	if got := order(); got != "abcd" {
+		t.Fatalf("expected the creation order, got %s", got)
+	}
+
+	for _, test := range []struct {
+		id   int64
+		move core.MoveRequest
+		want string
+	}{
+		{ids[3], core.MoveRequest{BeforeID: &ids[0]}, "dabc"},
+		{ids[0], core.MoveRequest{AfterID: &ids[2]}, "dbca"},
+		{ids[2], core.MoveRequest{AfterID: &ids[3], BeforeID: &ids[1]}, "dcba"},
+		{ids[3], core.MoveRequest{AfterID: &ids[0]}, "cbad"},
+	} {
+		if _, err := service.MoveTask(ctx, test.id, test.move); err != nil {
+			t.Fatal(err)
+		}
+		if got := order(); got != test.want {
+			t.Errorf("expected %s, got %s", test.want, got)
+		}
+	}
+
+	missing := int64(42)
+	for _, move := range []core.MoveRequest{{}, {AfterID: &ids[0]}, {AfterID: &missing}, {AfterID: &ids[3], BeforeID: &ids[2]}} {
+		if _, err := service.MoveTask(ctx, ids[0], move); !errors.Is(err, core.ErrInvalidMove) {
+			t.Errorf("move %+v: expected an invalid move, got %v", move, err)
+		}
+	}
+
+	// the moves to the top make the ranks grow, the balancer spreads them again in the same order.
+	for i := 0; i < 40; i++ {
+		id := ids[i%2]
+		tasks, _ := service.GetManyTasks(ctx, core.Filter{Sort: core.SortManual})
+		if tasks[0].ID != id {
+			service.MoveTask(ctx, id, core.MoveRequest{BeforeID: &tasks[0].ID})
+		}
+	}
+	before := order()
+	balancer := core.NewRankBalancer(logrus.New(), repositories.Tasks, lock.NewMemoryLocker(), core.RankOptions{MaxLength: 4})
+	rebalanced, err := balancer.Run(ctx)
+	if err != nil || rebalanced == 0 {
+		t.Fatalf("expected the ranks to be rebalanced, got %d %v", rebalanced, err)
+	}
+	if got := order(); got != before {
+		t.Errorf("expected the order %s to be kept, got %s", before, got)
+	}
+	if rebalanced, _ := balancer.Run(ctx); rebalanced != 0 {
+		t.Errorf("expected balanced ranks, got %d rebalanced", rebalanced)
+	}
+}
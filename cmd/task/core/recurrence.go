@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/rrule"
+)
+
+// occurrenceUpdate is a pending occurrence of a series and the request that rewrites it.
+type occurrenceUpdate struct {
+	task    entity.Task
+	request TaskRequest
+}
+
+// UpdateSeries replaces the fields of an occurrence like UpdateTask, and carries its name, description, priority,
+// labels and rule over to the pending occurrences of the series. A new rule restarts the series at the occurrence, no rule ends it.
+func (s *taskService) UpdateSeries(ctx context.Context, id int64, taskRequest TaskRequest) (task entity.Task, err error) {
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+	if task.SeriesID == nil {
+		return s.UpdateTask(ctx, id, taskRequest)
+	}
+
+	taskRequest = s.inLocations(taskRequest)
+	taskRequest.Rank = ""
+	taskRequest.Assignees, taskRequest.Watchers = task.Assignees, task.Watchers
+	taskRequest.SeriesID, taskRequest.SeriesStart, taskRequest.Occurrence = task.SeriesID, task.SeriesStart, task.Occurrence
+	if taskRequest.Recurrence != nil {
+		previous := task.Recurrence
+		if err = s.startSeries(&taskRequest); err != nil {
+			return
+		}
+		if previous != nil && *previous == *taskRequest.Recurrence {
+			taskRequest.SeriesStart, taskRequest.Occurrence = task.SeriesStart, task.Occurrence
+		}
+	}
+
+	pending, err := s.taskRepository.FindMany(ctx, Filter{SeriesID: task.SeriesID, Pending: true})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	occurrences := make([]occurrenceUpdate, 0, len(pending))
+	for _, occurrence := range pending {
+		if occurrence.ID == id {
+			continue
+		}
+		occurrenceRequest := requestOf(occurrence)
+		occurrenceRequest.Name = taskRequest.Name
+		occurrenceRequest.Description = taskRequest.Description
+		occurrenceRequest.Priority = taskRequest.Priority
+		occurrenceRequest.LabelIDs = taskRequest.LabelIDs
+		occurrenceRequest.Recurrence = taskRequest.Recurrence
+		occurrenceRequest.SeriesStart = taskRequest.SeriesStart
+		// a restarted series keeps the distance of the later occurrences.
+		occurrenceRequest.Occurrence = occurrence.Occurrence - task.Occurrence + taskRequest.Occurrence
+		occurrences = append(occurrences, occurrenceUpdate{task: occurrence, request: occurrenceRequest})
+	}
+
+	return s.update(ctx, task, taskRequest, occurrences)
+}
+
+// startSeries makes the task the first occurrence of a series that starts at its due date, the rule is stored canonical.
+// The caller sets the series id.
+func (s *taskService) startSeries(taskRequest *TaskRequest) (err error) {
+	if taskRequest.DueAt == nil {
+		return ErrInvalidRecurrence
+	}
+	rule, err := rrule.Parse(*taskRequest.Recurrence, s.location)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	recurrence := rule.String()
+	taskRequest.Recurrence = &recurrence
+	taskRequest.SeriesStart = taskRequest.DueAt
+	taskRequest.Occurrence = 1
+	return
+}
+
+// saveTask saves the task and its activity in a transaction, the first occurrence of a series is its own series.
+func (s *taskService) saveTask(ctx context.Context, taskRequest TaskRequest) (id int64, err error) {
+	tx, err := s.taskRepository.BeginTx(ctx)
+	if err != nil {
+		return
+	}
+	if id, err = s.taskRepository.Save(ctx, taskRequest, tx); err == nil && taskRequest.Recurrence != nil {
+		taskRequest.SeriesID = &id
+		err = s.taskRepository.UpdateById(ctx, id, taskRequest, tx)
+	}
+	if err == nil {
+		err = s.record(ctx, tx, nil, taskOf(id, taskRequest))
+	}
+	if err != nil {
+		if err := s.taskRepository.RollbackTx(ctx, tx); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+		return
+	}
+	err = s.taskRepository.CommitTx(ctx, tx)
+	return
+}
+
+// nextOccurrence returns the occurrence that follows a recurring task that is being completed. The due date
+// follows the schedule of the series, so an occurrence that was moved on its own doesn't shift the next one.
+func (s *taskService) nextOccurrence(ctx context.Context, task entity.Task, taskRequest TaskRequest) (next TaskRequest, ok bool, err error) {
+	if task.Status == entity.TaskStatusDone || intValue(taskRequest.Status) != entity.TaskStatusDone {
+		return
+	}
+	if taskRequest.Recurrence == nil || taskRequest.SeriesStart == nil {
+		return
+	}
+
+	rule, err := rrule.Parse(*taskRequest.Recurrence, s.location)
+	if err != nil {
+		return
+	}
+	dueAt, ok := rule.Nth(taskRequest.SeriesStart.In(s.location), taskRequest.Occurrence+1)
+	if !ok {
+		return
+	}
+
+	// the next occurrence exists already when the task is completed again.
+	series, err := s.taskRepository.FindMany(ctx, Filter{SeriesID: taskRequest.SeriesID})
+	if err != nil {
+		return next, false, err
+	}
+	for _, occurrence := range series {
+		if occurrence.Occurrence > taskRequest.Occurrence {
+			return next, false, nil
+		}
+	}
+
+	status := entity.TaskStatusInitiate
+	next = TaskRequest{
+		Name:        taskRequest.Name,
+		Description: taskRequest.Description,
+		Status:      &status,
+		Priority:    taskRequest.Priority,
+		DueAt:       &dueAt,
+		Recurrence:  taskRequest.Recurrence,
+		SeriesID:    taskRequest.SeriesID,
+		SeriesStart: taskRequest.SeriesStart,
+		Occurrence:  taskRequest.Occurrence + 1,
+		LabelIDs:    taskRequest.LabelIDs,
+		Assignees:   taskRequest.Assignees,
+		Watchers:    taskRequest.Watchers,
+		ParentID:    taskRequest.ParentID,
+		ProjectID:   taskRequest.ProjectID,
+		CreatedAt:   time.Now().In(s.location),
+	}
+	if taskRequest.RemindAt != nil && taskRequest.DueAt != nil {
+		remindAt := dueAt.Add(taskRequest.RemindAt.Sub(*taskRequest.DueAt))
+		next.RemindAt = &remindAt
+	}
+	if next.Rank, err = s.appendRank(ctx); err != nil {
+		return next, false, err
+	}
+	return next, true, nil
+}
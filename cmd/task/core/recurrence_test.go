@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRecurringTask(t *testing.T) {
+	ctx := context.Background()
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	service := core.NewTaskService(logrus.New(), jakarta, nil, nil, newTestRepositories(), core.TaskOptions{})
+
+	// a monday at 08:00 in jakarta, sent in utc.
+	dueAt := time.Date(2024, 5, 6, 1, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+	recurrence := "freq=weekly;byday=mo;count=3"
+	created, err := service.CreateTask(ctx, core.TaskRequest{Name: "bins", DueAt: &dueAt, RemindAt: &remindAt, Recurrence: &recurrence})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *created.Recurrence != "FREQ=WEEKLY;BYDAY=MO;COUNT=3" || *created.SeriesID != created.ID || created.Occurrence != 1 {
+		t.Fatalf("expected the first occurrence of a series, got %+v", created)
+	}
+
+	// moving an occurrence on its own doesn't shift the series.
+	done, moved := entity.TaskStatusDone, dueAt.Add(time.Hour*24)
+	if _, err := service.UpdateTask(ctx, created.ID, core.TaskRequest{Name: "bins", Status: &done, DueAt: &moved, RemindAt: &remindAt}); err != nil {
+		t.Fatal(err)
+	}
+	initiate := entity.TaskStatusInitiate
+	service.UpdateTask(ctx, created.ID, core.TaskRequest{Name: "bins", Status: &initiate, DueAt: &moved})
+	service.UpdateTask(ctx, created.ID, core.TaskRequest{Name: "bins", Status: &done, DueAt: &moved})
+
+	series, _ := service.GetManyTasks(ctx, core.Filter{SeriesID: created.SeriesID})
+	if len(series) != 2 {
+		t.Fatalf("expected a single next occurrence, got %+v", series)
+	}
+	next := series[1]
+	if next.Occurrence != 2 || next.Status != entity.TaskStatusInitiate || next.DueAt.In(jakarta).Format("2006-01-02 15:04 Mon") != "2024-05-13 08:00 Mon" {
+		t.Errorf("unexpected next occurrence %+v", next)
+	}
+	if next.RemindAt == nil || next.DueAt.Sub(*next.RemindAt) != time.Hour*24+time.Hour {
+		t.Errorf("expected the reminder to keep its distance to the due date, got %v", next.RemindAt)
+	}
+
+	// a new rule restarts the series at the occurrence.
+	daily := "FREQ=DAILY;COUNT=2"
+	updated, err := service.UpdateSeries(ctx, next.ID, core.TaskRequest{Name: "bins out", DueAt: next.DueAt, Recurrence: &daily})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updated.Recurrence != daily || updated.Occurrence != 1 || !updated.SeriesStart.Equal(*next.DueAt) {
+		t.Errorf("expected the series to restart, got %+v", updated)
+	}
+
+	service.UpdateTask(ctx, next.ID, core.TaskRequest{Name: "bins out", Status: &done, DueAt: next.DueAt})
+	series, _ = service.GetManyTasks(ctx, core.Filter{SeriesID: created.SeriesID, Pending: true})
+	if len(series) != 1 || series[0].DueAt.In(jakarta).Format("2006-01-02 15:04") != "2024-05-14 08:00" {
+		t.Fatalf("expected the next daily occurrence, got %+v", series)
+	}
+
+	// the last occurrence of the count ends the series.
+	service.UpdateTask(ctx, series[0].ID, core.TaskRequest{Name: "bins out", Status: &done, DueAt: series[0].DueAt})
+	if pending, _ := service.GetManyTasks(ctx, core.Filter{SeriesID: created.SeriesID, Pending: true}); len(pending) != 0 {
+		t.Errorf("expected the series to end, got %+v", pending)
+	}
+}
+
+func TestInvalidRecurrence(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	dueAt, yearly, weekly := time.Now(), "FREQ=YEARLY", "FREQ=WEEKLY"
+	if _, err := service.CreateTask(ctx, core.TaskRequest{Name: "taxes", DueAt: &dueAt, Recurrence: &yearly}); !errors.Is(err, core.ErrInvalidRecurrence) {
+		t.Errorf("expected an unsupported rule, got %v", err)
+	}
+	if _, err := service.CreateTask(ctx, core.TaskRequest{Name: "laundry", Recurrence: &weekly}); !errors.Is(err, core.ErrInvalidRecurrence) {
+		t.Errorf("expected a recurring task to need a due date, got %v", err)
+	}
+}
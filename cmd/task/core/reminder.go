@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReminderOptions are the settings of a ReminderScheduler.
+type ReminderOptions struct {
+	// LockTTL is how long a reminder stays locked, it outlives the lag of a replica so a sent reminder is not read back as due.
+	LockTTL   time.Duration
+	BatchSize int
+}
+
+// ReminderScheduler sends the reminders that are due. Every replica runs it,
+// the lock of a reminder lets a single replica send it.
+type ReminderScheduler struct {
+	logger         *logrus.Logger
+	location       *time.Location
+	taskRepository TaskRepository
+	notifier       notifier.Notifier
+	locker         lock.Locker
+	options        ReminderOptions
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewReminderScheduler is a constructor.
+func NewReminderScheduler(logger *logrus.Logger, location *time.Location, taskRepository TaskRepository, notifier notifier.Notifier, locker lock.Locker, options ReminderOptions) *ReminderScheduler {
+	if options.LockTTL <= 0 {
+		options.LockTTL = time.Minute * 10
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	return &ReminderScheduler{
+		logger:         logger,
+		location:       location,
+		taskRepository: taskRepository,
+		notifier:       notifier,
+		locker:         locker,
+		options:        options,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start sends the due reminders on every interval until Close is called.
+func (s *ReminderScheduler) Start(interval time.Duration) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				sent, err := s.Run(context.Background())
+				if err != nil {
+					s.logger.Errorf("failed to send reminders: %v", err)
+					continue
+				}
+				if sent > 0 {
+					s.logger.WithField("reminder.sent", sent).Info("reminders are sent")
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the scheduler.
+func (s *ReminderScheduler) Close() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// Run sends the reminders that are due once, a reminder that fails to send is retried on the next run.
+func (s *ReminderScheduler) Run(ctx context.Context) (sent int, err error) {
+	now := time.Now().In(s.location)
+
+	tasks, err := s.taskRepository.FindDueReminders(ctx, now, s.options.BatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, task := range tasks {
+		// the reminder time is part of the key, so a reminder that moves is locked anew.
+		key := fmt.Sprintf("reminder:%d:%d", task.ID, task.RemindAt.Unix())
+		token, acquired, err := s.locker.Acquire(ctx, key, s.options.LockTTL)
+		if err != nil {
+			return sent, err
+		}
+		if !acquired {
+			continue
+		}
+
+		data := map[string]string{
+			"task.id":        strconv.FormatInt(task.ID, 10),
+			"task.remind_at": task.RemindAt.In(s.location).Format(time.RFC3339),
+		}
+		message := fmt.Sprintf("reminder: %s", task.Name)
+		if task.DueAt != nil {
+			data["task.due_at"] = task.DueAt.In(s.location).Format(time.RFC3339)
+			message = fmt.Sprintf("reminder: %s is due at %s", task.Name, task.DueAt.In(s.location).Format("2006-01-02 15:04"))
+		}
+
+		// the tasks have no owner, the notification goes to the default recipient of the notifier.
+		if err := s.notifier.Notify(ctx, notifier.Notification{Subject: "Task reminder", Message: message, Data: data}); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+			if err := s.locker.Release(ctx, key, token); err != nil {
+				s.logger.WithContext(ctx).Error(err)
+			}
+			continue
+		}
+
+		if err := s.taskRepository.MarkReminded(ctx, task.ID, now); err != nil {
+			// the lock keeps the other replicas from sending it again until it expires.
+			s.logger.WithContext(ctx).Error(err)
+			continue
+		}
+		sent++
+	}
+	return
+}
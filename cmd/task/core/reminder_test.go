@@ -0,0 +1,61 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fakeNotifier struct {
+	notifications []notifier.Notification
+	err           error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, notification notifier.Notification) (err error) {
+	if n.err != nil {
+		return n.err
+	}
+	n.notifications = append(n.notifications, notification)
+	return
+}
+
+func TestReminderScheduler(t *testing.T) {
+	ctx := context.Background()
+	service, repository := newTestService()
+
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	service.CreateTask(ctx, core.TaskRequest{Name: "call mom", DueAt: &future, RemindAt: &past})
+	service.CreateTask(ctx, core.TaskRequest{Name: "not yet", RemindAt: &future})
+
+	locker := lock.NewMemoryLocker()
+	failing := &fakeNotifier{err: errors.New("unreachable")}
+	if sent, err := core.NewReminderScheduler(logrus.New(), time.UTC, repository, failing, locker, core.ReminderOptions{}).Run(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected nothing to be sent, got %d %v", sent, err)
+	}
+
+	// two replicas, the lock lets a single one send the reminder.
+	first, second := &fakeNotifier{}, &fakeNotifier{}
+	core.NewReminderScheduler(logrus.New(), time.UTC, repository, first, locker, core.ReminderOptions{}).Run(ctx)
+	core.NewReminderScheduler(logrus.New(), time.UTC, repository, second, locker, core.ReminderOptions{}).Run(ctx)
+	if len(first.notifications) != 1 || len(second.notifications) != 0 || first.notifications[0].Data["task.id"] != "1" {
+		t.Fatalf("expected a single reminder, got %v and %v", first.notifications, second.notifications)
+	}
+
+	task, _ := service.GetOneTask(ctx, 1)
+	if task.RemindedAt == nil {
+		t.Fatal("expected the reminder to be marked")
+	}
+
+	// moving the reminder sends it again.
+	moved := time.Now().Add(-time.Second)
+	service.UpdateTask(ctx, 1, core.TaskRequest{Name: "call mom", DueAt: &future, RemindAt: &moved})
+	if sent, _ := core.NewReminderScheduler(logrus.New(), time.UTC, repository, first, locker, core.ReminderOptions{}).Run(ctx); sent != 1 {
+		t.Errorf("expected the moved reminder to be sent, got %d", sent)
+	}
+}
@@ -0,0 +1,799 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+type TaskRepository interface {
+	BeginTx(ctx context.Context) (tx database.Tx, err error)
+	RollbackTx(ctx context.Context, tx database.Tx) (err error)
+	CommitTx(ctx context.Context, tx database.Tx) (err error)
+	Save(ctx context.Context, task TaskRequest, tx database.Tx) (id int64, err error)
+	UpdateById(ctx context.Context, id int64, task TaskRequest, tx database.Tx) (err error)
+	FindMany(ctx context.Context, filter Filter) (bunchOfTasks []entity.Task, err error)
+	FindOneById(ctx context.Context, id int64) (task entity.Task, err error)
+	// Count returns the number of tasks of the filter, the paging of the filter is ignored.
+	Count(ctx context.Context, filter Filter) (count int, err error)
+	FindAttachments(ctx context.Context) (attachments []string, err error)
+	// FindDueReminders returns the pending tasks whose reminder is due by until and was not sent yet, the earliest first.
+	FindDueReminders(ctx context.Context, until time.Time, limit int) (bunchOfTasks []entity.Task, err error)
+	// MarkReminded records that the reminder of the task is sent, a reminder that is marked already is left as is.
+	MarkReminded(ctx context.Context, id int64, remindedAt time.Time) (err error)
+	// RemoveLabel takes a deleted label off every task.
+	RemoveLabel(ctx context.Context, labelID int64) (err error)
+	// RemoveProject takes the tasks out of a deleted project.
+	RemoveProject(ctx context.Context, projectID int64) (err error)
+	// CountByProjectIds returns the number of tasks of the projects by status, a project without tasks is left out.
+	CountByProjectIds(ctx context.Context, projectIDs []int64) (counts map[int64]entity.StatusCounts, err error)
+	// FindLastRank returns the greatest rank, an empty rank when there is no task.
+	FindLastRank(ctx context.Context) (rank string, err error)
+	// FindAdjacentRank returns the closest rank after the rank, or before it, an empty rank when there is none.
+	FindAdjacentRank(ctx context.Context, rank string, after bool) (adjacent string, err error)
+	// UpdateRank moves the task in the manual order, it writes the rank only.
+	UpdateRank(ctx context.Context, id int64, rank string, tx database.Tx) (err error)
+}
+
+// taskColumns are the columns scanned by query, in order.
+const taskColumns = "t.id, t.name, t.description, t.status, t.priority, t.attachment, t.due_at, t.remind_at, t.reminded_at, t.recurrence, t.series_id, t.series_start, t.occurrence, t.parent_id, t.project_id, t.rank_key, t.created_at, t.updated_at"
+
+type sqlCommand interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type taskRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewTaskRepository is a constructor
+func NewTaskRepository(logger *logrus.Logger, db *database.Router, tableName string) TaskRepository {
+	return &taskRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+// BeginTx returns sql trx for global scope.
+func (r *taskRepository) BeginTx(ctx context.Context) (tx database.Tx, err error) {
+	sqlTx, err := r.db.Writer(ctx).BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	return sqlTx, nil
+}
+
+// CommitTx will commit the transaction that has began.
+func (r *taskRepository) CommitTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Commit()
+}
+
+// RollbackTx will rollback the transaction to achieve the consistency.
+func (r *taskRepository) RollbackTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r *taskRepository) FindMany(ctx context.Context, filter Filter) (bunchOfTasks []entity.Task, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+	stmt := r.where(r.builder.Select(taskColumns).From(fmt.Sprintf("%s t", r.tableName)), filter)
+	if filter.After != nil {
+		stmt = stmt.Where(sq.Or{
+			sq.Gt{"t.rank_key": filter.After.Rank},
+			sq.And{sq.Eq{"t.rank_key": filter.After.Rank}, sq.Gt{"t.id": filter.After.ID}},
+		})
+	}
+	if filter.Limit > 0 {
+		stmt = stmt.Limit(uint64(filter.Limit))
+	}
+
+	switch filter.Sort {
+	case SortPriority:
+		stmt = stmt.OrderBy("t.priority", "t.id")
+	case SortPriorityDesc:
+		stmt = stmt.OrderBy("t.priority DESC", "t.id")
+	case SortManual:
+		stmt = stmt.OrderBy("t.rank_key", "t.id")
+	default:
+		stmt = stmt.OrderBy("t.id")
+	}
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	bunchOfTasks, err = r.query(ctx, cmd, sql, args...)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if err = r.loadRelations(ctx, cmd, bunchOfTasks); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// Count returns the number of tasks of the filter.
+func (r *taskRepository) Count(ctx context.Context, filter Filter) (count int, err error) {
+	sql, args, err := r.where(r.builder.Select("COUNT(*)").From(r.tableName+" t"), filter).ToSql()
+	if err != nil {
+		return
+	}
+
+	if err = r.db.Reader(ctx).QueryRowContext(ctx, sql, args...).Scan(&count); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// where narrows the statement down to the tasks of the filter, the paging and the sort are left to the caller.
+func (r *taskRepository) where(stmt sq.SelectBuilder, filter Filter) sq.SelectBuilder {
+	if filter.IDs != nil {
+		stmt = stmt.Where(sq.Eq{"t.id": filter.IDs})
+	}
+
+	if filter.Name != nil {
+		stmt = stmt.Where(sq.Eq{"t.name": filter.Name})
+	}
+
+	if filter.Attachment != nil {
+		stmt = stmt.Where(sq.Eq{"t.attachment": filter.Attachment})
+	}
+
+	if filter.DueFrom != nil {
+		stmt = stmt.Where(sq.GtOrEq{"t.due_at": filter.DueFrom})
+	}
+
+	if filter.DueUntil != nil {
+		stmt = stmt.Where(sq.Lt{"t.due_at": filter.DueUntil})
+	}
+
+	if len(filter.Statuses) > 0 {
+		stmt = stmt.Where(sq.Eq{"t.status": filter.Statuses})
+	}
+
+	if filter.Pending {
+		stmt = stmt.Where(sq.NotEq{"t.status": entity.TaskStatusDone})
+	}
+
+	if filter.SeriesID != nil {
+		stmt = stmt.Where(sq.Eq{"t.series_id": filter.SeriesID})
+	}
+
+	if filter.ParentID != nil {
+		stmt = stmt.Where(sq.Eq{"t.parent_id": filter.ParentID})
+	}
+
+	if filter.ProjectID != nil {
+		stmt = stmt.Where(sq.Eq{"t.project_id": filter.ProjectID})
+	}
+
+	if len(filter.ExcludedProjectIDs) > 0 {
+		stmt = stmt.Where(sq.Or{sq.Eq{"t.project_id": nil}, sq.NotEq{"t.project_id": filter.ExcludedProjectIDs}})
+	}
+
+	if len(filter.Priorities) > 0 {
+		stmt = stmt.Where(sq.Eq{"t.priority": filter.Priorities})
+	}
+
+	if len(filter.LabelIDs) > 0 {
+		labeled := sq.Select("tl.task_id").From(r.labelTableName() + " tl").Where(sq.Eq{"tl.label_id": filter.LabelIDs})
+		if filter.AllLabels {
+			labeled = labeled.GroupBy("tl.task_id").Having("COUNT(DISTINCT tl.label_id) = ?", len(uniqueIDs(filter.LabelIDs)))
+		}
+		stmt = stmt.Where(sq.Expr("t.id IN (?)", labeled))
+	}
+
+	if filter.Assignee != nil {
+		stmt = stmt.Where(sq.Expr("t.id IN (?)", sq.Select("ta.task_id").From(r.assigneeTableName()+" ta").Where(sq.Eq{"ta.user_uuid": filter.Assignee})))
+	}
+
+	if filter.Unassigned {
+		stmt = stmt.Where(sq.Expr("t.id NOT IN (?)", sq.Select("ta.task_id").From(r.assigneeTableName()+" ta")))
+	}
+	return stmt
+}
+
+func (r *taskRepository) FindOneById(ctx context.Context, id int64) (task entity.Task, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := r.builder.Select(taskColumns).From(fmt.Sprintf("%s t", r.tableName)).Where(sq.Eq{"t.id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	bunchOfTasks, err := r.query(ctx, cmd, stmt, args...)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	lengthOfTasks := len(bunchOfTasks)
+	if lengthOfTasks < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+
+	if err = r.loadRelations(ctx, cmd, bunchOfTasks); err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	task = bunchOfTasks[lengthOfTasks-1]
+	return
+}
+
+// FindAttachments returns every attachment that is linked to a task.
+func (r *taskRepository) FindAttachments(ctx context.Context) (attachments []string, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := r.builder.Select("DISTINCT t.attachment").From(fmt.Sprintf("%s t", r.tableName)).Where(sq.NotEq{"t.attachment": nil}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	attachments = make([]string, 0)
+	for rows.Next() {
+		var attachment string
+		if err = rows.Scan(&attachment); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// FindDueReminders reads from the primary, so a reminder that is marked already is not read back from a lagging replica.
+func (r *taskRepository) FindDueReminders(ctx context.Context, until time.Time, limit int) (bunchOfTasks []entity.Task, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Select(taskColumns).From(fmt.Sprintf("%s t", r.tableName)).
+		Where(sq.LtOrEq{"t.remind_at": until}).
+		Where(sq.Eq{"t.reminded_at": nil}).
+		Where(sq.NotEq{"t.status": entity.TaskStatusDone}).
+		OrderBy("t.remind_at", "t.id").
+		Limit(uint64(limit)).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if bunchOfTasks, err = r.query(ctx, cmd, stmt, args...); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *taskRepository) MarkReminded(ctx context.Context, id int64, remindedAt time.Time) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("reminded_at", remindedAt).
+		Where(sq.Eq{"id": id, "reminded_at": nil}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// RemoveLabel deletes the links of the label, the foreign key deletes them too along with the label.
+func (r *taskRepository) RemoveLabel(ctx context.Context, labelID int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.labelTableName()).Where(sq.Eq{"label_id": labelID}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// RemoveProject takes the tasks out of the project, the foreign key does it too along with the project.
+func (r *taskRepository) RemoveProject(ctx context.Context, projectID int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).Set("project_id", nil).Where(sq.Eq{"project_id": projectID}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *taskRepository) CountByProjectIds(ctx context.Context, projectIDs []int64) (counts map[int64]entity.StatusCounts, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	counts = make(map[int64]entity.StatusCounts)
+	if len(projectIDs) == 0 {
+		return
+	}
+
+	stmt, args, err := r.builder.Select("t.project_id, t.status, COUNT(*)").
+		From(r.tableName+" t").
+		Where(sq.Eq{"t.project_id": projectIDs}).
+		GroupBy("t.project_id", "t.status").ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID int64
+		var status, count int
+		if err = rows.Scan(&projectID, &status, &count); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		projectCounts := counts[projectID]
+		projectCounts.Add(status, count)
+		counts[projectID] = projectCounts
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *taskRepository) FindLastRank(ctx context.Context) (rank string, err error) {
+	return r.findRank(ctx, r.builder.Select("t.rank_key").From(r.tableName+" t").OrderBy("t.rank_key DESC").Limit(1))
+}
+
+func (r *taskRepository) FindAdjacentRank(ctx context.Context, rank string, after bool) (adjacent string, err error) {
+	stmt := r.builder.Select("t.rank_key").From(r.tableName + " t").Limit(1)
+	if after {
+		stmt = stmt.Where(sq.Gt{"t.rank_key": rank}).OrderBy("t.rank_key")
+	} else {
+		stmt = stmt.Where(sq.Lt{"t.rank_key": rank}).OrderBy("t.rank_key DESC")
+	}
+	return r.findRank(ctx, stmt)
+}
+
+func (r *taskRepository) findRank(ctx context.Context, builder sq.SelectBuilder) (rank string, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if err = cmd.QueryRowContext(ctx, stmt, args...).Scan(&rank); err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *taskRepository) UpdateRank(ctx context.Context, id int64, rank string, tx database.Tx) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+	if sqlTx, ok := tx.(*sql.Tx); ok {
+		cmd = sqlTx
+	}
+
+	stmt, args, err := r.builder.Update(r.tableName).Set("rank_key", rank).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// loadRelations sets the labels, the assignees and the watchers of the tasks.
+func (r *taskRepository) loadRelations(ctx context.Context, cmd sqlCommand, bunchOfTasks []entity.Task) (err error) {
+	if err = r.loadLabelIDs(ctx, cmd, bunchOfTasks); err != nil {
+		return
+	}
+	if err = r.loadUsers(ctx, cmd, r.assigneeTableName(), bunchOfTasks, func(task *entity.Task) *[]string { return &task.Assignees }); err != nil {
+		return
+	}
+	return r.loadUsers(ctx, cmd, r.watcherTableName(), bunchOfTasks, func(task *entity.Task) *[]string { return &task.Watchers })
+}
+
+// loadLabelIDs sets the labels of the tasks.
+func (r *taskRepository) loadLabelIDs(ctx context.Context, cmd sqlCommand, bunchOfTasks []entity.Task) (err error) {
+	if len(bunchOfTasks) == 0 {
+		return
+	}
+
+	ids := make([]int64, len(bunchOfTasks))
+	positions := make(map[int64]int, len(bunchOfTasks))
+	for i, task := range bunchOfTasks {
+		ids[i] = task.ID
+		positions[task.ID] = i
+		bunchOfTasks[i].LabelIDs = make([]int64, 0)
+	}
+
+	stmt, args, err := r.builder.Select("tl.task_id, tl.label_id").From(r.labelTableName() + " tl").Where(sq.Eq{"tl.task_id": ids}).OrderBy("tl.label_id").ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, labelID int64
+		if err = rows.Scan(&taskID, &labelID); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			return
+		}
+		position := positions[taskID]
+		bunchOfTasks[position].LabelIDs = append(bunchOfTasks[position].LabelIDs, labelID)
+	}
+	return rows.Err()
+}
+
+// loadUsers sets the users of the tasks that the table links, users returns the field of a task to set.
+func (r *taskRepository) loadUsers(ctx context.Context, cmd sqlCommand, tableName string, bunchOfTasks []entity.Task, users func(task *entity.Task) *[]string) (err error) {
+	if len(bunchOfTasks) == 0 {
+		return
+	}
+
+	ids := make([]int64, len(bunchOfTasks))
+	positions := make(map[int64]int, len(bunchOfTasks))
+	for i, task := range bunchOfTasks {
+		ids[i] = task.ID
+		positions[task.ID] = i
+		*users(&bunchOfTasks[i]) = make([]string, 0)
+	}
+
+	stmt, args, err := r.builder.Select("tu.task_id, tu.user_uuid").From(tableName + " tu").Where(sq.Eq{"tu.task_id": ids}).OrderBy("tu.user_uuid").ToSql()
+	if err != nil {
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int64
+		var userUUID string
+		if err = rows.Scan(&taskID, &userUUID); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			return
+		}
+		field := users(&bunchOfTasks[positions[taskID]])
+		*field = append(*field, userUUID)
+	}
+	return rows.Err()
+}
+
+// setUsers replaces the users of the task in the table.
+func (r *taskRepository) setUsers(ctx context.Context, cmd sqlCommand, tableName string, id int64, userUUIDs []string) (err error) {
+	stmt, args, err := r.builder.Delete(tableName).Where(sq.Eq{"task_id": id}).ToSql()
+	if err != nil {
+		return
+	}
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		return
+	}
+
+	userUUIDs = uniqueStrings(userUUIDs)
+	if len(userUUIDs) == 0 {
+		return
+	}
+
+	insert := r.builder.Insert(tableName).Columns("task_id", "user_uuid")
+	for _, userUUID := range userUUIDs {
+		insert = insert.Values(id, userUUID)
+	}
+	if stmt, args, err = insert.ToSql(); err != nil {
+		return
+	}
+	_, err = r.exec(ctx, cmd, stmt, args...)
+	return
+}
+
+// setRelations replaces the labels, the assignees and the watchers of the task.
+func (r *taskRepository) setRelations(ctx context.Context, cmd sqlCommand, id int64, task TaskRequest) (err error) {
+	if err = r.setLabels(ctx, cmd, id, task.LabelIDs); err != nil {
+		return
+	}
+	if err = r.setUsers(ctx, cmd, r.assigneeTableName(), id, task.Assignees); err != nil {
+		return
+	}
+	return r.setUsers(ctx, cmd, r.watcherTableName(), id, task.Watchers)
+}
+
+// setLabels replaces the labels of the task.
+func (r *taskRepository) setLabels(ctx context.Context, cmd sqlCommand, id int64, labelIDs []int64) (err error) {
+	stmt, args, err := r.builder.Delete(r.labelTableName()).Where(sq.Eq{"task_id": id}).ToSql()
+	if err != nil {
+		return
+	}
+	if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+		return
+	}
+
+	labelIDs = uniqueIDs(labelIDs)
+	if len(labelIDs) == 0 {
+		return
+	}
+
+	insert := r.builder.Insert(r.labelTableName()).Columns("task_id", "label_id")
+	for _, labelID := range labelIDs {
+		insert = insert.Values(id, labelID)
+	}
+	if stmt, args, err = insert.ToSql(); err != nil {
+		return
+	}
+	_, err = r.exec(ctx, cmd, stmt, args...)
+	return
+}
+
+// inTx runs fn on the transaction, or on a transaction of its own when there is none.
+func (r *taskRepository) inTx(ctx context.Context, tx database.Tx, fn func(cmd sqlCommand) error) (err error) {
+	if sqlTx, ok := tx.(*sql.Tx); ok {
+		return fn(sqlTx)
+	}
+
+	sqlTx, err := r.db.Writer(ctx).BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	if err = fn(sqlTx); err != nil {
+		if err := sqlTx.Rollback(); err != nil {
+			r.logger.WithContext(ctx).Error(err)
+		}
+		return
+	}
+	return sqlTx.Commit()
+}
+
+func (r *taskRepository) labelTableName() string {
+	return r.tableName + "_label"
+}
+
+func (r *taskRepository) assigneeTableName() string {
+	return r.tableName + "_assignee"
+}
+
+func (r *taskRepository) watcherTableName() string {
+	return r.tableName + "_watcher"
+}
+
+func (r *taskRepository) query(ctx context.Context, cmd sqlCommand, query string, args ...interface{}) (bunchOfTasks []entity.Task, err error) {
+	var rows *sql.Rows
+	if rows, err = cmd.QueryContext(ctx, query, args...); err != nil {
+		r.logger.WithContext(ctx).Error(query, err)
+		return
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+		}
+	}()
+
+	for rows.Next() {
+		var task entity.Task
+		var description sql.NullString
+		var updatedAt sql.NullTime
+		var attachment sql.NullString
+		var dueAt, remindAt, remindedAt, seriesStart sql.NullTime
+		var recurrence sql.NullString
+		var seriesID, parentID, projectID sql.NullInt64
+
+		err = rows.Scan(&task.ID, &task.Name, &description, &task.Status, &task.Priority, &attachment, &dueAt, &remindAt, &remindedAt, &recurrence, &seriesID, &seriesStart, &task.Occurrence, &parentID, &projectID, &task.Rank, &task.CreatedAt, &updatedAt)
+
+		if err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+			return
+		}
+
+		task.Description = description.String
+
+		if attachment.Valid {
+			task.Attachment = &attachment.String
+		}
+
+		if dueAt.Valid {
+			task.DueAt = &dueAt.Time
+		}
+
+		if remindAt.Valid {
+			task.RemindAt = &remindAt.Time
+		}
+
+		if remindedAt.Valid {
+			task.RemindedAt = &remindedAt.Time
+		}
+
+		if recurrence.Valid {
+			task.Recurrence = &recurrence.String
+		}
+
+		if seriesID.Valid {
+			task.SeriesID = &seriesID.Int64
+		}
+
+		if seriesStart.Valid {
+			task.SeriesStart = &seriesStart.Time
+		}
+
+		if parentID.Valid {
+			task.ParentID = &parentID.Int64
+		}
+
+		if projectID.Valid {
+			task.ProjectID = &projectID.Int64
+		}
+
+		if updatedAt.Valid {
+			task.UpdatedAt = &updatedAt.Time
+		}
+
+		bunchOfTasks = append(bunchOfTasks, task)
+	}
+
+	return
+}
+
+// Save will collect the order
+func (r *taskRepository) Save(ctx context.Context, task TaskRequest, tx database.Tx) (id int64, err error) {
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("name", "description", "status", "priority", "due_at", "remind_at", "recurrence", "series_id", "series_start", "occurrence", "parent_id", "project_id", "rank_key", "created_at").
+		Values(task.Name, task.Description, task.Status, intValue(task.Priority), task.DueAt, task.RemindAt, task.Recurrence, task.SeriesID, task.SeriesStart, task.Occurrence, task.ParentID, task.ProjectID, task.Rank, task.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	related := len(task.LabelIDs) > 0 || len(task.Assignees) > 0 || len(task.Watchers) > 0
+	save := func(cmd sqlCommand) (err error) {
+		if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			return
+		}
+		if related {
+			err = r.setRelations(ctx, cmd, id, task)
+		}
+		return
+	}
+
+	if related {
+		err = r.inTx(ctx, tx, save)
+	} else if sqlTx, ok := tx.(*sql.Tx); ok {
+		err = save(sqlTx)
+	} else {
+		err = save(r.db.Writer(ctx))
+	}
+	if err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+// UpdateById replaces the task, its labels, assignees and watchers.
+func (r *taskRepository) UpdateById(ctx context.Context, id int64, task TaskRequest, tx database.Tx) (err error) {
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("name", task.Name).
+		Set("description", task.Description).
+		Set("status", task.Status).
+		Set("priority", intValue(task.Priority)).
+		Set("attachment", task.Attachment).
+		Set("due_at", task.DueAt).
+		Set("remind_at", task.RemindAt).
+		Set("reminded_at", task.RemindedAt).
+		Set("recurrence", task.Recurrence).
+		Set("series_id", task.SeriesID).
+		Set("series_start", task.SeriesStart).
+		Set("occurrence", task.Occurrence).
+		Set("parent_id", task.ParentID).
+		Set("project_id", task.ProjectID).
+		Set("updated_at", task.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	return r.inTx(ctx, tx, func(cmd sqlCommand) (err error) {
+		if _, err = r.exec(ctx, cmd, stmt, args...); err != nil {
+			return
+		}
+		return r.setRelations(ctx, cmd, id, task)
+	})
+}
+
+func (r *taskRepository) exec(ctx context.Context, cmd sqlCommand, command string, args ...interface{}) (result sql.Result, err error) {
+	var stmt *sql.Stmt
+	if stmt, err = cmd.PrepareContext(ctx, command); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		return
+	}
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(command, err)
+		}
+	}()
+
+	if result, err = stmt.ExecContext(ctx, args...); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+	}
+
+	return
+}
+
+func wrapError(e error) (err error) {
+	if e == sql.ErrNoRows {
+		return exception.ErrNotFound
+	}
+	if database.IsUniqueViolation(e) {
+		return exception.ErrConflict
+	}
+	return exception.ErrInternalServer
+}
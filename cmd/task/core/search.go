@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"unicode"
+)
+
+const (
+	// searchLimit is the number of the most relevant tasks that a query reads at most.
+	searchLimit = 500
+	// snippetWords is the number of words of the snippet of a description.
+	snippetWords = 24
+)
+
+// search returns the ids of the tasks that match the query, the most relevant first. The ids are kept to the given
+// ones unless they are nil, the result is never nil so that a query without matches filters out every task.
+func (s *taskService) search(ctx context.Context, query string, ids []int64) (matches []int64, err error) {
+	hits, err := s.searchIndex.Search(ctx, query, searchLimit)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+
+	matches = make([]int64, 0, len(hits))
+	for _, hit := range hits {
+		if ids == nil || containsID(ids, hit.TaskID) {
+			matches = append(matches, hit.TaskID)
+		}
+	}
+	return
+}
+
+// rankMatches orders the tasks of a search by relevance, unless the filter sorts them, and highlights them.
+func rankMatches(tasks []entity.Task, filter Filter) {
+	if filter.Sort == "" {
+		positions := make(map[int64]int, len(filter.IDs))
+		for i, id := range filter.IDs {
+			positions[id] = i
+		}
+		sort.SliceStable(tasks, func(i, j int) bool { return positions[tasks[i].ID] < positions[tasks[j].ID] })
+	}
+	highlightMatches(tasks, filter.Query)
+}
+
+// highlightMatches sets the highlight of the tasks of a search.
+func highlightMatches(tasks []entity.Task, query string) {
+	words := make(map[string]bool)
+	for _, word := range searchTerms(query) {
+		words[word] = true
+	}
+	for i := range tasks {
+		tasks[i].Highlight = &entity.TaskHighlight{
+			Name:        highlight(tasks[i].Name, words, 0),
+			Description: highlight(tasks[i].Description, words, snippetWords),
+		}
+	}
+}
+
+// index updates the search index after a write, a failure is logged and the task is indexed again on its next write.
+func (s *taskService) index(ctx context.Context, tasks ...entity.Task) {
+	for _, task := range tasks {
+		if err := s.searchIndex.Index(ctx, task); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+	}
+}
+
+// searchTerms splits the text into the lower case words of letters and digits that the indexes match.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// highlight escapes the text for html and marks the words of the query with <mark>. A positive size cuts the text to a
+// snippet of as many words that starts shortly before the first match, the snippet is empty when no word matches.
+func highlight(text string, words map[string]bool, size int) string {
+	type span struct {
+		start, end int
+		match      bool
+	}
+
+	spans := make([]span, 0)
+	first := -1
+	start := -1
+	for i, r := range text + " " {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			match := words[strings.ToLower(text[start:i])]
+			if match && first < 0 {
+				first = len(spans)
+			}
+			spans = append(spans, span{start: start, end: i, match: match})
+			start = -1
+		}
+	}
+	if first < 0 && size > 0 {
+		return ""
+	}
+
+	from, to := 0, len(spans)
+	if size > 0 && len(spans) > size {
+		from = first - size/4
+		if from < 0 {
+			from = 0
+		}
+		if to = from + size; to > len(spans) {
+			to, from = len(spans), len(spans)-size
+		}
+	}
+
+	var builder strings.Builder
+	cursor, end := 0, len(text)
+	if from > 0 {
+		builder.WriteString("…")
+		cursor = spans[from].start
+	}
+	if to < len(spans) {
+		end = spans[to-1].end
+	}
+	for _, word := range spans[from:to] {
+		builder.WriteString(html.EscapeString(text[cursor:word.start]))
+		if word.match {
+			builder.WriteString("<mark>" + html.EscapeString(text[word.start:word.end]) + "</mark>")
+		} else {
+			builder.WriteString(html.EscapeString(text[word.start:word.end]))
+		}
+		cursor = word.end
+	}
+	builder.WriteString(html.EscapeString(text[cursor:end]))
+	if to < len(spans) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// SearchIndex finds the tasks whose name or description match a natural language query, a task matches any word of the query.
+type SearchIndex interface {
+	// Index adds or replaces the text of the task, an index that the database maintains ignores it.
+	Index(ctx context.Context, task entity.Task) (err error)
+	// Search returns at most limit tasks that match the query, the most relevant first.
+	Search(ctx context.Context, query string, limit int) (hits []SearchHit, err error)
+}
+
+// SearchHit is a task that matches a query, a higher score is more relevant.
+type SearchHit struct {
+	TaskID int64
+	Score  float64
+}
+
+type searchIndex struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewSearchIndex is a constructor of the index of the sql databases, it reads the full-text index of the task table.
+func NewSearchIndex(logger *logrus.Logger, db *database.Router, tableName string) SearchIndex {
+	return &searchIndex{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+// Index does nothing, the database keeps the full-text index up to date with the table.
+func (i *searchIndex) Index(ctx context.Context, task entity.Task) (err error) {
+	return
+}
+
+func (i *searchIndex) Search(ctx context.Context, query string, limit int) (hits []SearchHit, err error) {
+	var cmd sqlCommand = i.db.Reader(ctx)
+
+	match, score := i.dialect.FullText("t.name", "t.description")
+	stmt, args, err := i.builder.Select("t.id").
+		Column(sq.Expr(score+" AS score", query)).
+		From(i.tableName+" t").
+		Where(sq.Expr(match, query)).
+		OrderBy("score DESC", "t.id").
+		Limit(uint64(limit)).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		i.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	hits = make([]SearchHit, 0)
+	for rows.Next() {
+		var hit SearchHit
+		if err = rows.Scan(&hit.TaskID, &hit.Score); err != nil {
+			i.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
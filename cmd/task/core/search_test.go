@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, newTestRepositories(), core.TaskOptions{})
+
+	paperwork, milk, receipts := "file the receipts, then send the invoice <before> friday", "milk", "receipts"
+	for _, request := range []core.TaskRequest{
+		{Name: "Paperwork", Description: &paperwork},
+		{Name: "Send invoice to ACME"},
+		{Name: "Groceries", Description: &milk},
+	} {
+		if _, err := service.CreateTask(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks, err := service.GetManyTasks(ctx, core.Filter{Query: "Invoice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "Send invoice to ACME" || tasks[1].Name != "Paperwork" {
+		t.Fatalf("expected a match of the name before a match of the description, got %+v", tasks)
+	}
+	if tasks[0].Highlight == nil || tasks[0].Highlight.Name != "Send <mark>invoice</mark> to ACME" || tasks[0].Highlight.Description != "" {
+		t.Errorf("unexpected highlight %+v", tasks[0].Highlight)
+	}
+	if !strings.Contains(tasks[1].Highlight.Description, "<mark>invoice</mark> &lt;before&gt;") {
+		t.Errorf("expected an escaped snippet, got %q", tasks[1].Highlight.Description)
+	}
+
+	// a write reindexes the task and a query combines with the other filters.
+	if _, err = service.UpdateTask(ctx, tasks[1].ID, core.TaskRequest{Name: "Paperwork", Description: &receipts}); err != nil {
+		t.Fatal(err)
+	}
+	done := entity.TaskStatusDone
+	if _, err = service.UpdateTask(ctx, tasks[0].ID, core.TaskRequest{Name: "Send invoice to ACME", Status: &done}); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, err = service.GetManyTasks(ctx, core.Filter{Query: "invoice", Statuses: []int{entity.TaskStatusInitiate}}); err != nil || len(tasks) != 0 {
+		t.Errorf("expected no match, got %+v, %v", tasks, err)
+	}
+	if tasks, err = service.GetManyTasks(ctx, core.Filter{Query: "unknown"}); err != nil || len(tasks) != 0 {
+		t.Errorf("expected no match, got %+v, %v", tasks, err)
+	}
+}
@@ -0,0 +1,789 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/attachment"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNoAttachment is returned when the attachment of a task without one is deleted.
+	ErrNoAttachment = errors.New("task has no attachment")
+	// ErrInvalidRecurrence is returned for a rule that isn't a supported RRULE, or for a recurring task without a due date.
+	ErrInvalidRecurrence = errors.New("recurrence must be a valid RRULE of a task with a due date")
+	// ErrUnknownLabel is returned when a task is labeled with a label that doesn't exist.
+	ErrUnknownLabel = errors.New("unknown label")
+	// ErrUnknownProject is returned when a task is put in a project that doesn't exist.
+	ErrUnknownProject = errors.New("unknown project")
+	// ErrInvalidParent is returned for a parent that doesn't exist, that is a subtask of the task, or that nests it too deep.
+	ErrInvalidParent = errors.New("invalid parent")
+	// ErrOpenSubtasks is returned when a task is completed while its subtasks are open, unless it is forced.
+	ErrOpenSubtasks = errors.New("task has open subtasks")
+	// ErrInvalidChecklistOrder is returned when a reorder doesn't list every item of the checklist once.
+	ErrInvalidChecklistOrder = errors.New("the order must list every checklist item once")
+	// ErrInvalidDependency is returned for a blocker that doesn't exist.
+	ErrInvalidDependency = errors.New("invalid dependency")
+	// ErrDependencyCycle is returned for a dependency that would make a task block itself.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrInvalidMove is returned for a move without a neighbour, with a neighbour that doesn't exist, or with neighbours out of order.
+	ErrInvalidMove = errors.New("invalid move")
+	// ErrUnknownUser is returned when a task is assigned to or watched by a user that doesn't exist.
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrBlocked is returned when a task is started while one of its blockers is open.
+	ErrBlocked = errors.New("task is blocked by an open task")
+)
+
+// TaskService holds the task rules that are shared by every version of the api.
+// The errors are exception errors, or the errors of the attachment package on uploads.
+type TaskService interface {
+	GetManyTasks(ctx context.Context, filter Filter) (tasks []entity.Task, err error)
+	GetOneTask(ctx context.Context, id int64) (task entity.Task, err error)
+	CreateTask(ctx context.Context, taskRequest TaskRequest) (task entity.Task, err error)
+	UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (task entity.Task, err error)
+	UpdateSeries(ctx context.Context, id int64, taskRequest TaskRequest) (task entity.Task, err error)
+	// MoveTask places the task in the manual order, see MoveRequest.
+	MoveTask(ctx context.Context, id int64, moveRequest MoveRequest) (task entity.Task, err error)
+	// GetBoard returns the tasks by status, see BoardRequest.
+	GetBoard(ctx context.Context, boardRequest BoardRequest) (board Board, err error)
+	// MoveOnBoard moves the task to another status and place at once, see BoardMoveRequest.
+	MoveOnBoard(ctx context.Context, id int64, boardMove BoardMoveRequest) (task entity.Task, err error)
+	GetChecklist(ctx context.Context, id int64) (items []entity.ChecklistItem, err error)
+	AddChecklistItem(ctx context.Context, id int64, itemRequest ChecklistItemRequest) (item entity.ChecklistItem, err error)
+	UpdateChecklistItem(ctx context.Context, id int64, itemID int64, itemRequest ChecklistItemRequest) (item entity.ChecklistItem, err error)
+	ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (items []entity.ChecklistItem, err error)
+	DeleteChecklistItem(ctx context.Context, id int64, itemID int64) (err error)
+	AddDependency(ctx context.Context, id int64, blockerID int64) (dependency entity.Dependency, err error)
+	RemoveDependency(ctx context.Context, id int64, blockerID int64) (err error)
+	GetGraph(ctx context.Context, id int64) (graph Graph, err error)
+	UploadAttachment(ctx context.Context, folderName string, file attachment.File) (uploaded entity.Attachment, err error)
+	DeleteTaskAttachment(ctx context.Context, id int64) (err error)
+	DeleteAttachment(ctx context.Context, url string) (err error)
+	// Assign, Unassign, Watch and Unwatch change the users of the task by their uuid.
+	Assign(ctx context.Context, id int64, userUUID string) (task entity.Task, err error)
+	Unassign(ctx context.Context, id int64, userUUID string) (task entity.Task, err error)
+	Watch(ctx context.Context, id int64, userUUID string) (task entity.Task, err error)
+	Unwatch(ctx context.Context, id int64, userUUID string) (task entity.Task, err error)
+	// GetActivities returns a page of the history of the task, see ActivityPage.
+	GetActivities(ctx context.Context, id int64, page ActivityPage) (activities []entity.TaskActivity, err error)
+}
+
+// Repositories are the stores of the task service.
+type Repositories struct {
+	Tasks        TaskRepository
+	Labels       LabelRepository
+	Checklists   ChecklistRepository
+	Dependencies DependencyRepository
+	Projects     ProjectRepository
+	Comments     CommentRepository
+	Activities   ActivityRepository
+	// Users are the users that the tasks are assigned to and watched by.
+	Users UserFinder
+	// Search is kept up to date by the writes of the service.
+	Search SearchIndex
+}
+
+// TaskOptions are the limits of the task rules.
+type TaskOptions struct {
+	// MaxDepth is the number of levels of a tree of subtasks, a top level task included. Zero means no limit.
+	MaxDepth int
+	// Locker serializes the writes of the dependencies across the replicas, the process is locked without one.
+	Locker lock.Locker
+}
+
+type taskService struct {
+	logger               *logrus.Logger
+	location             *time.Location
+	attachmentUploader   *attachment.Uploader
+	taskRepository       TaskRepository
+	labelRepository      LabelRepository
+	checklistRepository  ChecklistRepository
+	dependencyRepository DependencyRepository
+	projectRepository    ProjectRepository
+	commentRepository    CommentRepository
+	activityRepository   ActivityRepository
+	users                UserFinder
+	searchIndex          SearchIndex
+	notifier             notifier.Notifier
+	options              TaskOptions
+}
+
+// NewTaskService is a constructor. The uploader is optional for the callers that never upload,
+// the watchers aren't notified without a notifier.
+func NewTaskService(logger *logrus.Logger, location *time.Location, attachmentUploader *attachment.Uploader, notifier notifier.Notifier, repositories Repositories, options TaskOptions) TaskService {
+	if options.Locker == nil {
+		options.Locker = lock.NewMemoryLocker()
+	}
+
+	return &taskService{
+		logger:               logger,
+		location:             location,
+		attachmentUploader:   attachmentUploader,
+		taskRepository:       repositories.Tasks,
+		labelRepository:      repositories.Labels,
+		checklistRepository:  repositories.Checklists,
+		dependencyRepository: repositories.Dependencies,
+		projectRepository:    repositories.Projects,
+		commentRepository:    repositories.Comments,
+		activityRepository:   repositories.Activities,
+		users:                repositories.Users,
+		searchIndex:          repositories.Search,
+		notifier:             notifier,
+		options:              options,
+	}
+}
+
+// GetManyTasks returns the tasks of the filter, an empty slice when none matches.
+// The tasks of the archived projects are left out unless the filter asks for a project.
+// The tasks of a query are highlighted, and the most relevant come first unless the filter sorts them.
+func (s *taskService) GetManyTasks(ctx context.Context, filter Filter) (tasks []entity.Task, err error) {
+	if filter, err = s.resolveFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	if tasks, err = s.findTasks(ctx, filter); err != nil || filter.Query == "" {
+		return
+	}
+	rankMatches(tasks, filter)
+	return
+}
+
+// resolveFilter turns the filter of a request into the filter of the repositories.
+func (s *taskService) resolveFilter(ctx context.Context, filter Filter) (resolved Filter, err error) {
+	if filter.ProjectID == nil {
+		if filter.ExcludedProjectIDs, err = s.archivedProjectIDs(ctx); err != nil {
+			return
+		}
+	}
+	if filter.Query != "" {
+		if filter.IDs, err = s.search(ctx, filter.Query, filter.IDs); err != nil {
+			return
+		}
+	}
+	return s.resolveDueFilter(filter, time.Now()), nil
+}
+
+// findTasks returns the loaded tasks of a resolved filter.
+func (s *taskService) findTasks(ctx context.Context, filter Filter) (tasks []entity.Task, err error) {
+	if tasks, err = s.taskRepository.FindMany(ctx, filter); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, s.wrapError(err)
+	}
+	if tasks == nil {
+		tasks = make([]entity.Task, 0)
+	}
+	if err = s.load(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (s *taskService) GetOneTask(ctx context.Context, id int64) (task entity.Task, err error) {
+	if task, err = s.taskRepository.FindOneById(ctx, id); err != nil {
+		if err != exception.ErrNotFound {
+			s.logger.WithContext(ctx).Error(err)
+		}
+		err = s.wrapError(err)
+		return
+	}
+
+	tasks := []entity.Task{task}
+	err = s.load(ctx, tasks)
+	return tasks[0], err
+}
+
+// CreateTask saves a task in its initial status, the attachment is only linked by an update.
+// A task with a recurrence is the first occurrence of its series.
+func (s *taskService) CreateTask(ctx context.Context, taskRequest TaskRequest) (task entity.Task, err error) {
+	taskStatus := entity.TaskStatusInitiate
+	taskRequest.Status = &taskStatus
+	taskRequest.Attachment = nil
+	taskRequest = s.inLocations(taskRequest)
+	taskRequest.RemindedAt = nil
+	taskRequest.SeriesID, taskRequest.SeriesStart, taskRequest.Occurrence = nil, nil, 0
+	taskRequest.Assignees, taskRequest.Watchers = nil, nil
+	taskRequest.CreatedAt = time.Now().In(s.location)
+	taskRequest.UpdatedAt = nil
+
+	if taskRequest.Recurrence != nil {
+		if err = s.startSeries(&taskRequest); err != nil {
+			return
+		}
+	}
+	if taskRequest.LabelIDs, err = s.checkLabels(ctx, taskRequest.LabelIDs); err != nil {
+		return
+	}
+	if err = s.checkParent(ctx, 0, taskRequest.ParentID); err != nil {
+		return
+	}
+	if err = s.checkProject(ctx, taskRequest.ProjectID); err != nil {
+		return
+	}
+	if taskRequest.Rank, err = s.appendRank(ctx); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	id, err := s.saveTask(ctx, taskRequest)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	task = taskOf(id, taskRequest)
+	if taskRequest.Recurrence != nil {
+		task.SeriesID = &id
+	}
+	s.index(ctx, task)
+	tasks := []entity.Task{task}
+	err = s.load(ctx, tasks)
+	return tasks[0], err
+}
+
+// UpdateTask replaces the fields of an existing task, a reminder that moves is sent again.
+// The rule of a recurring task is kept, UpdateSeries changes it, and completing an occurrence creates the next one.
+func (s *taskService) UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (task entity.Task, err error) {
+	if task, err = s.GetOneTask(ctx, id); err != nil {
+		return
+	}
+
+	taskRequest = s.inLocations(taskRequest)
+	taskRequest.Rank = ""
+	taskRequest.Assignees, taskRequest.Watchers = task.Assignees, task.Watchers
+	if task.SeriesID != nil {
+		taskRequest.Recurrence, taskRequest.SeriesID, taskRequest.SeriesStart, taskRequest.Occurrence = task.Recurrence, task.SeriesID, task.SeriesStart, task.Occurrence
+	} else if taskRequest.Recurrence != nil {
+		if err = s.startSeries(&taskRequest); err != nil {
+			return
+		}
+		taskRequest.SeriesID = &id
+	}
+
+	return s.update(ctx, task, taskRequest, nil)
+}
+
+// update writes the task, its new rank, the pending occurrences of its series, the next occurrence and their activities in a transaction.
+// An empty rank keeps the rank of the task.
+func (s *taskService) update(ctx context.Context, task entity.Task, taskRequest TaskRequest, occurrences []occurrenceUpdate) (updated entity.Task, err error) {
+	if taskRequest.LabelIDs, err = s.checkLabels(ctx, taskRequest.LabelIDs); err != nil {
+		return task, err
+	}
+	if !sameID(task.ParentID, taskRequest.ParentID) {
+		if err = s.checkParent(ctx, task.ID, taskRequest.ParentID); err != nil {
+			return task, err
+		}
+	}
+	if !sameID(task.ProjectID, taskRequest.ProjectID) {
+		if err = s.checkProject(ctx, taskRequest.ProjectID); err != nil {
+			return task, err
+		}
+	}
+	if task.Status == entity.TaskStatusInitiate && intValue(taskRequest.Status) != entity.TaskStatusInitiate {
+		if err = s.checkBlockersDone(ctx, task.ID); err != nil {
+			return task, err
+		}
+	}
+	if task.Status != entity.TaskStatusDone && intValue(taskRequest.Status) == entity.TaskStatusDone && !taskRequest.Force {
+		if err = s.checkSubtasksDone(ctx, task.ID); err != nil {
+			return task, err
+		}
+	}
+
+	if taskRequest.Rank == task.Rank {
+		taskRequest.Rank = ""
+	}
+	taskRequest.RemindedAt = nil
+	if taskRequest.RemindAt != nil && task.RemindAt != nil && taskRequest.RemindAt.Equal(*task.RemindAt) {
+		taskRequest.RemindedAt = task.RemindedAt
+	}
+
+	updatedAt := time.Now().In(s.location)
+	taskRequest.UpdatedAt = &updatedAt
+
+	next, hasNext, err := s.nextOccurrence(ctx, task, taskRequest)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	tx, err := s.taskRepository.BeginTx(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+	nextID, err := s.writeUpdate(ctx, task, taskRequest, occurrences, next, hasNext, tx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		if err := s.taskRepository.RollbackTx(ctx, tx); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+		return task, exception.ErrInternalServer
+	}
+	if err = s.taskRepository.CommitTx(ctx, tx); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return task, exception.ErrInternalServer
+	}
+
+	updated = taskOf(task.ID, taskRequest)
+	if updated.Rank == "" {
+		updated.Rank = task.Rank
+	}
+	updated.CreatedAt = task.CreatedAt
+	updated.RemindedAt = taskRequest.RemindedAt
+	s.notifyWatchers(ctx, task, updated)
+	s.index(ctx, updated)
+	for _, occurrence := range occurrences {
+		s.index(ctx, taskOf(occurrence.task.ID, occurrence.request))
+	}
+	if hasNext {
+		s.index(ctx, taskOf(nextID, next))
+	}
+
+	tasks := []entity.Task{updated}
+	err = s.load(ctx, tasks)
+	return tasks[0], err
+}
+
+// writeUpdate returns the id of the next occurrence when there is one.
+func (s *taskService) writeUpdate(ctx context.Context, task entity.Task, taskRequest TaskRequest, occurrences []occurrenceUpdate, next TaskRequest, hasNext bool, tx database.Tx) (nextID int64, err error) {
+	if err = s.taskRepository.UpdateById(ctx, task.ID, taskRequest, tx); err != nil {
+		return
+	}
+	if taskRequest.Rank != "" {
+		if err = s.taskRepository.UpdateRank(ctx, task.ID, taskRequest.Rank, tx); err != nil {
+			return
+		}
+	}
+	if err = s.record(ctx, tx, &task, taskOf(task.ID, taskRequest)); err != nil {
+		return
+	}
+	for _, occurrence := range occurrences {
+		if err = s.taskRepository.UpdateById(ctx, occurrence.task.ID, occurrence.request, tx); err != nil {
+			return
+		}
+		if err = s.record(ctx, tx, &occurrence.task, taskOf(occurrence.task.ID, occurrence.request)); err != nil {
+			return
+		}
+	}
+	if hasNext {
+		if nextID, err = s.taskRepository.Save(ctx, next, tx); err != nil {
+			return
+		}
+		err = s.record(ctx, tx, nil, taskOf(nextID, next))
+	}
+	return
+}
+
+func (s *taskService) UploadAttachment(ctx context.Context, folderName string, file attachment.File) (uploaded entity.Attachment, err error) {
+	if uploaded, err = s.attachmentUploader.Upload(ctx, folderName, file); err != nil && !errors.Is(err, attachment.ErrInfected) {
+		s.logger.WithContext(ctx).Error(err)
+	}
+	return
+}
+
+// DeleteTaskAttachment unlinks the attachment of the task and deletes it unless another task links it.
+func (s *taskService) DeleteTaskAttachment(ctx context.Context, id int64) (err error) {
+	task, err := s.GetOneTask(ctx, id)
+	if err != nil {
+		return
+	}
+	if task.Attachment == nil {
+		return ErrNoAttachment
+	}
+
+	updatedAt := time.Now().In(s.location)
+	taskRequest := requestOf(task)
+	taskRequest.Attachment = nil
+	taskRequest.UpdatedAt = &updatedAt
+	if err = s.unlinkAttachment(ctx, task, taskRequest); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+
+	// the task is unlinked already, an object that is left behind is collected by the reconciler.
+	if err := s.deleteUnreferencedAttachment(ctx, *task.Attachment); err != nil && err != exception.ErrConflict {
+		s.logger.WithContext(ctx).Error(err)
+	}
+	return nil
+}
+
+// unlinkAttachment writes the task without its attachment and the activity in a transaction.
+func (s *taskService) unlinkAttachment(ctx context.Context, task entity.Task, taskRequest TaskRequest) (err error) {
+	tx, err := s.taskRepository.BeginTx(ctx)
+	if err != nil {
+		return
+	}
+	if err = s.taskRepository.UpdateById(ctx, task.ID, taskRequest, tx); err == nil {
+		err = s.record(ctx, tx, &task, taskOf(task.ID, taskRequest))
+	}
+	if err != nil {
+		if err := s.taskRepository.RollbackTx(ctx, tx); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+		return
+	}
+	return s.taskRepository.CommitTx(ctx, tx)
+}
+
+// DeleteAttachment deletes an attachment, exception.ErrConflict is returned while a task links it.
+func (s *taskService) DeleteAttachment(ctx context.Context, url string) (err error) {
+	if err = s.deleteUnreferencedAttachment(ctx, url); err != nil && err != exception.ErrNotFound && err != exception.ErrConflict {
+		s.logger.WithContext(ctx).Error(err)
+		err = exception.ErrInternalServer
+	}
+	return
+}
+
+func (s *taskService) deleteUnreferencedAttachment(ctx context.Context, url string) (err error) {
+	tasks, err := s.taskRepository.FindMany(ctx, Filter{Attachment: &url})
+	if err != nil {
+		return
+	}
+	if len(tasks) > 0 {
+		return exception.ErrConflict
+	}
+
+	return s.attachmentUploader.DeleteByURL(ctx, url)
+}
+
+// checkLabels returns the labels without duplicates, ErrUnknownLabel is returned when one doesn't exist.
+func (s *taskService) checkLabels(ctx context.Context, labelIDs []int64) (checked []int64, err error) {
+	checked = uniqueIDs(labelIDs)
+	if len(checked) == 0 {
+		return
+	}
+
+	labels, err := s.labelRepository.FindByIds(ctx, checked)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+	if len(labels) != len(checked) {
+		return nil, ErrUnknownLabel
+	}
+	return
+}
+
+// checkProject returns ErrUnknownProject when the project doesn't exist, a task can be put in an archived project.
+func (s *taskService) checkProject(ctx context.Context, projectID *int64) (err error) {
+	if projectID == nil {
+		return
+	}
+
+	if _, err = s.projectRepository.FindOneById(ctx, *projectID); err == exception.ErrNotFound {
+		return ErrUnknownProject
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	return
+}
+
+func (s *taskService) archivedProjectIDs(ctx context.Context) (ids []int64, err error) {
+	archived := true
+	projects, err := s.projectRepository.FindMany(ctx, ProjectFilter{Archived: &archived})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, exception.ErrInternalServer
+	}
+
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	return
+}
+
+// checkParent checks that the task can move under the parent, the id of a new task is 0.
+func (s *taskService) checkParent(ctx context.Context, id int64, parentID *int64) (err error) {
+	if parentID == nil {
+		return
+	}
+
+	// the depth of the task is one more than the depth of its parent.
+	depth := 1
+	for ancestorID := parentID; ancestorID != nil; {
+		if *ancestorID == id {
+			return fmt.Errorf("%w: the parent is the task or one of its subtasks", ErrInvalidParent)
+		}
+		depth++
+		if s.tooDeep(depth) {
+			return fmt.Errorf("%w: subtasks are %d levels deep at most", ErrInvalidParent, s.options.MaxDepth)
+		}
+
+		ancestor, err := s.taskRepository.FindOneById(ctx, *ancestorID)
+		if err == exception.ErrNotFound {
+			return fmt.Errorf("%w: the parent doesn't exist", ErrInvalidParent)
+		}
+		if err != nil {
+			s.logger.WithContext(ctx).Error(err)
+			return exception.ErrInternalServer
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	// the subtasks of the task move along with it.
+	for level := []int64{id}; id != 0 && len(level) > 0; {
+		var next []int64
+		for _, levelID := range level {
+			levelID := levelID
+			children, err := s.taskRepository.FindMany(ctx, Filter{ParentID: &levelID})
+			if err != nil {
+				s.logger.WithContext(ctx).Error(err)
+				return exception.ErrInternalServer
+			}
+			for _, child := range children {
+				next = append(next, child.ID)
+			}
+		}
+		if len(next) > 0 {
+			depth++
+		}
+		if s.tooDeep(depth) {
+			return fmt.Errorf("%w: subtasks are %d levels deep at most", ErrInvalidParent, s.options.MaxDepth)
+		}
+		level = next
+	}
+	return
+}
+
+func (s *taskService) tooDeep(depth int) bool {
+	return s.options.MaxDepth > 0 && depth > s.options.MaxDepth
+}
+
+// checkSubtasksDone returns ErrOpenSubtasks while a subtask of the task is open.
+func (s *taskService) checkSubtasksDone(ctx context.Context, id int64) (err error) {
+	open, err := s.taskRepository.FindMany(ctx, Filter{ParentID: &id, Pending: true})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	if len(open) > 0 {
+		return ErrOpenSubtasks
+	}
+	return
+}
+
+// load sets what the service loads on the tasks, their labels, the progress of their checklists and their comment counts.
+func (s *taskService) load(ctx context.Context, tasks []entity.Task) (err error) {
+	if err = s.loadLabels(ctx, tasks); err != nil {
+		return
+	}
+	if err = s.loadProgress(ctx, tasks); err != nil {
+		return
+	}
+	return s.loadCommentCounts(ctx, tasks)
+}
+
+func (s *taskService) loadProgress(ctx context.Context, tasks []entity.Task) (err error) {
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	progress, err := s.checklistRepository.CountByTaskIds(ctx, ids)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	for i := range tasks {
+		tasks[i].Progress = progress[tasks[i].ID]
+	}
+	return
+}
+
+// loadCommentCounts sets the number of comments of the tasks.
+func (s *taskService) loadCommentCounts(ctx context.Context, tasks []entity.Task) (err error) {
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	counts, err := s.commentRepository.CountByTaskIds(ctx, ids)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	for i := range tasks {
+		tasks[i].CommentCount = counts[tasks[i].ID]
+	}
+	return
+}
+
+// loadLabels sets the labels of the tasks from their label ids.
+func (s *taskService) loadLabels(ctx context.Context, tasks []entity.Task) (err error) {
+	var labelIDs []int64
+	for _, task := range tasks {
+		labelIDs = append(labelIDs, task.LabelIDs...)
+	}
+
+	labels, err := s.labelRepository.FindByIds(ctx, uniqueIDs(labelIDs))
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return exception.ErrInternalServer
+	}
+	byID := make(map[int64]entity.Label, len(labels))
+	for _, label := range labels {
+		byID[label.ID] = label
+	}
+
+	for i := range tasks {
+		tasks[i].Labels = make([]entity.Label, 0, len(tasks[i].LabelIDs))
+		for _, labelID := range tasks[i].LabelIDs {
+			if label, ok := byID[labelID]; ok {
+				tasks[i].Labels = append(tasks[i].Labels, label)
+			}
+		}
+	}
+	return
+}
+
+// resolveDueFilter turns the due filters into the bounds of the repositories, a day starts at midnight of the location.
+func (s *taskService) resolveDueFilter(filter Filter, now time.Time) Filter {
+	now = now.In(s.location)
+
+	if filter.DueBefore != nil {
+		filter.DueUntil = earliest(filter.DueUntil, *filter.DueBefore)
+	}
+	if filter.Overdue {
+		filter.DueUntil = earliest(filter.DueUntil, now)
+		filter.Pending = true
+	}
+	if filter.DueToday {
+		filter = within(filter, PeriodToday, now)
+	}
+	if filter.DuePeriod != "" {
+		filter = within(filter, filter.DuePeriod, now)
+	}
+	if filter.DueBeforePeriod != "" {
+		start, _ := periodOf(filter.DueBeforePeriod, now)
+		filter.DueUntil = earliest(filter.DueUntil, start)
+	}
+	return filter
+}
+
+// periodOf returns the bounds of the relative period around now, the end is exclusive. An unknown period is today.
+func periodOf(period string, now time.Time) (start time.Time, end time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// the days since monday.
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	firstOfMonth := today.AddDate(0, 0, 1-today.Day())
+
+	switch period {
+	case PeriodTomorrow:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2)
+	case PeriodThisWeek:
+		return monday, monday.AddDate(0, 0, 7)
+	case PeriodNextWeek:
+		return monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 14)
+	case PeriodThisMonth:
+		return firstOfMonth, firstOfMonth.AddDate(0, 1, 0)
+	case PeriodNextMonth:
+		return firstOfMonth.AddDate(0, 1, 0), firstOfMonth.AddDate(0, 2, 0)
+	}
+	return today, today.AddDate(0, 0, 1)
+}
+
+// within narrows the due bounds of the filter down to the period.
+func within(filter Filter, period string, now time.Time) Filter {
+	start, end := periodOf(period, now)
+	if filter.DueFrom == nil || filter.DueFrom.Before(start) {
+		filter.DueFrom = &start
+	}
+	filter.DueUntil = earliest(filter.DueUntil, end)
+	return filter
+}
+
+func (s *taskService) inLocations(taskRequest TaskRequest) TaskRequest {
+	taskRequest.DueAt = s.inLocation(taskRequest.DueAt)
+	taskRequest.RemindAt = s.inLocation(taskRequest.RemindAt)
+	return taskRequest
+}
+
+func (s *taskService) inLocation(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	inLocation := value.In(s.location)
+	return &inLocation
+}
+
+func earliest(value *time.Time, other time.Time) *time.Time {
+	if value != nil && value.Before(other) {
+		return value
+	}
+	return &other
+}
+
+func sameID(value, other *int64) bool {
+	if value == nil || other == nil {
+		return value == other
+	}
+	return *value == *other
+}
+
+// requestOf returns the request that writes the task as it is.
+func requestOf(task entity.Task) TaskRequest {
+	return TaskRequest{
+		Name:        task.Name,
+		Description: &task.Description,
+		Status:      &task.Status,
+		Priority:    &task.Priority,
+		Attachment:  task.Attachment,
+		DueAt:       task.DueAt,
+		RemindAt:    task.RemindAt,
+		RemindedAt:  task.RemindedAt,
+		Recurrence:  task.Recurrence,
+		SeriesID:    task.SeriesID,
+		SeriesStart: task.SeriesStart,
+		Occurrence:  task.Occurrence,
+		LabelIDs:    task.LabelIDs,
+		Assignees:   task.Assignees,
+		Watchers:    task.Watchers,
+		ParentID:    task.ParentID,
+		ProjectID:   task.ProjectID,
+		Rank:        task.Rank,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+}
+
+// taskOf returns the task that the request writes.
+func taskOf(id int64, taskRequest TaskRequest) entity.Task {
+	return entity.Task{
+		ID:          id,
+		Name:        taskRequest.Name,
+		Description: stringValue(taskRequest.Description),
+		Status:      intValue(taskRequest.Status),
+		Priority:    intValue(taskRequest.Priority),
+		Attachment:  taskRequest.Attachment,
+		DueAt:       taskRequest.DueAt,
+		RemindAt:    taskRequest.RemindAt,
+		Recurrence:  taskRequest.Recurrence,
+		SeriesID:    taskRequest.SeriesID,
+		SeriesStart: taskRequest.SeriesStart,
+		Occurrence:  taskRequest.Occurrence,
+		LabelIDs:    uniqueIDs(taskRequest.LabelIDs),
+		Assignees:   uniqueStrings(taskRequest.Assignees),
+		Watchers:    uniqueStrings(taskRequest.Watchers),
+		ParentID:    taskRequest.ParentID,
+		ProjectID:   taskRequest.ProjectID,
+		Rank:        taskRequest.Rank,
+		CreatedAt:   taskRequest.CreatedAt,
+		UpdatedAt:   taskRequest.UpdatedAt,
+	}
+}
+
+func (s *taskService) wrapError(err error) error {
+	if err == exception.ErrNotFound {
+		return err
+	}
+	return exception.ErrInternalServer
+}
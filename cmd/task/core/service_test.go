@@ -0,0 +1,279 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/memstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestRepositories() core.Repositories {
+	return core.Repositories{
+		Tasks:        core.NewMemoryTaskRepository(memstore.NewTable[int64, entity.Task]()),
+		Labels:       core.NewMemoryLabelRepository(memstore.NewTable[int64, entity.Label]()),
+		Checklists:   core.NewMemoryChecklistRepository(memstore.NewTable[int64, entity.ChecklistItem]()),
+		Dependencies: core.NewMemoryDependencyRepository(memstore.NewTable[core.DependencyKey, entity.Dependency]()),
+		Projects:     core.NewMemoryProjectRepository(memstore.NewTable[int64, entity.Project]()),
+		Comments:     core.NewMemoryCommentRepository(memstore.NewTable[int64, entity.Comment]()),
+		Activities:   core.NewMemoryActivityRepository(memstore.NewTable[int64, entity.TaskActivity]()),
+		Search:       core.NewInvertedIndex(),
+	}
+}
+
+func newTestService() (core.TaskService, core.TaskRepository) {
+	repositories := newTestRepositories()
+	return core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, core.TaskOptions{}), repositories.Tasks
+}
+
+func TestCreateAndUpdateTask(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	description := "buy milk"
+	created, err := service.CreateTask(ctx, core.TaskRequest{Name: "groceries", Description: &description})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 1 || created.Status != entity.TaskStatusInitiate || created.Description != description || created.CreatedAt.IsZero() {
+		t.Errorf("unexpected task %+v", created)
+	}
+
+	status := entity.TaskStatusDone
+	updated, err := service.UpdateTask(ctx, 1, core.TaskRequest{Name: "done", Status: &status})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "done" || updated.Status != status || updated.Description != "" || !updated.CreatedAt.Equal(created.CreatedAt) || updated.UpdatedAt == nil {
+		t.Errorf("unexpected task %+v", updated)
+	}
+
+	if _, err := service.UpdateTask(ctx, 42, core.TaskRequest{Name: "missing"}); err != exception.ErrNotFound {
+		t.Errorf("expected a missing task, got %v", err)
+	}
+	if err := service.DeleteTaskAttachment(ctx, 1); err != core.ErrNoAttachment {
+		t.Errorf("expected a task without attachment, got %v", err)
+	}
+}
+
+func TestGetManyTasksIsNeverNil(t *testing.T) {
+	service, _ := newTestService()
+
+	tasks, err := service.GetManyTasks(context.Background(), core.Filter{})
+	if err != nil || tasks == nil {
+		t.Errorf("expected an empty slice, got %v %v", tasks, err)
+	}
+}
+
+func TestRepositoryRollback(t *testing.T) {
+	ctx := context.Background()
+	service, repository := newTestService()
+
+	tx, _ := repository.BeginTx(ctx)
+	repository.Save(ctx, core.TaskRequest{Name: "discarded", CreatedAt: time.Now()}, tx)
+	if err := repository.RollbackTx(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.GetOneTask(ctx, 1); err != exception.ErrNotFound {
+		t.Errorf("expected the task to be rolled back, got %v", err)
+	}
+}
+
+func TestDueFilters(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	now := time.Now()
+	yesterday, tomorrow, nextWeek := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), now.AddDate(0, 0, 7)
+	service.CreateTask(ctx, core.TaskRequest{Name: "late", DueAt: &yesterday})
+	service.CreateTask(ctx, core.TaskRequest{Name: "today", DueAt: &now})
+	service.CreateTask(ctx, core.TaskRequest{Name: "soon", DueAt: &tomorrow})
+	service.CreateTask(ctx, core.TaskRequest{Name: "later", DueAt: &nextWeek})
+	service.CreateTask(ctx, core.TaskRequest{Name: "someday"})
+
+	status := entity.TaskStatusDone
+	service.UpdateTask(ctx, 1, core.TaskRequest{Name: "late", Status: &status, DueAt: &yesterday})
+	service.CreateTask(ctx, core.TaskRequest{Name: "forgotten", DueAt: &yesterday})
+
+	names := func(filter core.Filter) (names []string) {
+		tasks, err := service.GetManyTasks(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		return
+	}
+
+	if overdue := names(core.Filter{Overdue: true}); len(overdue) != 2 || overdue[0] != "today" || overdue[1] != "forgotten" {
+		t.Errorf("expected the pending tasks that are past due, got %v", overdue)
+	}
+	if today := names(core.Filter{DueToday: true}); len(today) != 1 || today[0] != "today" {
+		t.Errorf("expected the tasks due today, got %v", today)
+	}
+	if before := names(core.Filter{DueBefore: &nextWeek}); len(before) != 4 {
+		t.Errorf("expected the tasks due before next week, got %v", before)
+	}
+	if soon := names(core.Filter{DuePeriod: core.PeriodTomorrow}); len(soon) != 1 || soon[0] != "soon" {
+		t.Errorf("expected the tasks due tomorrow, got %v", soon)
+	}
+	if before := names(core.Filter{DueBeforePeriod: core.PeriodTomorrow}); len(before) != 3 {
+		t.Errorf("expected the tasks due before tomorrow, got %v", before)
+	}
+}
+
+func TestSubtasks(t *testing.T) {
+	ctx := context.Background()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, newTestRepositories(), core.TaskOptions{MaxDepth: 3})
+
+	parent, _ := service.CreateTask(ctx, core.TaskRequest{Name: "parent"})
+	child, err := service.CreateTask(ctx, core.TaskRequest{Name: "child", ParentID: &parent.ID})
+	if err != nil || *child.ParentID != parent.ID {
+		t.Fatalf("unexpected subtask %+v %v", child, err)
+	}
+	grandchild, _ := service.CreateTask(ctx, core.TaskRequest{Name: "grandchild", ParentID: &child.ID})
+	if _, err := service.CreateTask(ctx, core.TaskRequest{Name: "too deep", ParentID: &grandchild.ID}); !errors.Is(err, core.ErrInvalidParent) {
+		t.Errorf("expected a subtask that is too deep, got %v", err)
+	}
+	missing := int64(42)
+	if _, err := service.CreateTask(ctx, core.TaskRequest{Name: "orphan", ParentID: &missing}); !errors.Is(err, core.ErrInvalidParent) {
+		t.Errorf("expected a missing parent, got %v", err)
+	}
+	if _, err := service.UpdateTask(ctx, parent.ID, core.TaskRequest{Name: "parent", ParentID: &grandchild.ID}); !errors.Is(err, core.ErrInvalidParent) {
+		t.Errorf("expected a cycle, got %v", err)
+	}
+
+	// a task moves along with its subtasks.
+	other, _ := service.CreateTask(ctx, core.TaskRequest{Name: "other"})
+	if _, err := service.UpdateTask(ctx, parent.ID, core.TaskRequest{Name: "parent", ParentID: &other.ID}); !errors.Is(err, core.ErrInvalidParent) {
+		t.Errorf("expected the subtasks to be too deep, got %v", err)
+	}
+	if _, err := service.UpdateTask(ctx, grandchild.ID, core.TaskRequest{Name: "grandchild", ParentID: &other.ID}); err != nil {
+		t.Errorf("expected the subtask to move, got %v", err)
+	}
+
+	done := entity.TaskStatusDone
+	if _, err := service.UpdateTask(ctx, parent.ID, core.TaskRequest{Name: "parent", Status: &done}); err != core.ErrOpenSubtasks {
+		t.Errorf("expected the open subtasks to block the parent, got %v", err)
+	}
+	if _, err := service.UpdateTask(ctx, parent.ID, core.TaskRequest{Name: "parent", Status: &done, Force: true}); err != nil {
+		t.Errorf("expected a forced completion, got %v", err)
+	}
+
+	children, _ := service.GetManyTasks(ctx, core.Filter{ParentID: &parent.ID})
+	if len(children) != 1 || children[0].ID != child.ID {
+		t.Errorf("expected the child, got %+v", children)
+	}
+}
+
+// slowDependencies widens the window between the cycle check and the write of an edge.
+type slowDependencies struct {
+	core.DependencyRepository
+}
+
+func (r slowDependencies) FindBlockers(ctx context.Context, taskIDs []int64) (dependencies []entity.Dependency, err error) {
+	dependencies, err = r.DependencyRepository.FindBlockers(ctx, taskIDs)
+	time.Sleep(time.Millisecond * 20)
+	return
+}
+
+func TestConcurrentDependenciesCloseNoCycle(t *testing.T) {
+	ctx := context.Background()
+	repositories := newTestRepositories()
+	repositories.Dependencies = slowDependencies{repositories.Dependencies}
+	// two replicas share the repositories and the lock.
+	options := core.TaskOptions{Locker: lock.NewMemoryLocker()}
+	replicas := []core.TaskService{
+		core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, options),
+		core.NewTaskService(logrus.New(), time.UTC, nil, nil, repositories, options),
+	}
+
+	a, _ := replicas[0].CreateTask(ctx, core.TaskRequest{Name: "a"})
+	b, _ := replicas[0].CreateTask(ctx, core.TaskRequest{Name: "b"})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, edge := range [][2]int64{{a.ID, b.ID}, {b.ID, a.ID}} {
+		wg.Add(1)
+		go func(i int, edge [2]int64) {
+			defer wg.Done()
+			_, errs[i] = replicas[i].AddDependency(ctx, edge[0], edge[1])
+		}(i, edge)
+	}
+	wg.Wait()
+
+	if (errs[0] == nil) == (errs[1] == nil) {
+		t.Fatalf("expected a single edge to be written, got %v and %v", errs[0], errs[1])
+	}
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, core.ErrDependencyCycle) {
+			t.Errorf("expected a cycle, got %v", err)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	ctx := context.Background()
+	service := core.NewTaskService(logrus.New(), time.UTC, nil, nil, newTestRepositories(), core.TaskOptions{})
+
+	design, _ := service.CreateTask(ctx, core.TaskRequest{Name: "design"})
+	build, _ := service.CreateTask(ctx, core.TaskRequest{Name: "build"})
+	ship, _ := service.CreateTask(ctx, core.TaskRequest{Name: "ship"})
+	if _, err := service.AddDependency(ctx, build.ID, design.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddDependency(ctx, ship.ID, build.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddDependency(ctx, ship.ID, build.ID); err != nil {
+		t.Errorf("expected an existing dependency to be kept, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, design.ID, ship.ID); !errors.Is(err, core.ErrDependencyCycle) {
+		t.Errorf("expected a cycle, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, design.ID, design.ID); !errors.Is(err, core.ErrDependencyCycle) {
+		t.Errorf("expected a self dependency to be a cycle, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, design.ID, 42); !errors.Is(err, core.ErrInvalidDependency) {
+		t.Errorf("expected a missing blocker, got %v", err)
+	}
+
+	progress := entity.TaskStatusOnProgress
+	if _, err := service.UpdateTask(ctx, build.ID, core.TaskRequest{Name: "build", Status: &progress, Force: true}); err != core.ErrBlocked {
+		t.Errorf("expected the open blocker to block the start, got %v", err)
+	}
+	done := entity.TaskStatusDone
+	if _, err := service.UpdateTask(ctx, design.ID, core.TaskRequest{Name: "design", Status: &done}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdateTask(ctx, build.ID, core.TaskRequest{Name: "build", Status: &progress}); err != nil {
+		t.Errorf("expected the task to start once its blocker is done, got %v", err)
+	}
+
+	graph, err := service.GetGraph(ctx, build.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Upstream.Tasks) != 1 || graph.Upstream.Tasks[0].ID != design.ID || len(graph.Upstream.Edges) != 1 {
+		t.Errorf("unexpected upstream %+v", graph.Upstream)
+	}
+	if len(graph.Downstream.Tasks) != 1 || graph.Downstream.Tasks[0].ID != ship.ID || len(graph.Downstream.Edges) != 1 {
+		t.Errorf("unexpected downstream %+v", graph.Downstream)
+	}
+
+	if err := service.RemoveDependency(ctx, ship.ID, build.ID); err != nil {
+		t.Fatal(err)
+	}
+	if graph, _ := service.GetGraph(ctx, ship.ID); len(graph.Upstream.Tasks) != 0 {
+		t.Errorf("expected no blockers, got %+v", graph.Upstream)
+	}
+}
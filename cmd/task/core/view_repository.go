@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// ViewRepository stores the saved views of the task list.
+type ViewRepository interface {
+	Save(ctx context.Context, view entity.View) (id int64, err error)
+	// UpdateById writes the name, the query, the sort, the sharing and the update date of the view.
+	UpdateById(ctx context.Context, id int64, view entity.View) (err error)
+	DeleteById(ctx context.Context, id int64) (err error)
+	FindOneById(ctx context.Context, id int64) (view entity.View, err error)
+	// FindVisible returns the views of the owner and the shared views, sorted by name then id.
+	FindVisible(ctx context.Context, owner string) (views []entity.View, err error)
+}
+
+const viewColumns = "v.id, v.owner, v.name, v.query_string, v.sort, v.shared, v.created_at, v.updated_at"
+
+type viewRepository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	builder   sq.StatementBuilderType
+	tableName string
+}
+
+// NewViewRepository is a constructor
+func NewViewRepository(logger *logrus.Logger, db *database.Router, tableName string) ViewRepository {
+	return &viewRepository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		builder:   sq.StatementBuilder.PlaceholderFormat(db.Dialect().Placeholder()),
+		tableName: tableName,
+	}
+}
+
+func (r *viewRepository) Save(ctx context.Context, view entity.View) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Insert(r.tableName).
+		Columns("owner", "name", "query_string", "sort", "shared", "created_at").
+		Values(view.Owner, view.Name, view.Query, view.Sort, view.Shared, view.CreatedAt).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *viewRepository) UpdateById(ctx context.Context, id int64, view entity.View) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Update(r.tableName).
+		Set("name", view.Name).
+		Set("query_string", view.Query).
+		Set("sort", view.Sort).
+		Set("shared", view.Shared).
+		Set("updated_at", view.UpdatedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *viewRepository) DeleteById(ctx context.Context, id int64) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	stmt, args, err := r.builder.Delete(r.tableName).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if _, err = cmd.ExecContext(ctx, stmt, args...); err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *viewRepository) FindOneById(ctx context.Context, id int64) (view entity.View, err error) {
+	views, err := r.find(ctx, r.builder.Select(viewColumns).From(r.tableName+" v").Where(sq.Eq{"v.id": id}))
+	if err != nil {
+		return
+	}
+	if len(views) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+	return views[0], nil
+}
+
+func (r *viewRepository) FindVisible(ctx context.Context, owner string) (views []entity.View, err error) {
+	return r.find(ctx, r.builder.Select(viewColumns).
+		From(r.tableName+" v").
+		Where(sq.Or{sq.Eq{"v.owner": owner}, sq.Eq{"v.shared": true}}).
+		OrderBy("v.name", "v.id"))
+}
+
+func (r *viewRepository) find(ctx context.Context, builder sq.SelectBuilder) (views []entity.View, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	stmt, args, err := builder.ToSql()
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	rows, err := cmd.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Error(stmt, err)
+		err = wrapError(err)
+		return
+	}
+	defer rows.Close()
+
+	views = make([]entity.View, 0)
+	for rows.Next() {
+		var view entity.View
+		var updatedAt sql.NullTime
+		if err = rows.Scan(&view.ID, &view.Owner, &view.Name, &view.Query, &view.Sort, &view.Shared, &view.CreatedAt, &updatedAt); err != nil {
+			r.logger.WithContext(ctx).Error(stmt, err)
+			err = wrapError(err)
+			return
+		}
+		if updatedAt.Valid {
+			view.UpdatedAt = &updatedAt.Time
+		}
+		views = append(views, view)
+	}
+
+	if err = rows.Err(); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
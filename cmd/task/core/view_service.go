@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ViewService manages the saved views of the task list. A user reads its own views and the shared views, the views of
+// other users are exception.ErrNotFound. The errors are exception errors, exception.ErrForbidden is returned when a view
+// is changed by another user than its owner. The query of a view is checked by the api that runs it.
+type ViewService interface {
+	// GetViews returns the views that the user reads, sorted by name.
+	GetViews(ctx context.Context, owner string) (views []entity.View, err error)
+	GetView(ctx context.Context, id int64, owner string) (view entity.View, err error)
+	CreateView(ctx context.Context, viewRequest ViewRequest) (view entity.View, err error)
+	UpdateView(ctx context.Context, id int64, viewRequest ViewRequest) (view entity.View, err error)
+	DeleteView(ctx context.Context, id int64, owner string) (err error)
+}
+
+type viewService struct {
+	logger         *logrus.Logger
+	location       *time.Location
+	viewRepository ViewRepository
+}
+
+// NewViewService is a constructor
+func NewViewService(logger *logrus.Logger, location *time.Location, viewRepository ViewRepository) ViewService {
+	return &viewService{
+		logger:         logger,
+		location:       location,
+		viewRepository: viewRepository,
+	}
+}
+
+func (s *viewService) GetViews(ctx context.Context, owner string) (views []entity.View, err error) {
+	if views, err = s.viewRepository.FindVisible(ctx, owner); err != nil {
+		return nil, s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *viewService) GetView(ctx context.Context, id int64, owner string) (view entity.View, err error) {
+	if view, err = s.viewRepository.FindOneById(ctx, id); err != nil {
+		return view, s.wrapError(ctx, err)
+	}
+	if view.Owner != owner && !view.Shared {
+		return entity.View{}, exception.ErrNotFound
+	}
+	return
+}
+
+func (s *viewService) CreateView(ctx context.Context, viewRequest ViewRequest) (view entity.View, err error) {
+	view = entity.View{
+		Owner:     viewRequest.Owner,
+		Name:      strings.TrimSpace(viewRequest.Name),
+		Query:     viewRequest.Query,
+		Sort:      viewRequest.Sort,
+		Shared:    viewRequest.Shared,
+		CreatedAt: time.Now().In(s.location),
+	}
+	if view.ID, err = s.viewRepository.Save(ctx, view); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *viewService) UpdateView(ctx context.Context, id int64, viewRequest ViewRequest) (view entity.View, err error) {
+	if view, err = s.owned(ctx, id, viewRequest.Owner); err != nil {
+		return
+	}
+
+	updatedAt := time.Now().In(s.location)
+	view.Name = strings.TrimSpace(viewRequest.Name)
+	view.Query = viewRequest.Query
+	view.Sort = viewRequest.Sort
+	view.Shared = viewRequest.Shared
+	view.UpdatedAt = &updatedAt
+	if err = s.viewRepository.UpdateById(ctx, id, view); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+func (s *viewService) DeleteView(ctx context.Context, id int64, owner string) (err error) {
+	if _, err = s.owned(ctx, id, owner); err != nil {
+		return
+	}
+	if err = s.viewRepository.DeleteById(ctx, id); err != nil {
+		err = s.wrapError(ctx, err)
+	}
+	return
+}
+
+// owned returns the view that the user reads, exception.ErrForbidden is returned when another user owns it.
+func (s *viewService) owned(ctx context.Context, id int64, owner string) (view entity.View, err error) {
+	if view, err = s.GetView(ctx, id, owner); err != nil {
+		return
+	}
+	if view.Owner != owner {
+		return view, exception.ErrForbidden
+	}
+	return
+}
+
+func (s *viewService) wrapError(ctx context.Context, err error) error {
+	if err == exception.ErrNotFound {
+		return err
+	}
+	s.logger.WithContext(ctx).Error(err)
+	return exception.ErrInternalServer
+}
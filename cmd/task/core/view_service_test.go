@@ -0,0 +1,51 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestViews(t *testing.T) {
+	ctx := context.Background()
+	service := core.NewViewService(logrus.New(), time.UTC, core.NewMemoryViewRepository(memstore.NewTable[int64, entity.View]()))
+
+	private, err := service.CreateView(ctx, core.ViewRequest{Owner: "u-1", Name: " urgent ", Query: "priority=urgent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if private.Name != "urgent" {
+		t.Errorf("expected a trimmed name, got %q", private.Name)
+	}
+	shared, _ := service.CreateView(ctx, core.ViewRequest{Owner: "u-1", Name: "mine", Query: "assignee=me", Shared: true})
+
+	if views, _ := service.GetViews(ctx, "u-2"); len(views) != 1 || views[0].ID != shared.ID {
+		t.Errorf("expected the shared views only, got %+v", views)
+	}
+	if views, _ := service.GetViews(ctx, "u-1"); len(views) != 2 || views[0].Name != "mine" {
+		t.Errorf("expected the views of the owner by name, got %+v", views)
+	}
+	if _, err = service.GetView(ctx, private.ID, "u-2"); err != exception.ErrNotFound {
+		t.Errorf("expected a private view to be hidden, got %v", err)
+	}
+	if _, err = service.UpdateView(ctx, shared.ID, core.ViewRequest{Owner: "u-2", Name: "hijacked"}); err != exception.ErrForbidden {
+		t.Errorf("expected another user to be forbidden, got %v", err)
+	}
+
+	updated, err := service.UpdateView(ctx, private.ID, core.ViewRequest{Owner: "u-1", Name: "urgent", Query: "priority=urgent&due=this_week", Shared: true})
+	if err != nil || !updated.Shared || updated.UpdatedAt == nil {
+		t.Errorf("unexpected view %+v, %v", updated, err)
+	}
+	if err = service.DeleteView(ctx, private.ID, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = service.GetView(ctx, private.ID, "u-1"); err != exception.ErrNotFound {
+		t.Errorf("expected the view to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package task_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var timestampPattern = regexp.MustCompile(`"\d{4}-\d\d-\d\dT[^"]+"`)
+
+// TestTaskContract pins the json of the v1 api byte for byte, the timestamps aside.
+// The steps run in order against the same store.
+func TestTaskContract(t *testing.T) {
+	router := newTestRouter()
+
+	steps := []struct {
+		method   string
+		target   string
+		body     string
+		code     int
+		expected string
+	}{
+		{http.MethodGet, "/todo/v1/task", "", http.StatusOK,
+			`{"success":true,"data":[],"message":"","status":"OK","code":200}`},
+		{http.MethodPost, "/todo/v1/task", `{"name":"write tests","description":"for the handlers"}`, http.StatusOK,
+			`{"success":true,"data":{"id":1,"name":"write tests","description":"for the handlers","status":null,"attachment":null,"createdAt":"<time>","updatedAt":null},"message":"","status":"OK","code":200}`},
+		{http.MethodPost, "/todo/v1/task", `{"name":"second","status":1,"attachment":"https://x/y.png"}`, http.StatusOK,
+			`{"success":true,"data":{"id":2,"name":"second","description":null,"status":null,"attachment":"https://x/y.png","createdAt":"<time>","updatedAt":null},"message":"","status":"OK","code":200}`},
+		{http.MethodPost, "/todo/v1/task", `{"description":"no name"}`, http.StatusBadRequest,
+			`{"success":false,"data":null,"message":"invalid 'Name' with value ''","status":"INVALID_PAYLOAD","code":400}`},
+		{http.MethodPost, "/todo/v1/task", `{"name":`, http.StatusUnprocessableEntity,
+			`{"success":false,"data":null,"message":"unexpected EOF","status":"INVALID_PAYLOAD","code":422}`},
+		{http.MethodGet, "/todo/v1/task", "", http.StatusOK,
+			`{"success":true,"data":[{"id":1,"name":"write tests","description":"for the handlers","status":0,"attachment":null,"createdAt":"<time>","updatedAt":null},{"id":2,"name":"second","description":"","status":0,"attachment":null,"createdAt":"<time>","updatedAt":null}],"message":"","status":"OK","code":200}`},
+		{http.MethodGet, "/todo/v1/task/1", "", http.StatusOK,
+			`{"success":true,"data":{"id":1,"name":"write tests","description":"for the handlers","status":0,"attachment":null,"createdAt":"<time>","updatedAt":null},"message":"","status":"OK","code":200}`},
+		{http.MethodGet, "/todo/v1/task/9", "", http.StatusNotFound,
+			`{"success":false,"data":null,"message":"","status":"NOT_FOUND","code":404}`},
+		{http.MethodPut, "/todo/v1/task/1", `{"name":"renamed","status":1}`, http.StatusOK,
+			`{"success":true,"data":{"id":1,"name":"renamed","description":null,"status":1,"attachment":null,"createdAt":"<time>","updatedAt":"<time>"},"message":"","status":"OK","code":200}`},
+		{http.MethodPut, "/todo/v1/task/9", `{"name":"renamed"}`, http.StatusNotFound,
+			`{"success":false,"data":null,"message":"","status":"NOT_FOUND","code":404}`},
+		{http.MethodPut, "/todo/v1/task/1", `{"status":1}`, http.StatusBadRequest,
+			`{"success":false,"data":null,"message":"invalid 'Name' with value ''","status":"INVALID_PAYLOAD","code":400}`},
+		{http.MethodGet, "/todo/v1/task/1", "", http.StatusOK,
+			`{"success":true,"data":{"id":1,"name":"renamed","description":"","status":1,"attachment":null,"createdAt":"<time>","updatedAt":"<time>"},"message":"","status":"OK","code":200}`},
+	}
+
+	for _, step := range steps {
+		request := httptest.NewRequest(step.method, step.target, strings.NewReader(step.body))
+		request.SetBasicAuth("admin", "password")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		body := timestampPattern.ReplaceAllString(strings.TrimSpace(recorder.Body.String()), `"<time>"`)
+		if recorder.Code != step.code || body != step.expected {
+			t.Errorf("%s %s %s\nexpected %d %s\ngot      %d %s", step.method, step.target, step.body, step.code, step.expected, recorder.Code, body)
+		}
+	}
+}
@@ -4,9 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"path/filepath"
 	"strconv"
-	"strings"
+	"todo-app-api/pkg/attachment"
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/middleware"
 	"todo-app-api/pkg/response"
@@ -17,16 +16,18 @@ import (
 )
 
 type TaskHTTPHandler struct {
-	logger      *logrus.Logger
-	validator   *validator.Validate
-	taskUsecase TaskUsecase
+	logger           *logrus.Logger
+	validator        *validator.Validate
+	attachmentPolicy *attachment.Policy
+	taskUsecase      TaskUsecase
 }
 
-func NewTaskHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, taskUsecase TaskUsecase) {
+func NewTaskHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, attachmentPolicy *attachment.Policy, taskUsecase TaskUsecase) {
 	handler := &TaskHTTPHandler{
-		logger:      logger,
-		validator:   validator,
-		taskUsecase: taskUsecase,
+		logger:           logger,
+		validator:        validator,
+		attachmentPolicy: attachmentPolicy,
+		taskUsecase:      taskUsecase,
 	}
 	router.HandleFunc("/todo/v1/task", basicAuth.Verify(handler.GetManyTasks)).Methods(http.MethodGet)
 	router.HandleFunc("/todo/v1/task", basicAuth.Verify(handler.CreateTask)).Methods(http.MethodPost)
@@ -107,14 +108,16 @@ func (h TaskHTTPHandler) UploadAttachment(w http.ResponseWriter, r *http.Request
 
 	ctx := r.Context()
 
-	allowedFolderName := []string{"todo_attachment"}
-	if err := h.validateFolderName(allowedFolderName, folderName); err != nil {
+	rule, err := h.attachmentPolicy.Rule(folderName)
+	if err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
 		return
 	}
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
+	// leave some room for the other multipart fields, the file size itself is checked by the policy.
+	r.Body = http.MaxBytesReader(w, r.Body, rule.MaxSize+(1<<20))
+	if err := r.ParseMultipartForm(rule.MaxSize); err != nil {
 		h.logger.WithContext(ctx).Error(err)
 		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
@@ -139,19 +142,30 @@ func (h TaskHTTPHandler) UploadAttachment(w http.ResponseWriter, r *http.Request
 
 	defer file.Close()
 
-	payload.Attachment.File = file
-	payload.Attachment.FileName = fileHeader.Filename
-	payload.Attachment.Size = fileHeader.Size
-	payload.Attachment.FileExtension = filepath.Ext(fileHeader.Filename)
-	payload.Attachment.FileNameParam = fileNameParam
+	contentType, body, err := attachment.Sniff(file)
+	if err != nil {
+		h.logger.WithContext(ctx).Error(err)
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
 
-	allowedExtension := []string{".jpeg", ".jpg", ".png"}
-	if err := h.validateFileExtension(allowedExtension, payload.Attachment.FileExtension); err != nil {
+	if err := h.attachmentPolicy.Check(folderName, contentType, fileHeader.Size); err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
 		return
 	}
 
+	payload.Attachment.File = body
+	payload.Attachment.FileName = fileHeader.Filename
+	payload.Attachment.Size = fileHeader.Size
+	payload.Attachment.ContentType = contentType
+	payload.Attachment.FileExtension = attachment.Extension(contentType, fileHeader.Filename)
+	payload.Attachment.FileNameParam = fileNameParam
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		payload.Attachment.UploadedBy = principal.Subject
+	}
+
 	if err := h.validateRequestBody(payload); err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
@@ -174,27 +188,3 @@ func (h TaskHTTPHandler) validateRequestBody(body interface{}) (err error) {
 
 	return
 }
-
-func (h TaskHTTPHandler) validateFolderName(elems []string, v string) (err error) {
-	extension := strings.ToLower(v)
-	for _, s := range elems {
-		if extension == s {
-			return
-		}
-	}
-
-	err = fmt.Errorf("invalid bucket name '%s'", v)
-	return
-}
-
-func (h TaskHTTPHandler) validateFileExtension(elems []string, v string) (err error) {
-	extension := strings.ToLower(v)
-	for _, s := range elems {
-		if extension == s {
-			return
-		}
-	}
-
-	err = fmt.Errorf("invalid file extension '%s'", v)
-	return
-}
@@ -0,0 +1,77 @@
+package task_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	task "todo-app-api/cmd/task/v1"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/attachment"
+	"todo-app-api/pkg/memstore"
+	"todo-app-api/pkg/middleware"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestRouter() *mux.Router {
+	router := mux.NewRouter()
+	repository := core.NewMemoryTaskRepository(memstore.NewTable[int64, entity.Task]())
+	usecase := task.NewTaskUsecase(logrus.New(), core.NewTaskService(logrus.New(), time.UTC, nil, nil, core.Repositories{
+		Tasks:        repository,
+		Labels:       core.NewMemoryLabelRepository(memstore.NewTable[int64, entity.Label]()),
+		Checklists:   core.NewMemoryChecklistRepository(memstore.NewTable[int64, entity.ChecklistItem]()),
+		Dependencies: core.NewMemoryDependencyRepository(memstore.NewTable[core.DependencyKey, entity.Dependency]()),
+		Projects:     core.NewMemoryProjectRepository(memstore.NewTable[int64, entity.Project]()),
+		Comments:     core.NewMemoryCommentRepository(memstore.NewTable[int64, entity.Comment]()),
+		Activities:   core.NewMemoryActivityRepository(memstore.NewTable[int64, entity.TaskActivity]()),
+		Search:       core.NewInvertedIndex(),
+	}, core.TaskOptions{}))
+	policy := attachment.NewPolicy("bucket", "wr", "https://storage.googleapis.com", nil)
+	task.NewTaskHTTPHandler(logrus.New(), router, middleware.NewBasicAuth("admin", "password"), validator.New(), policy, usecase)
+	return router
+}
+
+func serve(router *mux.Router, method, target, body string) (recorder *httptest.ResponseRecorder, payload map[string]interface{}) {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.SetBasicAuth("admin", "password")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	json.Unmarshal(recorder.Body.Bytes(), &payload)
+	return
+}
+
+func TestTaskHTTPHandler(t *testing.T) {
+	router := newTestRouter()
+
+	recorder, payload := serve(router, http.MethodPost, "/todo/v1/task", `{"name":"write tests","description":"for the handlers"}`)
+	if recorder.Code != http.StatusOK || payload["data"].(map[string]interface{})["id"] != float64(1) {
+		t.Fatalf("unexpected response %d %v", recorder.Code, payload)
+	}
+
+	if recorder, _ := serve(router, http.MethodPost, "/todo/v1/task", `{"description":"no name"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected a missing name to be rejected, got %d", recorder.Code)
+	}
+
+	recorder, payload = serve(router, http.MethodGet, "/todo/v1/task/1", "")
+	data := payload["data"].(map[string]interface{})
+	if recorder.Code != http.StatusOK || data["name"] != "write tests" || data["description"] != "for the handlers" || data["status"] != float64(0) {
+		t.Errorf("unexpected task %d %v", recorder.Code, payload)
+	}
+
+	if recorder, _ := serve(router, http.MethodGet, "/todo/v1/task/2", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("expected a missing task, got %d", recorder.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/todo/v1/task", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected a request without credentials to be unauthorized, got %d", recorder.Code)
+	}
+}
@@ -3,6 +3,7 @@ package task
 import (
 	"io"
 	"time"
+	"todo-app-api/cmd/task/core"
 )
 
 type TaskResponse struct {
@@ -29,7 +30,18 @@ type UploadAttachmentRequest struct {
 		File          io.Reader `validate:"-"`
 		Size          int64     `validate:"-"`
 		FileName      string    `validate:"-"`
+		ContentType   string    `validate:"-"`
 		FileExtension string    `validate:"-"`
 		FileNameParam string    `validate:"-"`
+		UploadedBy    string    `validate:"-"`
 	} `validate:"-"`
 }
+
+func (r TaskRequest) core() core.TaskRequest {
+	return core.TaskRequest{
+		Name:        r.Name,
+		Description: r.Description,
+		Status:      r.Status,
+		Attachment:  r.Attachment,
+	}
+}
@@ -2,13 +2,13 @@ package task
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
-	"time"
+	"todo-app-api/cmd/task/core"
 	"todo-app-api/entity"
+	"todo-app-api/pkg/attachment"
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/response"
-	"todo-app-api/pkg/storage"
 
 	"github.com/sirupsen/logrus"
 )
@@ -21,46 +21,29 @@ type TaskUsecase interface {
 	UploadAttachment(ctx context.Context, folderName string, payload UploadAttachmentRequest) (resp response.Response)
 }
 
+// taskUsecase maps the v1 payloads onto the task service, the rules live in the core package.
 type taskUsecase struct {
-	logger         *logrus.Logger
-	location       *time.Location
-	storage        storage.Storage
-	taskRepository TaskRepository
+	logger      *logrus.Logger
+	taskService core.TaskService
 }
 
-func NewTaskUsecase(logger *logrus.Logger, location *time.Location, storage storage.Storage, taskRepository TaskRepository) TaskUsecase {
+func NewTaskUsecase(logger *logrus.Logger, taskService core.TaskService) TaskUsecase {
 	return &taskUsecase{
-		logger:         logger,
-		location:       location,
-		storage:        storage,
-		taskRepository: taskRepository,
+		logger:      logger,
+		taskService: taskService,
 	}
 }
 
 // GetManyTasks implements Usecase
 func (u *taskUsecase) GetManyTasks(ctx context.Context) (resp response.Response) {
-	result, err := u.taskRepository.FindMany(ctx)
+	result, err := u.taskService.GetManyTasks(ctx, core.Filter{})
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
-		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
 
-	totalDataOnPage := len(result)
-	tasksResponse := make([]TaskResponse, totalDataOnPage)
+	tasksResponse := make([]TaskResponse, len(result))
 	for i, v := range result {
-		var taskResponse TaskResponse
-		taskResponse.ID = v.ID
-		taskResponse.Name = v.Name
-		taskResponse.Description = &v.Description
-		taskResponse.Status = &v.Status
-		taskResponse.Attachment = v.Attachment
-		taskResponse.CreatedAt = v.CreatedAt
-		taskResponse.UpdatedAt = v.UpdatedAt
-
-		tasksResponse[i] = taskResponse
+		tasksResponse[i] = newTaskResponse(v)
 	}
 
 	return response.NewSuccessResponse(tasksResponse, response.StatOK, "")
@@ -68,47 +51,28 @@ func (u *taskUsecase) GetManyTasks(ctx context.Context) (resp response.Response)
 
 // GetOneTask implements Usecase
 func (u *taskUsecase) GetOneTask(ctx context.Context, id int64) (resp response.Response) {
-	result, err := u.taskRepository.FindOneById(ctx, id)
+	result, err := u.taskService.GetOneTask(ctx, id)
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
-		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
-	}
-
-	taskResponse := TaskResponse{
-		ID:          result.ID,
-		Name:        result.Name,
-		Description: &result.Description,
-		Status:      &result.Status,
-		Attachment:  result.Attachment,
-		CreatedAt:   result.CreatedAt,
-		UpdatedAt:   result.UpdatedAt,
+		return errorResponse(err)
 	}
 
-	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
+	return response.NewSuccessResponse(newTaskResponse(result), response.StatOK, "")
 }
 
 // CreateTask implements Usecase
 func (u *taskUsecase) CreateTask(ctx context.Context, taskRequest TaskRequest) (resp response.Response) {
-	taskStatus := entity.TaskStatusInitiate
-	createdAt := time.Now().In(u.location)
-
-	taskRequest.Status = &taskStatus
-	taskRequest.CreatedAt = createdAt
-
-	taskId, err := u.taskRepository.Save(ctx, taskRequest, nil)
+	task, err := u.taskService.CreateTask(ctx, taskRequest.core())
 	if err != nil {
 		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 	}
 
+	// v1 echoes the payload, the status is left out and the attachment is the one of the payload.
 	taskResponse := TaskResponse{
-		ID:          taskId,
+		ID:          task.ID,
 		Name:        taskRequest.Name,
 		Description: taskRequest.Description,
 		Attachment:  taskRequest.Attachment,
-		CreatedAt:   createdAt,
+		CreatedAt:   task.CreatedAt,
 	}
 
 	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
@@ -116,21 +80,19 @@ func (u *taskUsecase) CreateTask(ctx context.Context, taskRequest TaskRequest) (
 
 // UpdateTask implements Usecase
 func (u *taskUsecase) UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response) {
-	task, err := u.taskRepository.FindOneById(ctx, id)
+	// v1 knows nothing of the due dates, priorities, labels, parents and projects, an update keeps them.
+	existing, err := u.taskService.GetOneTask(ctx, id)
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
-		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
+	request := taskRequest.core()
+	request.DueAt, request.RemindAt = existing.DueAt, existing.RemindAt
+	request.Priority, request.LabelIDs = &existing.Priority, existing.LabelIDs
+	request.ParentID, request.ProjectID = existing.ParentID, existing.ProjectID
 
-	updatedAt := time.Now().In(u.location)
-	taskRequest.UpdatedAt = &updatedAt
-
-	err = u.taskRepository.UpdateById(ctx, id, taskRequest, nil)
+	task, err := u.taskService.UpdateTask(ctx, id, request)
 	if err != nil {
-		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
 
 	taskResponse := TaskResponse{
@@ -139,7 +101,7 @@ func (u *taskUsecase) UpdateTask(ctx context.Context, id int64, taskRequest Task
 		Description: taskRequest.Description,
 		Status:      taskRequest.Status,
 		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   &updatedAt,
+		UpdatedAt:   task.UpdatedAt,
 	}
 
 	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
@@ -147,21 +109,42 @@ func (u *taskUsecase) UpdateTask(ctx context.Context, id int64, taskRequest Task
 
 // UploadAttachment implements Usecase
 func (u *taskUsecase) UploadAttachment(ctx context.Context, folderName string, payload UploadAttachmentRequest) (resp response.Response) {
-	var attachment entity.Attachment
-	var url string
-
-	bucketName := "image-wreg"
-	fileName := fmt.Sprintf("wr/%s/%s%s", folderName, payload.Attachment.FileNameParam, payload.Attachment.FileExtension)
-
-	err := u.storage.PutObject(context.Background(), bucketName, fileName, payload.Attachment.File, "image/png", nil)
+	uploaded, err := u.taskService.UploadAttachment(ctx, folderName, attachment.File{
+		Body:        payload.Attachment.File,
+		Name:        payload.Attachment.FileNameParam,
+		Extension:   payload.Attachment.FileExtension,
+		ContentType: payload.Attachment.ContentType,
+		Size:        payload.Attachment.Size,
+		UploadedBy:  payload.Attachment.UploadedBy,
+	})
 	if err != nil {
-		u.logger.WithContext(ctx).Error(err)
+		if errors.Is(err, attachment.ErrInfected) {
+			return response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		}
 		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 	}
 
-	host := "https://storage.googleapis.com/"
-	url = fmt.Sprintf("%s%s/%s", host, bucketName, fileName)
-	attachment.ImageURL = url
+	return response.NewSuccessResponse(uploaded, response.StatOK, "")
+}
 
-	return response.NewSuccessResponse(attachment, response.StatOK, "")
+func newTaskResponse(task entity.Task) TaskResponse {
+	return TaskResponse{
+		ID:          task.ID,
+		Name:        task.Name,
+		Description: &task.Description,
+		Status:      &task.Status,
+		Attachment:  task.Attachment,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+}
+
+func errorResponse(err error) response.Response {
+	if err == exception.ErrNotFound {
+		return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+	}
+	if err == core.ErrOpenSubtasks || err == core.ErrBlocked {
+		return response.NewErrorResponse(err, http.StatusConflict, nil, response.StatNotPermitted, err.Error())
+	}
+	return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 }
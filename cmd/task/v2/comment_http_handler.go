@@ -0,0 +1,129 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/middleware"
+	"todo-app-api/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type CommentHTTPHandler struct {
+	logger         *logrus.Logger
+	validator      *validator.Validate
+	commentUsecase CommentUsecase
+}
+
+func NewCommentHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, commentUsecase CommentUsecase) {
+	handler := &CommentHTTPHandler{
+		logger:         logger,
+		validator:      validator,
+		commentUsecase: commentUsecase,
+	}
+	router.HandleFunc("/todo/v2/task/{id}/comment", basicAuth.Verify(handler.GetComments)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/task/{id}/comment", basicAuth.Verify(handler.AddComment)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/comment/{commentId}", basicAuth.Verify(handler.UpdateComment)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/task/{id}/comment/{commentId}", basicAuth.Verify(handler.DeleteComment)).Methods(http.MethodDelete)
+}
+
+// GetComments lists the comments of the task, the oldest first. limit sets the page size and cursor is the
+// nextCursor of the previous page.
+func (h CommentHTTPHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	qs := r.URL.Query()
+	limit := defaultCommentLimit
+	if qs.Get("limit") != "" {
+		parsed, err := strconv.Atoi(qs.Get("limit"))
+		if err != nil || parsed < 1 || parsed > maxCommentLimit {
+			resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, fmt.Sprintf("limit must be between 1 and %d", maxCommentLimit))
+			response.JSON(w, resp)
+			return
+		}
+		limit = parsed
+	}
+
+	var afterID int64
+	if qs.Get("cursor") != "" {
+		parsed, err := strconv.ParseInt(qs.Get("cursor"), 10, 64)
+		if err != nil {
+			resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "cursor must be a comment id")
+			response.JSON(w, resp)
+			return
+		}
+		afterID = parsed
+	}
+
+	resp := h.commentUsecase.GetComments(r.Context(), taskId, afterID, limit)
+	response.JSON(w, resp)
+}
+
+// AddComment comments on the task as the caller, the users that the comment mentions are notified.
+func (h CommentHTTPHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.commentUsecase.AddComment(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+// UpdateComment replaces the body of a comment of the caller.
+func (h CommentHTTPHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	commentId, _ := strconv.ParseInt(pathVariable["commentId"], 10, 64)
+
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.commentUsecase.UpdateComment(r.Context(), taskId, commentId, payload)
+	response.JSON(w, resp)
+}
+
+// DeleteComment deletes a comment of the caller.
+func (h CommentHTTPHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	commentId, _ := strconv.ParseInt(pathVariable["commentId"], 10, 64)
+
+	resp := h.commentUsecase.DeleteComment(r.Context(), taskId, commentId, author(r))
+	response.JSON(w, resp)
+}
+
+func (h CommentHTTPHandler) decodeRequestBody(w http.ResponseWriter, r *http.Request) (payload CommentRequest, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+
+	if err := h.validator.Struct(payload); err != nil {
+		errorField := err.(validator.ValidationErrors)[0]
+		err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+	payload.Author = author(r)
+	return payload, true
+}
+
+// author returns the subject of the caller, a user uuid for an api key.
+func author(r *http.Request) string {
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	return principal.Subject
+}
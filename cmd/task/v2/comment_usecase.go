@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+type CommentUsecase interface {
+	GetComments(ctx context.Context, taskID int64, afterID int64, limit int) (resp response.Response)
+	AddComment(ctx context.Context, taskID int64, commentRequest CommentRequest) (resp response.Response)
+	UpdateComment(ctx context.Context, taskID int64, id int64, commentRequest CommentRequest) (resp response.Response)
+	DeleteComment(ctx context.Context, taskID int64, id int64, author string) (resp response.Response)
+}
+
+type commentUsecase struct {
+	logger         *logrus.Logger
+	commentService core.CommentService
+}
+
+func NewCommentUsecase(logger *logrus.Logger, commentService core.CommentService) CommentUsecase {
+	return &commentUsecase{
+		logger:         logger,
+		commentService: commentService,
+	}
+}
+
+// GetComments implements CommentUsecase, the next cursor is the id of the last comment while there are more.
+func (u *commentUsecase) GetComments(ctx context.Context, taskID int64, afterID int64, limit int) (resp response.Response) {
+	// the comment past the page tells that there is a next page.
+	comments, total, err := u.commentService.GetComments(ctx, taskID, core.CommentPage{AfterID: afterID, Limit: limit + 1})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	meta := response.PaginationCursorResponseMeta{TotalData: int64(total)}
+	if len(comments) > limit {
+		comments = comments[:limit]
+		meta.NextCursor = comments[limit-1].ID
+	}
+	if afterID > 0 {
+		meta.PrevCursor = afterID
+	}
+
+	commentsResponse := make([]CommentResponse, len(comments))
+	for i, v := range comments {
+		commentsResponse[i] = newCommentResponse(v)
+	}
+	meta.TotalDataOnPage = int64(len(commentsResponse))
+
+	return response.NewSuccessResponseWithMeta(commentsResponse, meta, response.StatOK, "")
+}
+
+// AddComment implements CommentUsecase
+func (u *commentUsecase) AddComment(ctx context.Context, taskID int64, commentRequest CommentRequest) (resp response.Response) {
+	comment, err := u.commentService.AddComment(ctx, taskID, commentRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newCommentResponse(comment), response.StatCreated, "")
+}
+
+// UpdateComment implements CommentUsecase
+func (u *commentUsecase) UpdateComment(ctx context.Context, taskID int64, id int64, commentRequest CommentRequest) (resp response.Response) {
+	comment, err := u.commentService.UpdateComment(ctx, taskID, id, commentRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newCommentResponse(comment), response.StatOK, "")
+}
+
+// DeleteComment implements CommentUsecase
+func (u *commentUsecase) DeleteComment(ctx context.Context, taskID int64, id int64, author string) (resp response.Response) {
+	if err := u.commentService.DeleteComment(ctx, taskID, id, author); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func newCommentResponse(comment entity.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
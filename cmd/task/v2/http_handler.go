@@ -1,49 +1,83 @@
 package task
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/pkg/attachment"
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/middleware"
 	"todo-app-api/pkg/response"
+	"unicode/utf8"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
+const tusVersion = "1.0.0"
+
 type TaskHTTPHandler struct {
-	logger      *logrus.Logger
-	validator   *validator.Validate
-	taskUsecase TaskUsecase
+	logger           *logrus.Logger
+	validator        *validator.Validate
+	attachmentPolicy *attachment.Policy
+	taskUsecase      TaskUsecase
 }
 
-func NewTaskHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, taskUsecase TaskUsecase) {
+func NewTaskHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, attachmentPolicy *attachment.Policy, taskUsecase TaskUsecase) {
 	handler := &TaskHTTPHandler{
-		logger:      logger,
-		validator:   validator,
-		taskUsecase: taskUsecase,
+		logger:           logger,
+		validator:        validator,
+		attachmentPolicy: attachmentPolicy,
+		taskUsecase:      taskUsecase,
 	}
 	router.HandleFunc("/todo/v2/task", basicAuth.Verify(handler.GetManyTasks)).Methods(http.MethodGet)
 	router.HandleFunc("/todo/v2/task", basicAuth.Verify(handler.CreateTask)).Methods(http.MethodPost)
 	router.HandleFunc("/todo/v2/task/{id}", basicAuth.Verify(handler.GetOneTask)).Methods(http.MethodGet)
 	router.HandleFunc("/todo/v2/task/{id}", basicAuth.Verify(handler.UpdateTask)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/task/{id}/attachment", basicAuth.Verify(handler.DeleteTaskAttachment)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/task/{id}/checklist", basicAuth.Verify(handler.GetChecklist)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/task/{id}/checklist", basicAuth.Verify(handler.AddChecklistItem)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/checklist", basicAuth.Verify(handler.ReorderChecklist)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/task/{id}/checklist/{itemId}", basicAuth.Verify(handler.UpdateChecklistItem)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/task/{id}/checklist/{itemId}", basicAuth.Verify(handler.DeleteChecklistItem)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/task/{id}/move", basicAuth.Verify(handler.MoveTask)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/dependency", basicAuth.Verify(handler.AddDependency)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/dependency/{blockerId}", basicAuth.Verify(handler.RemoveDependency)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/task/{id}/graph", basicAuth.Verify(handler.GetGraph)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/task/{id}/activity", basicAuth.Verify(handler.GetActivities)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/task/{id}/assignee", basicAuth.Verify(handler.Assign)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/assignee/{userId}", basicAuth.Verify(handler.Unassign)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/task/{id}/watcher", basicAuth.Verify(handler.Watch)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/{id}/watcher/{userId}", basicAuth.Verify(handler.Unwatch)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/board", basicAuth.Verify(handler.GetBoard)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/board/task/{id}/move", basicAuth.Verify(handler.MoveOnBoard)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/attachment", basicAuth.Verify(handler.DeleteAttachment)).Methods(http.MethodDelete)
 	router.HandleFunc("/todo/v2/task/attachment/{bucket}", basicAuth.Verify(handler.UploadAttachment)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/attachment/{bucket}/uploads", basicAuth.Verify(handler.CreateUpload)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/task/attachment/{bucket}/uploads/{id}", basicAuth.Verify(handler.HeadUpload)).Methods(http.MethodHead)
+	router.HandleFunc("/todo/v2/task/attachment/{bucket}/uploads/{id}", basicAuth.Verify(handler.GetUpload)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/task/attachment/{bucket}/uploads/{id}", basicAuth.Verify(handler.PatchUpload)).Methods(http.MethodPatch)
+	router.HandleFunc("/todo/v2/task/attachment/{bucket}/uploads/{id}", basicAuth.Verify(handler.TerminateUpload)).Methods(http.MethodDelete)
 }
 
 func (h TaskHTTPHandler) GetManyTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	qs := r.URL.Query()
-	filter := GetManyTaskRequest{}
-
-	if qs.Get("name") != "" {
-		nameQs := qs.Get("name")
-		filter.Name = &nameQs
+	filter, err := parseGetManyTaskRequest(r.URL.Query(), callerUser(r.Context()))
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
 	}
 
 	resp := h.taskUsecase.GetManyTasks(ctx, filter)
@@ -103,7 +137,18 @@ func (h TaskHTTPHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp = h.taskUsecase.UpdateTask(ctx, taskId, payload)
+	// a task with open subtasks is only completed when forced.
+	payload.Force, _ = strconv.ParseBool(r.URL.Query().Get("force"))
+
+	// an occurrence of a recurring task is updated alone, unless the whole series is in scope.
+	switch r.URL.Query().Get("scope") {
+	case "", "occurrence":
+		resp = h.taskUsecase.UpdateTask(ctx, taskId, payload)
+	case "series":
+		resp = h.taskUsecase.UpdateSeries(ctx, taskId, payload)
+	default:
+		resp = response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "scope must be occurrence or series")
+	}
 	response.JSON(w, resp)
 }
 
@@ -116,14 +161,16 @@ func (h TaskHTTPHandler) UploadAttachment(w http.ResponseWriter, r *http.Request
 
 	ctx := r.Context()
 
-	allowedFolderName := []string{"todo_attachment"}
-	if err := h.validateFolderName(allowedFolderName, folderName); err != nil {
+	rule, err := h.attachmentPolicy.Rule(folderName)
+	if err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
 		return
 	}
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
+	// leave some room for the other multipart fields, the file size itself is checked by the policy.
+	r.Body = http.MaxBytesReader(w, r.Body, rule.MaxSize+(1<<20))
+	if err := r.ParseMultipartForm(rule.MaxSize); err != nil {
 		h.logger.WithContext(ctx).Error(err)
 		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
@@ -148,62 +195,664 @@ func (h TaskHTTPHandler) UploadAttachment(w http.ResponseWriter, r *http.Request
 
 	defer file.Close()
 
-	payload.Attachment.File = file
+	contentType, body, err := attachment.Sniff(file)
+	if err != nil {
+		h.logger.WithContext(ctx).Error(err)
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+
+	if err := h.attachmentPolicy.Check(folderName, contentType, fileHeader.Size); err != nil {
+		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+
+	payload.Attachment.File = body
 	payload.Attachment.FileName = fileHeader.Filename
 	payload.Attachment.Size = fileHeader.Size
-	payload.Attachment.FileExtension = filepath.Ext(fileHeader.Filename)
+	payload.Attachment.ContentType = contentType
+	payload.Attachment.FileExtension = attachment.Extension(contentType, fileHeader.Filename)
 	payload.Attachment.FileNameParam = fileNameParam
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		payload.Attachment.UploadedBy = principal.Subject
+	}
+
+	if err := h.validateRequestBody(payload); err != nil {
+		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+
+	resp = h.taskUsecase.UploadAttachment(ctx, folderName, payload)
+	response.JSON(w, resp)
+}
+
+// DeleteTaskAttachment unlinks the attachment of the task and deletes it.
+func (h TaskHTTPHandler) DeleteTaskAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.taskUsecase.DeleteTaskAttachment(ctx, taskId)
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.taskUsecase.GetChecklist(r.Context(), taskId)
+	response.JSON(w, resp)
+}
+
+// AddChecklistItem adds an item at the end of the checklist of the task.
+func (h TaskHTTPHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	var payload CreateChecklistItemRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.AddChecklistItem(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+// UpdateChecklistItem renames or toggles an item, the fields that are left out are kept.
+func (h TaskHTTPHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	var payload UpdateChecklistItemRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	itemId, _ := strconv.ParseInt(pathVariable["itemId"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.UpdateChecklistItem(r.Context(), taskId, itemId, payload)
+	response.JSON(w, resp)
+}
+
+// ReorderChecklist puts the items in the order of the payload, which lists every item of the checklist.
+func (h TaskHTTPHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	var payload ReorderChecklistRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.ReorderChecklist(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	itemId, _ := strconv.ParseInt(pathVariable["itemId"], 10, 64)
+	resp := h.taskUsecase.DeleteChecklistItem(r.Context(), taskId, itemId)
+	response.JSON(w, resp)
+}
+
+// MoveTask places the task between its new neighbours in the manual order of sort=manual.
+func (h TaskHTTPHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	var payload MoveTaskRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.MoveTask(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+// GetBoard returns the tasks by status, a page of every column. The cursor of a column reads its next page only,
+// and the task filters of GetManyTasks narrow every column down.
+func (h TaskHTTPHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qs := r.URL.Query()
+	filter, err := parseGetManyTaskRequest(qs, callerUser(r.Context()))
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+
+	request, err := parseGetBoardRequest(qs)
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+	request.Filter = filter
+
+	resp := h.taskUsecase.GetBoard(ctx, request)
+	response.JSON(w, resp)
+}
+
+// MoveOnBoard moves the task to another column and to its place in the column at once.
+func (h TaskHTTPHandler) MoveOnBoard(w http.ResponseWriter, r *http.Request) {
+	var payload BoardMoveTaskRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.MoveOnBoard(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+// AddDependency marks the task as blocked by another task, a dependency that would close a cycle is rejected.
+func (h TaskHTTPHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	var payload DependencyRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	resp := h.taskUsecase.AddDependency(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	blockerId, _ := strconv.ParseInt(pathVariable["blockerId"], 10, 64)
+	resp := h.taskUsecase.RemoveDependency(r.Context(), taskId, blockerId)
+	response.JSON(w, resp)
+}
+
+// Assign assigns the task to the user of the payload.
+func (h TaskHTTPHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	var payload TaskUserRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	userID, err := userOrCaller(r, payload.UserID)
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+	payload.UserID = userID
+	resp := h.taskUsecase.Assign(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) Unassign(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	userID, err := userOrCaller(r, pathVariable["userId"])
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+	resp := h.taskUsecase.Unassign(r.Context(), taskId, userID)
+	response.JSON(w, resp)
+}
+
+// Watch makes the user of the payload watch the task, the watchers are notified of its changes.
+func (h TaskHTTPHandler) Watch(w http.ResponseWriter, r *http.Request) {
+	var payload TaskUserRequest
+
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	if !h.decodeRequestBody(w, r, &payload) {
+		return
+	}
+
+	userID, err := userOrCaller(r, payload.UserID)
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+	payload.UserID = userID
+	resp := h.taskUsecase.Watch(r.Context(), taskId, payload)
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) Unwatch(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	userID, err := userOrCaller(r, pathVariable["userId"])
+	if err != nil {
+		resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return
+	}
+	resp := h.taskUsecase.Unwatch(r.Context(), taskId, userID)
+	response.JSON(w, resp)
+}
+
+// GetGraph returns the tasks that block the task and the tasks that it blocks, transitively.
+func (h TaskHTTPHandler) GetGraph(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.taskUsecase.GetGraph(r.Context(), taskId)
+	response.JSON(w, resp)
+}
+
+// GetActivities lists the history of the task, the newest first. limit sets the page size and cursor is the
+// nextCursor of the previous page.
+func (h TaskHTTPHandler) GetActivities(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	taskId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
 
-	allowedExtension := []string{".jpeg", ".jpg", ".png"}
-	if err := h.validateFileExtension(allowedExtension, payload.Attachment.FileExtension); err != nil {
+	qs := r.URL.Query()
+	limit := defaultActivityLimit
+	if qs.Get("limit") != "" {
+		parsed, err := strconv.Atoi(qs.Get("limit"))
+		if err != nil || parsed < 1 || parsed > maxActivityLimit {
+			resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, fmt.Sprintf("limit must be between 1 and %d", maxActivityLimit))
+			response.JSON(w, resp)
+			return
+		}
+		limit = parsed
+	}
+
+	var beforeID int64
+	if qs.Get("cursor") != "" {
+		parsed, err := strconv.ParseInt(qs.Get("cursor"), 10, 64)
+		if err != nil {
+			resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "cursor must be an activity id")
+			response.JSON(w, resp)
+			return
+		}
+		beforeID = parsed
+	}
+
+	resp := h.taskUsecase.GetActivities(r.Context(), taskId, beforeID, limit)
+	response.JSON(w, resp)
+}
+
+// DeleteAttachment deletes an uploaded attachment that is not linked to any task.
+func (h TaskHTTPHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	var resp response.Response
+	var payload DeleteAttachmentRequest
+
+	ctx := r.Context()
+
+	payload.URL = r.URL.Query().Get("url")
+	if err := h.validateRequestBody(payload); err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
 		return
 	}
 
+	resp = h.taskUsecase.DeleteAttachment(ctx, payload)
+	response.JSON(w, resp)
+}
+
+// CreateUpload starts a resumable upload, following the creation extension of the tus protocol.
+func (h TaskHTTPHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	var resp response.Response
+	var payload CreateUploadRequest
+
+	pathVariable := mux.Vars(r)
+	folderName := pathVariable["bucket"]
+
+	ctx := r.Context()
+
+	if !h.validateTusVersion(w, r) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil {
+		resp = response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "Invalid Upload-Length header")
+		response.JSON(w, resp)
+		return
+	}
+
+	fileName := parseUploadMetadata(r.Header.Get("Upload-Metadata"))["filename"]
+	payload.FileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	payload.Length = length
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		payload.UploadedBy = principal.Subject
+	}
+
 	if err := h.validateRequestBody(payload); err != nil {
 		resp = response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
 		response.JSON(w, resp)
 		return
 	}
 
-	resp = h.taskUsecase.UploadAttachment(ctx, folderName, payload)
+	resp = h.taskUsecase.CreateUpload(ctx, folderName, payload)
+	if upload, ok := resp.Data().(UploadResponse); ok {
+		w.Header().Set("Location", fmt.Sprintf("%s/%s", strings.TrimSuffix(r.URL.Path, "/"), upload.ID))
+		h.writeUploadHeaders(w, upload)
+	}
 	response.JSON(w, resp)
 }
 
-func (h TaskHTTPHandler) validateRequestBody(body interface{}) (err error) {
-	err = h.validator.Struct(body)
-	if err == nil {
+// HeadUpload returns the offset of a resumable upload, so the client knows where to resume.
+func (h TaskHTTPHandler) HeadUpload(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	ctx := r.Context()
+
+	resp := h.taskUsecase.GetUpload(ctx, pathVariable["bucket"], pathVariable["id"])
+	if upload, ok := resp.Data().(UploadResponse); ok {
+		h.writeUploadHeaders(w, upload)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(resp.HTTPStatusCode())
+}
+
+func (h TaskHTTPHandler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	ctx := r.Context()
+
+	resp := h.taskUsecase.GetUpload(ctx, pathVariable["bucket"], pathVariable["id"])
+	if upload, ok := resp.Data().(UploadResponse); ok {
+		h.writeUploadHeaders(w, upload)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, resp)
+}
+
+// PatchUpload appends a chunk at the Upload-Offset of a resumable upload.
+func (h TaskHTTPHandler) PatchUpload(w http.ResponseWriter, r *http.Request) {
+	var resp response.Response
+
+	pathVariable := mux.Vars(r)
+	ctx := r.Context()
+
+	if !h.validateTusVersion(w, r) {
 		return
 	}
 
-	errorFields := err.(validator.ValidationErrors)
-	errorField := errorFields[0]
-	err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		resp = response.NewErrorResponse(exception.ErrBadRequest, http.StatusUnsupportedMediaType, nil, response.StatusInvalidPayload, "Content-Type must be application/offset+octet-stream")
+		response.JSON(w, resp)
+		return
+	}
 
-	return
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		resp = response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "Invalid Upload-Offset header")
+		response.JSON(w, resp)
+		return
+	}
+
+	resp = h.taskUsecase.PatchUpload(ctx, pathVariable["bucket"], pathVariable["id"], offset, r.Body)
+	if upload, ok := resp.Data().(UploadResponse); ok {
+		h.writeUploadHeaders(w, upload)
+	}
+	response.JSON(w, resp)
 }
 
-func (h TaskHTTPHandler) validateFolderName(elems []string, v string) (err error) {
-	extension := strings.ToLower(v)
-	for _, s := range elems {
-		if extension == s {
-			return
+// TerminateUpload cancels a resumable upload, following the termination extension of the tus protocol.
+func (h TaskHTTPHandler) TerminateUpload(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	ctx := r.Context()
+
+	if !h.validateTusVersion(w, r) {
+		return
+	}
+
+	resp := h.taskUsecase.TerminateUpload(ctx, pathVariable["bucket"], pathVariable["id"])
+	response.JSON(w, resp)
+}
+
+func (h TaskHTTPHandler) writeUploadHeaders(w http.ResponseWriter, upload UploadResponse) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// validateTusVersion rejects the clients that speak another version of the tus protocol.
+// The header is optional, so plain http clients can use the endpoints too.
+func (h TaskHTTPHandler) validateTusVersion(w http.ResponseWriter, r *http.Request) bool {
+	version := r.Header.Get("Tus-Resumable")
+	if version == "" || version == tusVersion {
+		return true
+	}
+
+	w.Header().Set("Tus-Version", tusVersion)
+	resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusPreconditionFailed, nil, response.StatusInvalidPayload, fmt.Sprintf("unsupported tus version '%s'", version))
+	response.JSON(w, resp)
+	return false
+}
+
+// maxQueryLength is the number of characters of a full-text search at most.
+const maxQueryLength = 200
+
+// parseGetManyTaskRequest reads the task filters of the query string, the assignee me is the caller, a user uuid
+// that is empty when the caller isn't a user.
+// The relative dates of due and due_before are resolved by the service when the filter runs.
+// The error is the message of a bad request.
+func parseGetManyTaskRequest(qs url.Values, caller string) (filter GetManyTaskRequest, err error) {
+	if qs.Get("name") != "" {
+		nameQs := qs.Get("name")
+		filter.Name = &nameQs
+	}
+
+	if query := strings.TrimSpace(qs.Get("q")); query != "" {
+		if utf8.RuneCountInString(query) > maxQueryLength {
+			return filter, fmt.Errorf("q must be at most %d characters", maxQueryLength)
+		}
+		filter.Query = query
+	}
+
+	filter.Overdue, _ = strconv.ParseBool(qs.Get("overdue"))
+	filter.DueToday, _ = strconv.ParseBool(qs.Get("due_today"))
+	filter.Unassigned, _ = strconv.ParseBool(qs.Get("unassigned"))
+
+	if qs.Get("assignee") != "" {
+		assignee := qs.Get("assignee")
+		if assignee == "me" {
+			if caller == "" {
+				return filter, errMeRequiresUser
+			}
+			assignee = caller
+		}
+		filter.Assignee = &assignee
+	}
+
+	if qs.Get("due") != "" {
+		if !isPeriod(qs.Get("due")) {
+			return filter, fmt.Errorf("due must be one of %s", strings.Join(core.Periods, ", "))
+		}
+		filter.DuePeriod = qs.Get("due")
+	}
+
+	if value := qs.Get("due_before"); isPeriod(value) {
+		filter.DueBeforePeriod = value
+	} else if value != "" {
+		dueBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.New("due_before must be a RFC 3339 date time or a relative date like this_week")
+		}
+		filter.DueBefore = &dueBefore
+	}
+
+	if qs.Get("parent") != "" {
+		parentID, err := strconv.ParseInt(qs.Get("parent"), 10, 64)
+		if err != nil {
+			return filter, errors.New("parent must be a task id")
+		}
+		filter.ParentID = &parentID
+	}
+
+	if qs.Get("project") != "" {
+		projectID, err := strconv.ParseInt(qs.Get("project"), 10, 64)
+		if err != nil {
+			return filter, errors.New("project must be a project id")
+		}
+		filter.ProjectID = &projectID
+	}
+
+	for _, name := range splitQuery(qs.Get("priority")) {
+		priority := priorityOf(&name)
+		if priority == nil {
+			return filter, errors.New("priority must be low, medium, high or urgent")
+		}
+		filter.Priorities = append(filter.Priorities, *priority)
+	}
+
+	for _, value := range splitQuery(qs.Get("label")) {
+		labelID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errors.New("label must be a comma separated list of label ids")
+		}
+		filter.LabelIDs = append(filter.LabelIDs, labelID)
+	}
+
+	switch qs.Get("label_match") {
+	case "", "any":
+	case "all":
+		filter.AllLabels = true
+	default:
+		return filter, errors.New("label_match must be any or all")
+	}
+
+	switch qs.Get("sort") {
+	case "", core.SortPriority, core.SortPriorityDesc, core.SortManual:
+		filter.Sort = qs.Get("sort")
+	default:
+		return filter, errors.New("sort must be priority, -priority or manual")
+	}
+	return filter, nil
+}
+
+// isPeriod tells whether the value is a relative period of core.Periods.
+func isPeriod(value string) bool {
+	for _, period := range core.Periods {
+		if value == period {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGetBoardRequest reads the columns, the page size and the cursor of the board, the error is the message of a bad request.
+func parseGetBoardRequest(qs url.Values) (request GetBoardRequest, err error) {
+	for _, value := range splitQuery(qs.Get("status")) {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return request, errors.New("status must be a comma separated list of statuses")
 		}
+		request.Statuses = append(request.Statuses, status)
 	}
 
-	err = fmt.Errorf("invalid bucket name '%s'", v)
+	request.Limit = defaultBoardLimit
+	if qs.Get("limit") != "" {
+		if request.Limit, err = strconv.Atoi(qs.Get("limit")); err != nil || request.Limit < 1 || request.Limit > maxBoardLimit {
+			return request, fmt.Errorf("limit must be between 1 and %d", maxBoardLimit)
+		}
+	}
+
+	if qs.Get("cursor") != "" {
+		cursor, err := decodeBoardCursor(qs.Get("cursor"))
+		if err != nil {
+			return request, errors.New("cursor must be the nextCursor of a column")
+		}
+		request.Cursor = &cursor
+	}
+	return request, nil
+}
+
+// errMeRequiresUser rejects a me of a caller that isn't a user, the basic auth user has no user uuid.
+var errMeRequiresUser = errors.New("me requires a user principal")
+
+// callerUser returns the user uuid of the caller, empty when the caller isn't authenticated by an api key.
+func callerUser(ctx context.Context) string {
+	if principal, ok := middleware.PrincipalFromContext(ctx); ok && principal.Method == "api_key" {
+		return principal.Subject
+	}
+	return ""
+}
+
+// userOrCaller resolves me to the user uuid of the caller, errMeRequiresUser is returned when the caller isn't a user.
+func userOrCaller(r *http.Request, userID string) (string, error) {
+	if userID != "me" {
+		return userID, nil
+	}
+	if caller := callerUser(r.Context()); caller != "" {
+		return caller, nil
+	}
+	return "", errMeRequiresUser
+}
+
+// splitQuery splits a comma separated query value, the empty items are left out.
+func splitQuery(value string) (items []string) {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
 	return
 }
 
-func (h TaskHTTPHandler) validateFileExtension(elems []string, v string) (err error) {
-	extension := strings.ToLower(v)
-	for _, s := range elems {
-		if extension == s {
-			return
+// parseUploadMetadata parses the Upload-Metadata header, a comma separated list of keys and base64 encoded values.
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		if len(parts) == 0 {
+			continue
+		}
+
+		value := ""
+		if len(parts) > 1 {
+			if decoded, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+				value = string(decoded)
+			}
 		}
+		metadata[parts[0]] = value
+	}
+	return metadata
+}
+
+// decodeRequestBody decodes and validates the json body into payload, the error response is written when it fails.
+func (h TaskHTTPHandler) decodeRequestBody(w http.ResponseWriter, r *http.Request, payload interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return false
+	}
+
+	if err := h.validateRequestBody(payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return false
 	}
+	return true
+}
+
+func (h TaskHTTPHandler) validateRequestBody(body interface{}) (err error) {
+	err = h.validator.Struct(body)
+	if err == nil {
+		return
+	}
+
+	errorFields := err.(validator.ValidationErrors)
+	errorField := errorFields[0]
+	err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
 
-	err = fmt.Errorf("invalid file extension '%s'", v)
 	return
 }
@@ -0,0 +1,34 @@
+package task_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	task "todo-app-api/cmd/task/v2"
+	"todo-app-api/pkg/attachment"
+	"todo-app-api/pkg/middleware"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMeRequiresAUser(t *testing.T) {
+	router := mux.NewRouter()
+	policy := attachment.NewPolicy("bucket", "wr", "https://storage.googleapis.com", nil)
+	task.NewTaskHTTPHandler(logrus.New(), router, middleware.NewBasicAuth("admin", "password"), validator.New(), policy, newTestUsecase())
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/todo/v2/task?assignee=me", nil),
+		httptest.NewRequest(http.MethodPost, "/todo/v2/task/1/assignee", strings.NewReader(`{"userId":"me"}`)),
+		httptest.NewRequest(http.MethodDelete, "/todo/v2/task/1/watcher/me", nil),
+	} {
+		request.SetBasicAuth("admin", "password")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "me requires a user principal") {
+			t.Errorf("expected me of the basic auth user to be rejected by %s %s, got %d %s", request.Method, request.URL, recorder.Code, recorder.Body)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"todo-app-api/pkg/middleware"
+	"todo-app-api/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type LabelHTTPHandler struct {
+	logger       *logrus.Logger
+	validator    *validator.Validate
+	labelUsecase LabelUsecase
+}
+
+func NewLabelHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, labelUsecase LabelUsecase) {
+	handler := &LabelHTTPHandler{
+		logger:       logger,
+		validator:    validator,
+		labelUsecase: labelUsecase,
+	}
+	router.HandleFunc("/todo/v2/label", basicAuth.Verify(handler.GetManyLabels)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/label", basicAuth.Verify(handler.CreateLabel)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/label/{id}", basicAuth.Verify(handler.GetOneLabel)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/label/{id}", basicAuth.Verify(handler.UpdateLabel)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/label/{id}", basicAuth.Verify(handler.DeleteLabel)).Methods(http.MethodDelete)
+}
+
+func (h LabelHTTPHandler) GetManyLabels(w http.ResponseWriter, r *http.Request) {
+	resp := h.labelUsecase.GetManyLabels(r.Context())
+	response.JSON(w, resp)
+}
+
+func (h LabelHTTPHandler) GetOneLabel(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	labelId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.labelUsecase.GetOneLabel(r.Context(), labelId)
+	response.JSON(w, resp)
+}
+
+func (h LabelHTTPHandler) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.labelUsecase.CreateLabel(r.Context(), payload)
+	response.JSON(w, resp)
+}
+
+func (h LabelHTTPHandler) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	labelId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.labelUsecase.UpdateLabel(r.Context(), labelId, payload)
+	response.JSON(w, resp)
+}
+
+// DeleteLabel deletes the label and removes it from its tasks.
+func (h LabelHTTPHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	labelId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.labelUsecase.DeleteLabel(r.Context(), labelId)
+	response.JSON(w, resp)
+}
+
+func (h LabelHTTPHandler) decodeRequestBody(w http.ResponseWriter, r *http.Request) (payload LabelRequest, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+
+	if err := h.validator.Struct(payload); err != nil {
+		errorField := err.(validator.ValidationErrors)[0]
+		err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+	return payload, true
+}
@@ -0,0 +1,97 @@
+package task
+
+import (
+	"context"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+type LabelUsecase interface {
+	GetManyLabels(ctx context.Context) (resp response.Response)
+	GetOneLabel(ctx context.Context, id int64) (resp response.Response)
+	CreateLabel(ctx context.Context, labelRequest LabelRequest) (resp response.Response)
+	UpdateLabel(ctx context.Context, id int64, labelRequest LabelRequest) (resp response.Response)
+	DeleteLabel(ctx context.Context, id int64) (resp response.Response)
+}
+
+type labelUsecase struct {
+	logger       *logrus.Logger
+	labelService core.LabelService
+}
+
+func NewLabelUsecase(logger *logrus.Logger, labelService core.LabelService) LabelUsecase {
+	return &labelUsecase{
+		logger:       logger,
+		labelService: labelService,
+	}
+}
+
+// GetManyLabels implements LabelUsecase
+func (u *labelUsecase) GetManyLabels(ctx context.Context) (resp response.Response) {
+	labels, err := u.labelService.GetManyLabels(ctx)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newLabelResponses(labels), response.StatOK, "")
+}
+
+// GetOneLabel implements LabelUsecase
+func (u *labelUsecase) GetOneLabel(ctx context.Context, id int64) (resp response.Response) {
+	label, err := u.labelService.GetOneLabel(ctx, id)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newLabelResponse(label), response.StatOK, "")
+}
+
+// CreateLabel implements LabelUsecase
+func (u *labelUsecase) CreateLabel(ctx context.Context, labelRequest LabelRequest) (resp response.Response) {
+	label, err := u.labelService.CreateLabel(ctx, labelRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newLabelResponse(label), response.StatCreated, "")
+}
+
+// UpdateLabel implements LabelUsecase
+func (u *labelUsecase) UpdateLabel(ctx context.Context, id int64, labelRequest LabelRequest) (resp response.Response) {
+	label, err := u.labelService.UpdateLabel(ctx, id, labelRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newLabelResponse(label), response.StatOK, "")
+}
+
+// DeleteLabel implements LabelUsecase
+func (u *labelUsecase) DeleteLabel(ctx context.Context, id int64) (resp response.Response) {
+	if err := u.labelService.DeleteLabel(ctx, id); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func newLabelResponse(label entity.Label) LabelResponse {
+	return LabelResponse{
+		ID:        label.ID,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
+
+func newLabelResponses(labels []entity.Label) []LabelResponse {
+	labelsResponse := make([]LabelResponse, len(labels))
+	for i, v := range labels {
+		labelsResponse[i] = newLabelResponse(v)
+	}
+	return labelsResponse
+}
@@ -1,31 +1,287 @@
 package task
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"io"
 	"time"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
 )
 
 type GetManyTaskRequest struct {
 	Name *string `json:"name"`
+	// Query is a full-text search of the name and the description, the most relevant tasks come first unless sorted.
+	Query     string     `json:"q"`
+	Overdue   bool       `json:"overdue"`
+	DueToday  bool       `json:"due_today"`
+	DueBefore *time.Time `json:"due_before"`
+	// DuePeriod and DueBeforePeriod are relative dates, see core.Periods.
+	DuePeriod       string  `json:"due"`
+	DueBeforePeriod string  `json:"due_before_period"`
+	Priorities      []int   `json:"priority"`
+	LabelIDs        []int64 `json:"label"`
+	AllLabels       bool    `json:"label_match"`
+	Sort            string  `json:"sort"`
+	ParentID        *int64  `json:"parent"`
+	ProjectID       *int64  `json:"project"`
+	// Assignee is a user uuid, me is resolved to the caller.
+	Assignee   *string `json:"assignee"`
+	Unassigned bool    `json:"unassigned"`
 }
 
 type TaskResponse struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	Status      *int            `json:"status"`
+	Priority    string          `json:"priority"`
+	Labels      []LabelResponse `json:"labels"`
+	Assignees   []string        `json:"assignees"`
+	Watchers    []string        `json:"watchers"`
+	ParentID    *int64          `json:"parentId"`
+	ProjectID   *int64          `json:"projectId"`
+	Rank        string          `json:"rank"`
+	// Progress counts the done checklist items.
+	Progress     entity.TaskProgress `json:"progress"`
+	CommentCount int                 `json:"commentCount"`
+	Attachment   *string             `json:"attachment"`
+	DueAt        *time.Time          `json:"dueAt"`
+	RemindAt     *time.Time          `json:"remindAt"`
+	Recurrence   *string             `json:"recurrence"`
+	SeriesID     *int64              `json:"seriesId"`
+	Occurrence   int                 `json:"occurrence"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    *time.Time          `json:"updatedAt"`
+	// Highlight is the html of the matches of a search, the words of the query are marked with <mark>.
+	Highlight *HighlightResponse `json:"highlight,omitempty"`
+}
+
+// HighlightResponse is the highlighted name and a snippet of the description, empty when the description doesn't match.
+type HighlightResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type TaskRequest struct {
+	Name        string  `json:"name" validate:"required"`
+	Description *string `json:"description" validate:"-"`
+	Status      *int    `json:"status" validate:"-"`
+	Priority    *string `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	LabelIDs    []int64 `json:"labelIds" validate:"-"`
+	ParentID    *int64  `json:"parentId" validate:"-"`
+	ProjectID   *int64  `json:"projectId" validate:"-"`
+	// Force completes a task whose subtasks are open, it is set from the query string.
+	Force      bool       `json:"-" validate:"-"`
+	Attachment *string    `json:"attachment" validate:"-"`
+	DueAt      *time.Time `json:"dueAt" validate:"-"`
+	RemindAt   *time.Time `json:"remindAt" validate:"-"`
+	Recurrence *string    `json:"recurrence" validate:"-"`
+	CreatedAt  time.Time  `json:"createdAt" validate:"-"`
+	UpdatedAt  *time.Time `json:"updatedAt" validate:"-"`
+}
+
+type CreateChecklistItemRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	Done bool   `json:"done" validate:"-"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Name string `json:"name" validate:"max=255"`
+	Done *bool  `json:"done" validate:"-"`
+}
+
+type ReorderChecklistRequest struct {
+	ItemIDs []int64 `json:"itemIds" validate:"required"`
+}
+
+type ChecklistItemResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Done      bool       `json:"done"`
+	Position  int        `json:"position"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// MoveTaskRequest places a task after the task AfterID and before the task BeforeID, one of them is enough.
+type MoveTaskRequest struct {
+	AfterID  *int64 `json:"afterId" validate:"required_without=BeforeID"`
+	BeforeID *int64 `json:"beforeId" validate:"required_without=AfterID"`
+}
+
+// The page size of a board column.
+const (
+	defaultBoardLimit = 20
+	maxBoardLimit     = 100
+)
+
+// GetBoardRequest reads the board, a cursor reads the next page of its column only.
+type GetBoardRequest struct {
+	Filter   GetManyTaskRequest
+	Statuses []int
+	Limit    int
+	Cursor   *boardCursor
+}
+
+// boardCursor is the position of the last task of a page of a column.
+// It is sent as url safe base64 of its json, clients pass it back as is.
+type boardCursor struct {
+	Status int    `json:"s"`
+	Rank   string `json:"r"`
+	ID     int64  `json:"i"`
+}
+
+// BoardMoveTaskRequest moves a task to the column of the status, after the task AfterID and before the task BeforeID.
+// A task without neighbours keeps its place in the manual order.
+type BoardMoveTaskRequest struct {
+	Status   *int   `json:"status" validate:"required"`
+	AfterID  *int64 `json:"afterId" validate:"-"`
+	BeforeID *int64 `json:"beforeId" validate:"-"`
+}
+
+type BoardResponse struct {
+	Columns []BoardColumnResponse `json:"columns"`
+}
+
+type BoardColumnResponse struct {
+	Status int            `json:"status"`
+	Tasks  []TaskResponse `json:"tasks"`
+	// Count is the number of tasks of the column, every page included.
+	Count      int     `json:"count"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// TaskUserRequest assigns or watches a task, the userId is a user uuid or me for the caller.
+type TaskUserRequest struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+type DependencyRequest struct {
+	BlockerID int64 `json:"blockerId" validate:"required"`
+}
+
+type DependencyResponse struct {
+	TaskID    int64     `json:"taskId"`
+	BlockerID int64     `json:"blockerId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GraphResponse struct {
+	TaskID     int64                   `json:"taskId"`
+	Upstream   DependencyGraphResponse `json:"upstream"`
+	Downstream DependencyGraphResponse `json:"downstream"`
+}
+
+type DependencyGraphResponse struct {
+	Tasks []TaskResponse       `json:"tasks"`
+	Edges []DependencyResponse `json:"edges"`
+}
+
+type ProjectRequest struct {
+	Name        string `json:"name" validate:"required,max=128"`
+	Description string `json:"description" validate:"max=4096"`
+}
+
+type ProjectResponse struct {
 	ID          int64      `json:"id"`
 	Name        string     `json:"name"`
-	Description *string    `json:"description"`
-	Status      *int       `json:"status"`
-	Attachment  *string    `json:"attachment"`
+	Description string     `json:"description"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archivedAt"`
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   *time.Time `json:"updatedAt"`
 }
 
-type TaskRequest struct {
-	Name        string     `json:"name" validate:"required"`
-	Description *string    `json:"description" validate:"-"`
-	Status      *int       `json:"status" validate:"-"`
-	Attachment  *string    `json:"attachment" validate:"-"`
-	CreatedAt   time.Time  `json:"createdAt" validate:"-"`
-	UpdatedAt   *time.Time `json:"updatedAt" validate:"-"`
+// ProjectListMeta holds the number of tasks of every listed project by status, keyed by project id.
+type ProjectListMeta struct {
+	Counts map[int64]StatusCountsResponse `json:"counts"`
+}
+
+type StatusCountsResponse struct {
+	Initiate   int `json:"initiate"`
+	OnProgress int `json:"onProgress"`
+	Done       int `json:"done"`
+	Total      int `json:"total"`
+}
+
+// The page size of the comments of a task.
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+)
+
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+	// Author is the subject of the caller, it is set from the principal.
+	Author string `json:"-" validate:"-"`
+}
+
+type ViewRequest struct {
+	Name string `json:"name" validate:"required,max=128"`
+	// Query is the query string of GET /todo/v2/task, like priority=high&due=this_week.
+	Query  string `json:"query" validate:"max=2048"`
+	Sort   string `json:"sort" validate:"omitempty,oneof=priority -priority manual"`
+	Shared bool   `json:"shared"`
+	// Owner is the subject of the caller, it is set from the principal.
+	Owner string `json:"-" validate:"-"`
+}
+
+type ViewResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Owner     string     `json:"owner"`
+	Query     string     `json:"query"`
+	Sort      string     `json:"sort"`
+	Shared    bool       `json:"shared"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+type CommentResponse struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"taskId"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// The page size of the activities of a task.
+const (
+	defaultActivityLimit = 20
+	maxActivityLimit     = 100
+)
+
+type ActivityResponse struct {
+	ID           int64                `json:"id"`
+	TaskID       int64                `json:"taskId"`
+	Action       string               `json:"action"`
+	Actor        string               `json:"actor"`
+	ClientDevice ClientDeviceResponse `json:"clientDevice"`
+	// Changes name the fields like the task entity, the values are the stored ones.
+	Changes   []entity.FieldChange `json:"changes"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+type ClientDeviceResponse struct {
+	RemoteAddress string `json:"remoteAddress"`
+	XForwardedFor string `json:"xForwardedFor"`
+	XRealIP       string `json:"xRealIp"`
+	UserAgent     string `json:"userAgent"`
+}
+
+type LabelRequest struct {
+	Name  string `json:"name" validate:"required,max=64"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+type LabelResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
 }
 
 type UploadAttachmentRequest struct {
@@ -33,7 +289,141 @@ type UploadAttachmentRequest struct {
 		File          io.Reader `validate:"-"`
 		Size          int64     `validate:"-"`
 		FileName      string    `validate:"-"`
+		ContentType   string    `validate:"-"`
 		FileExtension string    `validate:"-"`
 		FileNameParam string    `validate:"-"`
+		UploadedBy    string    `validate:"-"`
 	} `validate:"-"`
 }
+
+type CreateUploadRequest struct {
+	FileName   string `validate:"required"`
+	Length     int64  `validate:"gt=0"`
+	UploadedBy string `validate:"-"`
+}
+
+type UploadResponse struct {
+	ID         string             `json:"id"`
+	Offset     int64              `json:"offset"`
+	Length     int64              `json:"length"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	Completed  bool               `json:"completed"`
+	Attachment *entity.Attachment `json:"attachment"`
+}
+
+type DeleteAttachmentRequest struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+func (r GetManyTaskRequest) core() core.Filter {
+	return core.Filter{
+		Name:            r.Name,
+		Query:           r.Query,
+		Overdue:         r.Overdue,
+		DueToday:        r.DueToday,
+		DueBefore:       r.DueBefore,
+		DuePeriod:       r.DuePeriod,
+		DueBeforePeriod: r.DueBeforePeriod,
+		Priorities:      r.Priorities,
+		LabelIDs:        r.LabelIDs,
+		AllLabels:       r.AllLabels,
+		Sort:            r.Sort,
+		ParentID:        r.ParentID,
+		ProjectID:       r.ProjectID,
+		Assignee:        r.Assignee,
+		Unassigned:      r.Unassigned,
+	}
+}
+
+func (r GetBoardRequest) core() core.BoardRequest {
+	request := core.BoardRequest{Filter: r.Filter.core(), Statuses: r.Statuses, Limit: r.Limit}
+	if r.Cursor != nil {
+		request.Statuses = []int{r.Cursor.Status}
+		request.After = &core.Position{Rank: r.Cursor.Rank, ID: r.Cursor.ID}
+	}
+	return request
+}
+
+func (r BoardMoveTaskRequest) core() core.BoardMoveRequest {
+	return core.BoardMoveRequest{Status: *r.Status, MoveRequest: core.MoveRequest{AfterID: r.AfterID, BeforeID: r.BeforeID}}
+}
+
+func (r TaskRequest) core() core.TaskRequest {
+	return core.TaskRequest{
+		Name:        r.Name,
+		Description: r.Description,
+		Status:      r.Status,
+		Priority:    priorityOf(r.Priority),
+		LabelIDs:    r.LabelIDs,
+		ParentID:    r.ParentID,
+		ProjectID:   r.ProjectID,
+		Force:       r.Force,
+		Attachment:  r.Attachment,
+		DueAt:       r.DueAt,
+		RemindAt:    r.RemindAt,
+		Recurrence:  r.Recurrence,
+	}
+}
+
+func (r CreateChecklistItemRequest) core() core.ChecklistItemRequest {
+	return core.ChecklistItemRequest{Name: r.Name, Done: &r.Done}
+}
+
+func (r UpdateChecklistItemRequest) core() core.ChecklistItemRequest {
+	return core.ChecklistItemRequest{Name: r.Name, Done: r.Done}
+}
+
+func (r MoveTaskRequest) core() core.MoveRequest {
+	return core.MoveRequest{AfterID: r.AfterID, BeforeID: r.BeforeID}
+}
+
+func (r ProjectRequest) core() core.ProjectRequest {
+	return core.ProjectRequest{Name: r.Name, Description: r.Description}
+}
+
+func (r CommentRequest) core() core.CommentRequest {
+	return core.CommentRequest{Author: r.Author, Body: r.Body}
+}
+
+func (r ViewRequest) core() core.ViewRequest {
+	return core.ViewRequest{Owner: r.Owner, Name: r.Name, Query: r.Query, Sort: r.Sort, Shared: r.Shared}
+}
+
+func (r LabelRequest) core() core.LabelRequest {
+	return core.LabelRequest{Name: r.Name, Color: r.Color}
+}
+
+func encodeBoardCursor(status int, position core.Position) string {
+	value, _ := json.Marshal(boardCursor{Status: status, Rank: position.Rank, ID: position.ID})
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func decodeBoardCursor(value string) (cursor boardCursor, err error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(decoded, &cursor)
+	return
+}
+
+// priorityOf returns the priority of a name, nil for a missing or unknown name.
+func priorityOf(name *string) *int {
+	if name == nil {
+		return nil
+	}
+	for priority, priorityName := range entity.TaskPriorityNames {
+		if priorityName == *name {
+			return &priority
+		}
+	}
+	return nil
+}
+
+// priorityName returns the name of a priority, the lowest priority for an unknown one.
+func priorityName(priority int) string {
+	if priority < 0 || priority >= len(entity.TaskPriorityNames) {
+		return entity.TaskPriorityNames[entity.TaskPriorityLow]
+	}
+	return entity.TaskPriorityNames[priority]
+}
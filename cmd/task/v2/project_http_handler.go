@@ -0,0 +1,123 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/middleware"
+	"todo-app-api/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectHTTPHandler struct {
+	logger         *logrus.Logger
+	validator      *validator.Validate
+	projectUsecase ProjectUsecase
+}
+
+func NewProjectHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, projectUsecase ProjectUsecase) {
+	handler := &ProjectHTTPHandler{
+		logger:         logger,
+		validator:      validator,
+		projectUsecase: projectUsecase,
+	}
+	router.HandleFunc("/todo/v2/project", basicAuth.Verify(handler.GetManyProjects)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/project", basicAuth.Verify(handler.CreateProject)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/project/{id}", basicAuth.Verify(handler.GetOneProject)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/project/{id}", basicAuth.Verify(handler.UpdateProject)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/project/{id}", basicAuth.Verify(handler.DeleteProject)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/project/{id}/archive", basicAuth.Verify(handler.ArchiveProject)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/project/{id}/archive", basicAuth.Verify(handler.RestoreProject)).Methods(http.MethodDelete)
+}
+
+// GetManyProjects lists the projects, archived=true or archived=false narrows them down.
+func (h ProjectHTTPHandler) GetManyProjects(w http.ResponseWriter, r *http.Request) {
+	var archived *bool
+	if value := r.URL.Query().Get("archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp := response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, "archived must be true or false")
+			response.JSON(w, resp)
+			return
+		}
+		archived = &parsed
+	}
+
+	resp := h.projectUsecase.GetManyProjects(r.Context(), archived)
+	response.JSON(w, resp)
+}
+
+func (h ProjectHTTPHandler) GetOneProject(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	projectId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.projectUsecase.GetOneProject(r.Context(), projectId)
+	response.JSON(w, resp)
+}
+
+func (h ProjectHTTPHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.projectUsecase.CreateProject(r.Context(), payload)
+	response.JSON(w, resp)
+}
+
+func (h ProjectHTTPHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	projectId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.projectUsecase.UpdateProject(r.Context(), projectId, payload)
+	response.JSON(w, resp)
+}
+
+// ArchiveProject archives the project, its tasks are hidden from the task list.
+func (h ProjectHTTPHandler) ArchiveProject(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	projectId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.projectUsecase.ArchiveProject(r.Context(), projectId, true)
+	response.JSON(w, resp)
+}
+
+func (h ProjectHTTPHandler) RestoreProject(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	projectId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.projectUsecase.ArchiveProject(r.Context(), projectId, false)
+	response.JSON(w, resp)
+}
+
+// DeleteProject deletes the project, its tasks are kept without a project.
+func (h ProjectHTTPHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	projectId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.projectUsecase.DeleteProject(r.Context(), projectId)
+	response.JSON(w, resp)
+}
+
+func (h ProjectHTTPHandler) decodeRequestBody(w http.ResponseWriter, r *http.Request) (payload ProjectRequest, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+
+	if err := h.validator.Struct(payload); err != nil {
+		errorField := err.(validator.ValidationErrors)[0]
+		err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+	return payload, true
+}
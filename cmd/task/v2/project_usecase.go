@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectUsecase interface {
+	GetManyProjects(ctx context.Context, archived *bool) (resp response.Response)
+	GetOneProject(ctx context.Context, id int64) (resp response.Response)
+	CreateProject(ctx context.Context, projectRequest ProjectRequest) (resp response.Response)
+	UpdateProject(ctx context.Context, id int64, projectRequest ProjectRequest) (resp response.Response)
+	ArchiveProject(ctx context.Context, id int64, archived bool) (resp response.Response)
+	DeleteProject(ctx context.Context, id int64) (resp response.Response)
+}
+
+type projectUsecase struct {
+	logger         *logrus.Logger
+	projectService core.ProjectService
+}
+
+func NewProjectUsecase(logger *logrus.Logger, projectService core.ProjectService) ProjectUsecase {
+	return &projectUsecase{
+		logger:         logger,
+		projectService: projectService,
+	}
+}
+
+// GetManyProjects implements ProjectUsecase, the meta holds the number of tasks of the projects by status.
+func (u *projectUsecase) GetManyProjects(ctx context.Context, archived *bool) (resp response.Response) {
+	projects, counts, err := u.projectService.GetManyProjects(ctx, core.ProjectFilter{Archived: archived})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	meta := ProjectListMeta{Counts: make(map[int64]StatusCountsResponse, len(projects))}
+	projectsResponse := make([]ProjectResponse, len(projects))
+	for i, v := range projects {
+		projectsResponse[i] = newProjectResponse(v)
+		meta.Counts[v.ID] = newStatusCountsResponse(counts[v.ID])
+	}
+
+	return response.NewSuccessResponseWithMeta(projectsResponse, meta, response.StatOK, "")
+}
+
+// GetOneProject implements ProjectUsecase
+func (u *projectUsecase) GetOneProject(ctx context.Context, id int64) (resp response.Response) {
+	project, err := u.projectService.GetOneProject(ctx, id)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newProjectResponse(project), response.StatOK, "")
+}
+
+// CreateProject implements ProjectUsecase
+func (u *projectUsecase) CreateProject(ctx context.Context, projectRequest ProjectRequest) (resp response.Response) {
+	project, err := u.projectService.CreateProject(ctx, projectRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newProjectResponse(project), response.StatCreated, "")
+}
+
+// UpdateProject implements ProjectUsecase
+func (u *projectUsecase) UpdateProject(ctx context.Context, id int64, projectRequest ProjectRequest) (resp response.Response) {
+	project, err := u.projectService.UpdateProject(ctx, id, projectRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newProjectResponse(project), response.StatOK, "")
+}
+
+// ArchiveProject implements ProjectUsecase
+func (u *projectUsecase) ArchiveProject(ctx context.Context, id int64, archived bool) (resp response.Response) {
+	project, err := u.projectService.ArchiveProject(ctx, id, archived)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newProjectResponse(project), response.StatOK, "")
+}
+
+// DeleteProject implements ProjectUsecase
+func (u *projectUsecase) DeleteProject(ctx context.Context, id int64) (resp response.Response) {
+	if err := u.projectService.DeleteProject(ctx, id); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func newProjectResponse(project entity.Project) ProjectResponse {
+	return ProjectResponse{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Archived:    project.ArchivedAt != nil,
+		ArchivedAt:  project.ArchivedAt,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
+
+func newStatusCountsResponse(counts entity.StatusCounts) StatusCountsResponse {
+	return StatusCountsResponse{
+		Initiate:   counts.Initiate,
+		OnProgress: counts.OnProgress,
+		Done:       counts.Done,
+		Total:      counts.Initiate + counts.OnProgress + counts.Done,
+	}
+}
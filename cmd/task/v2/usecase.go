@@ -2,13 +2,14 @@ package task
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
-	"time"
+	"todo-app-api/cmd/task/core"
 	"todo-app-api/entity"
+	"todo-app-api/pkg/attachment"
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/response"
-	"todo-app-api/pkg/storage"
 
 	"github.com/sirupsen/logrus"
 )
@@ -18,150 +19,562 @@ type TaskUsecase interface {
 	GetOneTask(ctx context.Context, id int64) (resp response.Response)
 	CreateTask(ctx context.Context, taskRequest TaskRequest) (resp response.Response)
 	UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response)
+	UpdateSeries(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response)
 	UploadAttachment(ctx context.Context, folderName string, payload UploadAttachmentRequest) (resp response.Response)
+	CreateUpload(ctx context.Context, folderName string, payload CreateUploadRequest) (resp response.Response)
+	GetUpload(ctx context.Context, folderName string, id string) (resp response.Response)
+	PatchUpload(ctx context.Context, folderName string, id string, offset int64, chunk io.Reader) (resp response.Response)
+	TerminateUpload(ctx context.Context, folderName string, id string) (resp response.Response)
+	DeleteTaskAttachment(ctx context.Context, id int64) (resp response.Response)
+	DeleteAttachment(ctx context.Context, payload DeleteAttachmentRequest) (resp response.Response)
+	GetChecklist(ctx context.Context, id int64) (resp response.Response)
+	AddChecklistItem(ctx context.Context, id int64, payload CreateChecklistItemRequest) (resp response.Response)
+	UpdateChecklistItem(ctx context.Context, id int64, itemID int64, payload UpdateChecklistItemRequest) (resp response.Response)
+	ReorderChecklist(ctx context.Context, id int64, payload ReorderChecklistRequest) (resp response.Response)
+	DeleteChecklistItem(ctx context.Context, id int64, itemID int64) (resp response.Response)
+	MoveTask(ctx context.Context, id int64, payload MoveTaskRequest) (resp response.Response)
+	GetBoard(ctx context.Context, request GetBoardRequest) (resp response.Response)
+	MoveOnBoard(ctx context.Context, id int64, payload BoardMoveTaskRequest) (resp response.Response)
+	AddDependency(ctx context.Context, id int64, payload DependencyRequest) (resp response.Response)
+	RemoveDependency(ctx context.Context, id int64, blockerID int64) (resp response.Response)
+	GetGraph(ctx context.Context, id int64) (resp response.Response)
+	GetActivities(ctx context.Context, id int64, beforeID int64, limit int) (resp response.Response)
+	Assign(ctx context.Context, id int64, payload TaskUserRequest) (resp response.Response)
+	Unassign(ctx context.Context, id int64, userID string) (resp response.Response)
+	Watch(ctx context.Context, id int64, payload TaskUserRequest) (resp response.Response)
+	Unwatch(ctx context.Context, id int64, userID string) (resp response.Response)
 }
 
+// taskUsecase maps the v2 payloads onto the task service, the resumable uploads are v2 only.
 type taskUsecase struct {
-	logger         *logrus.Logger
-	location       *time.Location
-	storage        storage.Storage
-	taskRepository TaskRepository
+	logger            *logrus.Logger
+	taskService       core.TaskService
+	resumableUploader *attachment.ResumableUploader
 }
 
-func NewTaskUsecase(logger *logrus.Logger, location *time.Location, storage storage.Storage, taskRepository TaskRepository) TaskUsecase {
+func NewTaskUsecase(logger *logrus.Logger, taskService core.TaskService, resumableUploader *attachment.ResumableUploader) TaskUsecase {
 	return &taskUsecase{
-		logger:         logger,
-		location:       location,
-		storage:        storage,
-		taskRepository: taskRepository,
+		logger:            logger,
+		taskService:       taskService,
+		resumableUploader: resumableUploader,
 	}
 }
 
 // GetManyTasks implements Usecase
 func (u *taskUsecase) GetManyTasks(ctx context.Context, filter GetManyTaskRequest) (resp response.Response) {
-	result, err := u.taskRepository.FindMany(ctx, filter)
+	result, err := u.taskService.GetManyTasks(ctx, filter.core())
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
-		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
 
-	totalDataOnPage := len(result)
-	tasksResponse := make([]TaskResponse, totalDataOnPage)
-	for i, v := range result {
-		var taskResponse TaskResponse
-		taskResponse.ID = v.ID
-		taskResponse.Name = v.Name
-		taskResponse.Description = &v.Description
-		taskResponse.Status = &v.Status
-		taskResponse.Attachment = v.Attachment
-		taskResponse.CreatedAt = v.CreatedAt
-		taskResponse.UpdatedAt = v.UpdatedAt
+	return response.NewSuccessResponse(newTaskResponses(result), response.StatOK, "")
+}
 
-		tasksResponse[i] = taskResponse
+// GetOneTask implements Usecase
+func (u *taskUsecase) GetOneTask(ctx context.Context, id int64) (resp response.Response) {
+	result, err := u.taskService.GetOneTask(ctx, id)
+	if err != nil {
+		return errorResponse(err)
 	}
 
-	return response.NewSuccessResponse(tasksResponse, response.StatOK, "")
+	return response.NewSuccessResponse(newTaskResponse(result), response.StatOK, "")
 }
 
-// GetOneTask implements Usecase
-func (u *taskUsecase) GetOneTask(ctx context.Context, id int64) (resp response.Response) {
-	result, err := u.taskRepository.FindOneById(ctx, id)
+// CreateTask implements Usecase
+func (u *taskUsecase) CreateTask(ctx context.Context, taskRequest TaskRequest) (resp response.Response) {
+	task, err := u.taskService.CreateTask(ctx, taskRequest.core())
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+		if errors.Is(err, core.ErrInvalidRecurrence) || errors.Is(err, core.ErrInvalidParent) || err == core.ErrUnknownLabel || err == core.ErrUnknownProject {
+			return errorResponse(err)
 		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 	}
 
 	taskResponse := TaskResponse{
-		ID:          result.ID,
-		Name:        result.Name,
-		Description: &result.Description,
-		Status:      &result.Status,
-		Attachment:  result.Attachment,
-		CreatedAt:   result.CreatedAt,
-		UpdatedAt:   result.UpdatedAt,
+		ID:           task.ID,
+		Name:         taskRequest.Name,
+		Description:  taskRequest.Description,
+		Priority:     priorityName(task.Priority),
+		Labels:       newLabelResponses(task.Labels),
+		Assignees:    task.Assignees,
+		Watchers:     task.Watchers,
+		ParentID:     task.ParentID,
+		ProjectID:    task.ProjectID,
+		Rank:         task.Rank,
+		Progress:     task.Progress,
+		CommentCount: task.CommentCount,
+		Attachment:   taskRequest.Attachment,
+		DueAt:        task.DueAt,
+		RemindAt:     task.RemindAt,
+		Recurrence:   task.Recurrence,
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		CreatedAt:    task.CreatedAt,
 	}
 
 	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
 }
 
-// CreateTask implements Usecase
-func (u *taskUsecase) CreateTask(ctx context.Context, taskRequest TaskRequest) (resp response.Response) {
-	taskStatus := entity.TaskStatusInitiate
-	createdAt := time.Now().In(u.location)
+// UpdateTask implements Usecase
+func (u *taskUsecase) UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response) {
+	task, err := u.taskService.UpdateTask(ctx, id, taskRequest.core())
+	return updateResponse(id, taskRequest, task, err)
+}
 
-	taskRequest.Status = &taskStatus
-	taskRequest.CreatedAt = createdAt
+// UpdateSeries implements Usecase
+func (u *taskUsecase) UpdateSeries(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response) {
+	task, err := u.taskService.UpdateSeries(ctx, id, taskRequest.core())
+	return updateResponse(id, taskRequest, task, err)
+}
 
-	taskId, err := u.taskRepository.Save(ctx, taskRequest, nil)
+func updateResponse(id int64, taskRequest TaskRequest, task entity.Task, err error) (resp response.Response) {
 	if err != nil {
-		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
 
 	taskResponse := TaskResponse{
-		ID:          taskId,
-		Name:        taskRequest.Name,
-		Description: taskRequest.Description,
-		Attachment:  taskRequest.Attachment,
-		CreatedAt:   createdAt,
+		ID:           id,
+		Name:         taskRequest.Name,
+		Description:  taskRequest.Description,
+		Status:       taskRequest.Status,
+		Priority:     priorityName(task.Priority),
+		Labels:       newLabelResponses(task.Labels),
+		Assignees:    task.Assignees,
+		Watchers:     task.Watchers,
+		ParentID:     task.ParentID,
+		ProjectID:    task.ProjectID,
+		Rank:         task.Rank,
+		Progress:     task.Progress,
+		CommentCount: task.CommentCount,
+		DueAt:        task.DueAt,
+		RemindAt:     task.RemindAt,
+		Recurrence:   task.Recurrence,
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
 	}
 
 	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
 }
 
-// UpdateTask implements Usecase
-func (u *taskUsecase) UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response) {
-	task, err := u.taskRepository.FindOneById(ctx, id)
+// UploadAttachment implements Usecase
+func (u *taskUsecase) UploadAttachment(ctx context.Context, folderName string, payload UploadAttachmentRequest) (resp response.Response) {
+	uploaded, err := u.taskService.UploadAttachment(ctx, folderName, attachment.File{
+		Body:        payload.Attachment.File,
+		Name:        payload.Attachment.FileNameParam,
+		Extension:   payload.Attachment.FileExtension,
+		ContentType: payload.Attachment.ContentType,
+		Size:        payload.Attachment.Size,
+		UploadedBy:  payload.Attachment.UploadedBy,
+	})
 	if err != nil {
-		if err == exception.ErrNotFound {
-			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+		if errors.Is(err, attachment.ErrInfected) {
+			return response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
 		}
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 	}
 
-	updatedAt := time.Now().In(u.location)
-	taskRequest.UpdatedAt = &updatedAt
+	return response.NewSuccessResponse(uploaded, response.StatOK, "")
+}
 
-	err = u.taskRepository.UpdateById(ctx, id, taskRequest, nil)
+// CreateUpload implements Usecase
+func (u *taskUsecase) CreateUpload(ctx context.Context, folderName string, payload CreateUploadRequest) (resp response.Response) {
+	session, err := u.resumableUploader.Create(ctx, folderName, payload.FileName, payload.Length, payload.UploadedBy)
 	if err != nil {
+		return u.uploadErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(u.uploadResponse(session, nil), response.StatCreated, "")
+}
+
+// GetUpload implements Usecase
+func (u *taskUsecase) GetUpload(ctx context.Context, folderName string, id string) (resp response.Response) {
+	session, err := u.resumableUploader.Get(ctx, folderName, id)
+	if err != nil {
+		return u.uploadErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(u.uploadResponse(session, u.resumableUploader.Attachment(session)), response.StatOK, "")
+}
+
+// PatchUpload implements Usecase
+func (u *taskUsecase) PatchUpload(ctx context.Context, folderName string, id string, offset int64, chunk io.Reader) (resp response.Response) {
+	session, uploaded, err := u.resumableUploader.WriteChunk(ctx, folderName, id, offset, chunk)
+	if err != nil {
+		return u.uploadErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(u.uploadResponse(session, uploaded), response.StatUpdated, "")
+}
+
+// TerminateUpload implements Usecase
+func (u *taskUsecase) TerminateUpload(ctx context.Context, folderName string, id string) (resp response.Response) {
+	if err := u.resumableUploader.Terminate(ctx, folderName, id); err != nil {
+		return u.uploadErrorResponse(ctx, err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func (u *taskUsecase) uploadResponse(session entity.UploadSession, uploaded *entity.Attachment) UploadResponse {
+	return UploadResponse{
+		ID:         session.ID,
+		Offset:     session.Offset,
+		Length:     session.Length,
+		ExpiresAt:  session.ExpiresAt,
+		Completed:  session.CompletedAt != nil,
+		Attachment: uploaded,
+	}
+}
+
+func (u *taskUsecase) uploadErrorResponse(ctx context.Context, err error) (resp response.Response) {
+	switch {
+	case errors.Is(err, exception.ErrNotFound):
+		return response.NewErrorResponse(exception.ErrNotFound, http.StatusNotFound, nil, response.StatNotFound, "")
+	case errors.Is(err, exception.ErrConflict):
+		return response.NewErrorResponse(exception.ErrConflict, http.StatusConflict, nil, response.StatusInvalidPayload, "upload offset does not match")
+	case errors.Is(err, attachment.ErrChunkTooLarge):
+		return response.NewErrorResponse(err, http.StatusRequestEntityTooLarge, nil, response.StatusInvalidPayload, err.Error())
+	case errors.Is(err, attachment.ErrInfected):
+		return response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+	case errors.Is(err, attachment.ErrPolicy):
+		return response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+	}
+
+	u.logger.WithContext(ctx).Error(err)
+	return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+}
+
+// DeleteTaskAttachment implements Usecase
+func (u *taskUsecase) DeleteTaskAttachment(ctx context.Context, id int64) (resp response.Response) {
+	if err := u.taskService.DeleteTaskAttachment(ctx, id); err != nil {
+		if err == core.ErrNoAttachment {
+			return response.NewErrorResponse(exception.ErrNotFound, http.StatusNotFound, nil, response.StatNotFound, err.Error())
+		}
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+// DeleteAttachment implements Usecase
+func (u *taskUsecase) DeleteAttachment(ctx context.Context, payload DeleteAttachmentRequest) (resp response.Response) {
+	if err := u.taskService.DeleteAttachment(ctx, payload.URL); err != nil {
+		switch err {
+		case exception.ErrNotFound:
+			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+		case exception.ErrConflict:
+			return response.NewErrorResponse(err, http.StatusConflict, nil, response.StatNotPermitted, "attachment is still linked to a task")
+		}
 		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
 	}
 
-	taskResponse := TaskResponse{
-		ID:          id,
-		Name:        taskRequest.Name,
-		Description: taskRequest.Description,
-		Status:      taskRequest.Status,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   &updatedAt,
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func newTaskResponse(task entity.Task) TaskResponse {
+	return TaskResponse{
+		ID:           task.ID,
+		Name:         task.Name,
+		Description:  &task.Description,
+		Status:       &task.Status,
+		Priority:     priorityName(task.Priority),
+		Labels:       newLabelResponses(task.Labels),
+		Assignees:    task.Assignees,
+		Watchers:     task.Watchers,
+		ParentID:     task.ParentID,
+		ProjectID:    task.ProjectID,
+		Rank:         task.Rank,
+		Progress:     task.Progress,
+		CommentCount: task.CommentCount,
+		Attachment:   task.Attachment,
+		DueAt:        task.DueAt,
+		RemindAt:     task.RemindAt,
+		Recurrence:   task.Recurrence,
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+		Highlight:    newHighlightResponse(task.Highlight),
 	}
+}
 
-	return response.NewSuccessResponse(taskResponse, response.StatOK, "")
+func newTaskResponses(tasks []entity.Task) []TaskResponse {
+	tasksResponse := make([]TaskResponse, len(tasks))
+	for i, v := range tasks {
+		tasksResponse[i] = newTaskResponse(v)
+	}
+	return tasksResponse
 }
 
-// UploadAttachment implements Usecase
-func (u *taskUsecase) UploadAttachment(ctx context.Context, folderName string, payload UploadAttachmentRequest) (resp response.Response) {
-	var attachment entity.Attachment
-	var url string
+func newHighlightResponse(highlight *entity.TaskHighlight) *HighlightResponse {
+	if highlight == nil {
+		return nil
+	}
+	return &HighlightResponse{Name: highlight.Name, Description: highlight.Description}
+}
 
-	bucketName := "image-wreg"
-	fileName := fmt.Sprintf("wr/%s/%s%s", folderName, payload.Attachment.FileNameParam, payload.Attachment.FileExtension)
+func errorResponse(err error) response.Response {
+	if err == exception.ErrNotFound {
+		return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+	}
+	if errors.Is(err, core.ErrInvalidRecurrence) || errors.Is(err, core.ErrInvalidParent) || errors.Is(err, core.ErrInvalidDependency) || errors.Is(err, core.ErrInvalidMove) || err == core.ErrUnknownLabel || err == core.ErrUnknownProject || err == core.ErrInvalidChecklistOrder || err == core.ErrEmptyComment || err == core.ErrUnknownUser {
+		return response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+	}
+	if err == exception.ErrForbidden {
+		return response.NewErrorResponse(err, http.StatusForbidden, nil, response.StatForbidden, "")
+	}
+	if err == exception.ErrConflict {
+		return response.NewErrorResponse(err, http.StatusConflict, nil, response.StatNotPermitted, "")
+	}
+	if err == core.ErrOpenSubtasks || err == core.ErrBlocked || errors.Is(err, core.ErrDependencyCycle) {
+		return response.NewErrorResponse(err, http.StatusConflict, nil, response.StatNotPermitted, err.Error())
+	}
+	return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+}
 
-	err := u.storage.PutObject(context.Background(), bucketName, fileName, payload.Attachment.File, "image/png", nil)
+// GetChecklist implements Usecase
+func (u *taskUsecase) GetChecklist(ctx context.Context, id int64) (resp response.Response) {
+	items, err := u.taskService.GetChecklist(ctx, id)
 	if err != nil {
-		u.logger.WithContext(ctx).Error(err)
-		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+		return errorResponse(err)
 	}
 
-	host := "https://storage.googleapis.com/"
-	url = fmt.Sprintf("%s%s/%s", host, bucketName, fileName)
-	attachment.ImageURL = url
+	return response.NewSuccessResponse(newChecklistResponse(items), response.StatOK, "")
+}
+
+// AddChecklistItem implements Usecase
+func (u *taskUsecase) AddChecklistItem(ctx context.Context, id int64, payload CreateChecklistItemRequest) (resp response.Response) {
+	item, err := u.taskService.AddChecklistItem(ctx, id, payload.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newChecklistItemResponse(item), response.StatCreated, "")
+}
 
-	return response.NewSuccessResponse(attachment, response.StatOK, "")
+// UpdateChecklistItem implements Usecase
+func (u *taskUsecase) UpdateChecklistItem(ctx context.Context, id int64, itemID int64, payload UpdateChecklistItemRequest) (resp response.Response) {
+	item, err := u.taskService.UpdateChecklistItem(ctx, id, itemID, payload.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newChecklistItemResponse(item), response.StatOK, "")
+}
+
+// ReorderChecklist implements Usecase
+func (u *taskUsecase) ReorderChecklist(ctx context.Context, id int64, payload ReorderChecklistRequest) (resp response.Response) {
+	items, err := u.taskService.ReorderChecklist(ctx, id, payload.ItemIDs)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newChecklistResponse(items), response.StatOK, "")
+}
+
+// DeleteChecklistItem implements Usecase
+func (u *taskUsecase) DeleteChecklistItem(ctx context.Context, id int64, itemID int64) (resp response.Response) {
+	if err := u.taskService.DeleteChecklistItem(ctx, id, itemID); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+func newChecklistItemResponse(item entity.ChecklistItem) ChecklistItemResponse {
+	return ChecklistItemResponse{
+		ID:        item.ID,
+		Name:      item.Name,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func newChecklistResponse(items []entity.ChecklistItem) []ChecklistItemResponse {
+	itemsResponse := make([]ChecklistItemResponse, len(items))
+	for i, v := range items {
+		itemsResponse[i] = newChecklistItemResponse(v)
+	}
+	return itemsResponse
+}
+
+// MoveTask implements Usecase
+func (u *taskUsecase) MoveTask(ctx context.Context, id int64, payload MoveTaskRequest) (resp response.Response) {
+	task, err := u.taskService.MoveTask(ctx, id, payload.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newTaskResponse(task), response.StatOK, "")
+}
+
+// GetBoard implements Usecase
+func (u *taskUsecase) GetBoard(ctx context.Context, request GetBoardRequest) (resp response.Response) {
+	board, err := u.taskService.GetBoard(ctx, request.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	boardResponse := BoardResponse{Columns: make([]BoardColumnResponse, len(board.Columns))}
+	for i, v := range board.Columns {
+		boardResponse.Columns[i] = newBoardColumnResponse(v)
+	}
+
+	return response.NewSuccessResponse(boardResponse, response.StatOK, "")
+}
+
+// MoveOnBoard implements Usecase
+func (u *taskUsecase) MoveOnBoard(ctx context.Context, id int64, payload BoardMoveTaskRequest) (resp response.Response) {
+	task, err := u.taskService.MoveOnBoard(ctx, id, payload.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newTaskResponse(task), response.StatOK, "")
+}
+
+// AddDependency implements Usecase
+func (u *taskUsecase) AddDependency(ctx context.Context, id int64, payload DependencyRequest) (resp response.Response) {
+	dependency, err := u.taskService.AddDependency(ctx, id, payload.BlockerID)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newDependencyResponse(dependency), response.StatCreated, "")
+}
+
+// RemoveDependency implements Usecase
+func (u *taskUsecase) RemoveDependency(ctx context.Context, id int64, blockerID int64) (resp response.Response) {
+	if err := u.taskService.RemoveDependency(ctx, id, blockerID); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+// GetGraph implements Usecase
+func (u *taskUsecase) GetGraph(ctx context.Context, id int64) (resp response.Response) {
+	graph, err := u.taskService.GetGraph(ctx, id)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(GraphResponse{
+		TaskID:     id,
+		Upstream:   newDependencyGraphResponse(graph.Upstream),
+		Downstream: newDependencyGraphResponse(graph.Downstream),
+	}, response.StatOK, "")
+}
+
+// Assign implements Usecase
+func (u *taskUsecase) Assign(ctx context.Context, id int64, payload TaskUserRequest) (resp response.Response) {
+	return taskUserResponse(u.taskService.Assign(ctx, id, payload.UserID))
+}
+
+// Unassign implements Usecase
+func (u *taskUsecase) Unassign(ctx context.Context, id int64, userID string) (resp response.Response) {
+	return taskUserResponse(u.taskService.Unassign(ctx, id, userID))
+}
+
+// Watch implements Usecase
+func (u *taskUsecase) Watch(ctx context.Context, id int64, payload TaskUserRequest) (resp response.Response) {
+	return taskUserResponse(u.taskService.Watch(ctx, id, payload.UserID))
+}
+
+// Unwatch implements Usecase
+func (u *taskUsecase) Unwatch(ctx context.Context, id int64, userID string) (resp response.Response) {
+	return taskUserResponse(u.taskService.Unwatch(ctx, id, userID))
+}
+
+func taskUserResponse(task entity.Task, err error) (resp response.Response) {
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newTaskResponse(task), response.StatOK, "")
+}
+
+// GetActivities implements Usecase, the next cursor is the id of the last activity while there are older ones.
+func (u *taskUsecase) GetActivities(ctx context.Context, id int64, beforeID int64, limit int) (resp response.Response) {
+	// the activity past the page tells that there is a next page.
+	activities, err := u.taskService.GetActivities(ctx, id, core.ActivityPage{BeforeID: beforeID, Limit: limit + 1})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	var meta response.PaginationCursorResponseMeta
+	if len(activities) > limit {
+		activities = activities[:limit]
+		meta.NextCursor = activities[limit-1].ID
+	}
+	if beforeID > 0 {
+		meta.PrevCursor = beforeID
+	}
+
+	activitiesResponse := make([]ActivityResponse, len(activities))
+	for i, v := range activities {
+		activitiesResponse[i] = newActivityResponse(v)
+	}
+	meta.TotalDataOnPage = int64(len(activitiesResponse))
+
+	return response.NewSuccessResponseWithMeta(activitiesResponse, meta, response.StatOK, "")
+}
+
+func newActivityResponse(activity entity.TaskActivity) ActivityResponse {
+	return ActivityResponse{
+		ID:     activity.ID,
+		TaskID: activity.TaskID,
+		Action: activity.Action,
+		Actor:  activity.Actor,
+		ClientDevice: ClientDeviceResponse{
+			RemoteAddress: activity.ClientDevice.RemoteAddress,
+			XForwardedFor: activity.ClientDevice.XForwardedFor,
+			XRealIP:       activity.ClientDevice.XRealIP,
+			UserAgent:     activity.ClientDevice.UserAgent,
+		},
+		Changes:   activity.Changes,
+		CreatedAt: activity.CreatedAt,
+	}
+}
+
+func newDependencyResponse(dependency entity.Dependency) DependencyResponse {
+	return DependencyResponse{
+		TaskID:    dependency.TaskID,
+		BlockerID: dependency.BlockerID,
+		CreatedAt: dependency.CreatedAt,
+	}
+}
+
+func newDependencyGraphResponse(graph core.DependencyGraph) DependencyGraphResponse {
+	graphResponse := DependencyGraphResponse{
+		Tasks: make([]TaskResponse, len(graph.Tasks)),
+		Edges: make([]DependencyResponse, len(graph.Edges)),
+	}
+	for i, v := range graph.Tasks {
+		graphResponse.Tasks[i] = newTaskResponse(v)
+	}
+	for i, v := range graph.Edges {
+		graphResponse.Edges[i] = newDependencyResponse(v)
+	}
+	return graphResponse
+}
+
+func newBoardColumnResponse(column core.BoardColumn) BoardColumnResponse {
+	columnResponse := BoardColumnResponse{
+		Status: column.Status,
+		Tasks:  make([]TaskResponse, len(column.Tasks)),
+		Count:  column.Count,
+	}
+	for i, v := range column.Tasks {
+		columnResponse.Tasks[i] = newTaskResponse(v)
+	}
+	if column.Next != nil {
+		cursor := encodeBoardCursor(column.Status, *column.Next)
+		columnResponse.NextCursor = &cursor
+	}
+	return columnResponse
 }
@@ -0,0 +1,105 @@
+package task_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"todo-app-api/cmd/task/core"
+	task "todo-app-api/cmd/task/v2"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/memstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestUsecase() task.TaskUsecase {
+	return task.NewTaskUsecase(logrus.New(), newTestService(), nil)
+}
+
+func newTestService() core.TaskService {
+	repository := core.NewMemoryTaskRepository(memstore.NewTable[int64, entity.Task]())
+	return core.NewTaskService(logrus.New(), time.UTC, nil, nil, core.Repositories{
+		Tasks:        repository,
+		Labels:       core.NewMemoryLabelRepository(memstore.NewTable[int64, entity.Label]()),
+		Checklists:   core.NewMemoryChecklistRepository(memstore.NewTable[int64, entity.ChecklistItem]()),
+		Dependencies: core.NewMemoryDependencyRepository(memstore.NewTable[core.DependencyKey, entity.Dependency]()),
+		Projects:     core.NewMemoryProjectRepository(memstore.NewTable[int64, entity.Project]()),
+		Comments:     core.NewMemoryCommentRepository(memstore.NewTable[int64, entity.Comment]()),
+		Activities:   core.NewMemoryActivityRepository(memstore.NewTable[int64, entity.TaskActivity]()),
+		Search:       core.NewInvertedIndex(),
+	}, core.TaskOptions{})
+}
+
+func TestCreateAndGetManyTasks(t *testing.T) {
+	ctx := context.Background()
+	usecase := newTestUsecase()
+
+	description := "buy milk"
+	for _, name := range []string{"groceries", "laundry", "groceries"} {
+		if resp := usecase.CreateTask(ctx, task.TaskRequest{Name: name, Description: &description}); resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	}
+
+	name := "groceries"
+	resp := usecase.GetManyTasks(ctx, task.GetManyTaskRequest{Name: &name})
+	tasks := resp.Data().([]task.TaskResponse)
+	if len(tasks) != 2 || tasks[0].ID != 1 || tasks[1].ID != 3 || *tasks[0].Description != description {
+		t.Errorf("unexpected tasks %+v", tasks)
+	}
+
+	if resp := usecase.GetManyTasks(ctx, task.GetManyTaskRequest{}); len(resp.Data().([]task.TaskResponse)) != 3 {
+		t.Errorf("expected every task without a filter, got %+v", resp.Data())
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	ctx := context.Background()
+	usecase := newTestUsecase()
+
+	usecase.CreateTask(ctx, task.TaskRequest{Name: "draft"})
+
+	status := entity.TaskStatusDone
+	resp := usecase.UpdateTask(ctx, 1, task.TaskRequest{Name: "final", Status: &status})
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	updated := usecase.GetOneTask(ctx, 1).Data().(task.TaskResponse)
+	if updated.Name != "final" || *updated.Status != entity.TaskStatusDone || updated.UpdatedAt == nil {
+		t.Errorf("unexpected task %+v", updated)
+	}
+
+	if resp := usecase.UpdateTask(ctx, 42, task.TaskRequest{Name: "missing"}); resp.HTTPStatusCode() != http.StatusNotFound {
+		t.Errorf("expected a missing task, got %d", resp.HTTPStatusCode())
+	}
+}
+
+func TestViews(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	usecase := task.NewTaskUsecase(logrus.New(), service, nil)
+	views := task.NewViewUsecase(logrus.New(), core.NewViewService(logrus.New(), time.UTC, core.NewMemoryViewRepository(memstore.NewTable[int64, entity.View]())), service)
+
+	now, nextMonth := time.Now(), time.Now().AddDate(0, 1, 7)
+	high, low := "high", "low"
+	usecase.CreateTask(ctx, task.TaskRequest{Name: "today", Priority: &low, DueAt: &now})
+	usecase.CreateTask(ctx, task.TaskRequest{Name: "urgent", Priority: &high, DueAt: &now})
+	usecase.CreateTask(ctx, task.TaskRequest{Name: "later", Priority: &high, DueAt: &nextMonth})
+
+	resp := views.CreateView(ctx, task.ViewRequest{Owner: "u-1", Name: "today", Query: "due=today", Sort: core.SortPriorityDesc, Shared: true})
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	view := resp.Data().(task.ViewResponse)
+
+	resp = views.GetViewTasks(ctx, view.ID, "u-2")
+	tasks, _ := resp.Data().([]task.TaskResponse)
+	if len(tasks) != 2 || tasks[0].Name != "urgent" || tasks[1].Name != "today" {
+		t.Errorf("expected the tasks due today by priority, got %+v", resp.Data())
+	}
+	if resp := views.DeleteView(ctx, view.ID, "u-2"); resp.HTTPStatusCode() != http.StatusForbidden {
+		t.Errorf("expected another user to be forbidden, got %d", resp.HTTPStatusCode())
+	}
+}
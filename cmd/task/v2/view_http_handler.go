@@ -0,0 +1,116 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo-app-api/pkg/middleware"
+	"todo-app-api/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type ViewHTTPHandler struct {
+	logger      *logrus.Logger
+	validator   *validator.Validate
+	viewUsecase ViewUsecase
+}
+
+func NewViewHTTPHandler(logger *logrus.Logger, router *mux.Router, basicAuth middleware.RouteMiddleware, validator *validator.Validate, viewUsecase ViewUsecase) {
+	handler := &ViewHTTPHandler{
+		logger:      logger,
+		validator:   validator,
+		viewUsecase: viewUsecase,
+	}
+	router.HandleFunc("/todo/v2/view", basicAuth.Verify(handler.GetViews)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/view", basicAuth.Verify(handler.CreateView)).Methods(http.MethodPost)
+	router.HandleFunc("/todo/v2/view/{id}", basicAuth.Verify(handler.GetView)).Methods(http.MethodGet)
+	router.HandleFunc("/todo/v2/view/{id}", basicAuth.Verify(handler.UpdateView)).Methods(http.MethodPut)
+	router.HandleFunc("/todo/v2/view/{id}", basicAuth.Verify(handler.DeleteView)).Methods(http.MethodDelete)
+	router.HandleFunc("/todo/v2/view/{id}/tasks", basicAuth.Verify(handler.GetViewTasks)).Methods(http.MethodGet)
+}
+
+// GetViews lists the views of the caller and the shared views.
+func (h ViewHTTPHandler) GetViews(w http.ResponseWriter, r *http.Request) {
+	resp := h.viewUsecase.GetViews(r.Context(), author(r))
+	response.JSON(w, resp)
+}
+
+func (h ViewHTTPHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	viewId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.viewUsecase.GetView(r.Context(), viewId, author(r))
+	response.JSON(w, resp)
+}
+
+// CreateView saves a view of the caller.
+func (h ViewHTTPHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.viewUsecase.CreateView(r.Context(), payload)
+	response.JSON(w, resp)
+}
+
+// UpdateView replaces a view of the caller.
+func (h ViewHTTPHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	viewId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+
+	payload, ok := h.decodeRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.viewUsecase.UpdateView(r.Context(), viewId, payload)
+	response.JSON(w, resp)
+}
+
+// DeleteView deletes a view of the caller.
+func (h ViewHTTPHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	viewId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.viewUsecase.DeleteView(r.Context(), viewId, author(r))
+	response.JSON(w, resp)
+}
+
+// GetViewTasks lists the tasks of the view.
+func (h ViewHTTPHandler) GetViewTasks(w http.ResponseWriter, r *http.Request) {
+	pathVariable := mux.Vars(r)
+	viewId, _ := strconv.ParseInt(pathVariable["id"], 10, 64)
+	resp := h.viewUsecase.GetViewTasks(r.Context(), viewId, author(r))
+	response.JSON(w, resp)
+}
+
+// decodeRequestBody checks the query of the view like GET /todo/v2/task would read it.
+func (h ViewHTTPHandler) decodeRequestBody(w http.ResponseWriter, r *http.Request) (payload ViewRequest, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		resp := response.NewErrorResponse(err, http.StatusUnprocessableEntity, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+
+	if err := h.validator.Struct(payload); err != nil {
+		errorField := err.(validator.ValidationErrors)[0]
+		err = fmt.Errorf("invalid '%s' with value '%v'", errorField.Field(), errorField.Value())
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+
+	payload.Query = strings.TrimPrefix(payload.Query, "?")
+	if _, err := parseViewQuery(payload.Query, author(r)); err != nil {
+		err = fmt.Errorf("invalid 'Query': %w", err)
+		resp := response.NewErrorResponse(err, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+		response.JSON(w, resp)
+		return payload, false
+	}
+	payload.Owner = author(r)
+	return payload, true
+}
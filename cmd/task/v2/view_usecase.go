@@ -0,0 +1,143 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"todo-app-api/cmd/task/core"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ViewUsecase interface {
+	GetViews(ctx context.Context, owner string) (resp response.Response)
+	GetView(ctx context.Context, id int64, owner string) (resp response.Response)
+	CreateView(ctx context.Context, viewRequest ViewRequest) (resp response.Response)
+	UpdateView(ctx context.Context, id int64, viewRequest ViewRequest) (resp response.Response)
+	DeleteView(ctx context.Context, id int64, owner string) (resp response.Response)
+	// GetViewTasks runs the view for the caller like GET /todo/v2/task, the assignee me of a shared view is the caller
+	// and is a bad request when the caller isn't a user.
+	GetViewTasks(ctx context.Context, id int64, caller string) (resp response.Response)
+}
+
+type viewUsecase struct {
+	logger      *logrus.Logger
+	viewService core.ViewService
+	taskService core.TaskService
+}
+
+func NewViewUsecase(logger *logrus.Logger, viewService core.ViewService, taskService core.TaskService) ViewUsecase {
+	return &viewUsecase{
+		logger:      logger,
+		viewService: viewService,
+		taskService: taskService,
+	}
+}
+
+// GetViews implements ViewUsecase
+func (u *viewUsecase) GetViews(ctx context.Context, owner string) (resp response.Response) {
+	views, err := u.viewService.GetViews(ctx, owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	viewsResponse := make([]ViewResponse, len(views))
+	for i, v := range views {
+		viewsResponse[i] = newViewResponse(v)
+	}
+
+	return response.NewSuccessResponse(viewsResponse, response.StatOK, "")
+}
+
+// GetView implements ViewUsecase
+func (u *viewUsecase) GetView(ctx context.Context, id int64, owner string) (resp response.Response) {
+	view, err := u.viewService.GetView(ctx, id, owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newViewResponse(view), response.StatOK, "")
+}
+
+// CreateView implements ViewUsecase
+func (u *viewUsecase) CreateView(ctx context.Context, viewRequest ViewRequest) (resp response.Response) {
+	view, err := u.viewService.CreateView(ctx, viewRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newViewResponse(view), response.StatCreated, "")
+}
+
+// UpdateView implements ViewUsecase
+func (u *viewUsecase) UpdateView(ctx context.Context, id int64, viewRequest ViewRequest) (resp response.Response) {
+	view, err := u.viewService.UpdateView(ctx, id, viewRequest.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newViewResponse(view), response.StatOK, "")
+}
+
+// DeleteView implements ViewUsecase
+func (u *viewUsecase) DeleteView(ctx context.Context, id int64, owner string) (resp response.Response) {
+	if err := u.viewService.DeleteView(ctx, id, owner); err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(nil, response.StatOK, "")
+}
+
+// GetViewTasks implements ViewUsecase, the relative dates of the view are resolved on the day it runs.
+func (u *viewUsecase) GetViewTasks(ctx context.Context, id int64, caller string) (resp response.Response) {
+	view, err := u.viewService.GetView(ctx, id, caller)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	filter, err := parseViewQuery(view.Query, callerUser(ctx))
+	if errors.Is(err, errMeRequiresUser) {
+		return response.NewErrorResponse(exception.ErrBadRequest, http.StatusBadRequest, nil, response.StatusInvalidPayload, err.Error())
+	}
+	if err != nil {
+		// the query was checked when the view was saved.
+		u.logger.WithContext(ctx).Error(err)
+		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+	}
+	if view.Sort != "" {
+		filter.Sort = view.Sort
+	}
+
+	tasks, err := u.taskService.GetManyTasks(ctx, filter.core())
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response.NewSuccessResponse(newTaskResponses(tasks), response.StatOK, "")
+}
+
+// parseViewQuery reads the task filters of the query of a view.
+func parseViewQuery(query string, caller string) (filter GetManyTaskRequest, err error) {
+	qs, err := url.ParseQuery(query)
+	if err != nil {
+		return
+	}
+	return parseGetManyTaskRequest(qs, caller)
+}
+
+func newViewResponse(view entity.View) ViewResponse {
+	return ViewResponse{
+		ID:        view.ID,
+		Name:      view.Name,
+		Owner:     view.Owner,
+		Query:     view.Query,
+		Sort:      view.Sort,
+		Shared:    view.Shared,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryUserRepository struct {
+	users *memstore.Table[string, entity.User]
+}
+
+// NewMemoryUserRepository is a constructor of the in-memory repository.
+func NewMemoryUserRepository(users *memstore.Table[string, entity.User]) UserRepository {
+	return &memoryUserRepository{users: users}
+}
+
+// BeginTx returns a fake transaction.
+func (r *memoryUserRepository) BeginTx(ctx context.Context) (tx database.Tx, err error) {
+	return memstore.Begin(), nil
+}
+
+// CommitTx will commit the transaction that has began.
+func (r *memoryUserRepository) CommitTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Commit()
+}
+
+// RollbackTx will undo the writes of the transaction.
+func (r *memoryUserRepository) RollbackTx(ctx context.Context, tx database.Tx) (err error) {
+	return tx.Rollback()
+}
+
+// SaveUser returns exception.ErrConflict on a registered email, like the unique key of the table.
+func (r *memoryUserRepository) SaveUser(ctx context.Context, user UserRequest, tx database.Tx) (err error) {
+	return r.users.Insert(tx, user.UUID, entity.User{
+		UUID:      user.UUID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}, func(existing entity.User) bool {
+		return existing.Email == user.Email
+	})
+}
+
+func (r *memoryUserRepository) FindManyUser(ctx context.Context) (bunchOfUsers []entity.User, err error) {
+	bunchOfUsers = r.users.List(nil)
+	return
+}
+
+func (r *memoryUserRepository) FindManyUserByHandles(ctx context.Context, handles []string) (bunchOfUsers []entity.User, err error) {
+	if len(handles) == 0 {
+		return
+	}
+
+	wanted := make(map[string]bool, len(handles))
+	for _, handle := range handles {
+		wanted[handle] = true
+	}
+	bunchOfUsers = r.users.List(func(user entity.User) bool {
+		localPart, _, _ := strings.Cut(user.Email, "@")
+		return wanted[strings.ToLower(user.Name)] || wanted[strings.ToLower(localPart)]
+	})
+	return
+}
+
+func (r *memoryUserRepository) FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error) {
+	user, ok := r.users.Get(uuid)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
@@ -10,6 +10,8 @@ type UserResponse struct {
 }
 
 type UserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	UUID      string    `json:"uuid" validate:"-"`
+	Name      string    `json:"name" validate:"required"`
+	Email     string    `json:"email" validate:"required,email"`
+	CreatedAt time.Time `json:"createdAt" validate:"-"`
 }
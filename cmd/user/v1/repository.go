@@ -4,23 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
 	"todo-app-api/pkg/exception"
 
-	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
 )
 
 type UserRepository interface {
-	BeginTx(ctx context.Context) (tx *sql.Tx, err error)
-	RollbackTx(ctx context.Context, tx *sql.Tx) (err error)
-	CommitTx(ctx context.Context, tx *sql.Tx) (err error)
-	// SaveUser(ctx context.Context, user UserRequest, tx *sql.Tx) (id int64, err error)
-	// UpdateById(ctx context.Context, id int64, user UserRequest, tx *sql.Tx) (err error)
+	BeginTx(ctx context.Context) (tx database.Tx, err error)
+	RollbackTx(ctx context.Context, tx database.Tx) (err error)
+	CommitTx(ctx context.Context, tx database.Tx) (err error)
+	SaveUser(ctx context.Context, user UserRequest, tx database.Tx) (err error)
+	// UpdateById(ctx context.Context, id int64, user UserRequest, tx database.Tx) (err error)
 	FindManyUser(ctx context.Context) (bunchOfUsers []entity.User, err error)
-	// FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error)
+	// FindManyUserByHandles returns the users whose name or the local part of whose email is one of the lower case handles.
+	FindManyUserByHandles(ctx context.Context, handles []string) (bunchOfUsers []entity.User, err error)
+	FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error)
 }
 
+// likeEscaper escapes the wildcards of a LIKE pattern, backslash is the escape of mysql and postgres.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type sqlCommand interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
@@ -29,39 +35,43 @@ type sqlCommand interface {
 }
 
 type userRepository struct {
-	logger      *logrus.Logger
-	dbReadOnly  *sql.DB
-	dbReadWrite *sql.DB
-	tableName   string
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	tableName string
 }
 
 // NewUserRepository is a constructor
-func NewUserRepository(logger *logrus.Logger, dbReadOnly *sql.DB, dbReadWrite *sql.DB, tableName string) UserRepository {
+func NewUserRepository(logger *logrus.Logger, db *database.Router, tableName string) UserRepository {
 	return &userRepository{
-		logger:      logger,
-		dbReadOnly:  dbReadOnly,
-		dbReadWrite: dbReadWrite,
-		tableName:   tableName,
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		tableName: tableName,
 	}
 }
 
 // BeginTx returns sql trx for global scope.
-func (r *userRepository) BeginTx(ctx context.Context) (tx *sql.Tx, err error) {
-	return r.dbReadWrite.BeginTx(ctx, nil)
+func (r *userRepository) BeginTx(ctx context.Context) (tx database.Tx, err error) {
+	sqlTx, err := r.db.Writer(ctx).BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	return sqlTx, nil
 }
 
 // CommitTx will commit the transaction that has began.
-func (r *userRepository) CommitTx(ctx context.Context, tx *sql.Tx) (err error) {
+func (r *userRepository) CommitTx(ctx context.Context, tx database.Tx) (err error) {
 	return tx.Commit()
 }
 
 // RollbackTx will rollback the transaction to achieve the consistency.
-func (r *userRepository) RollbackTx(ctx context.Context, tx *sql.Tx) (err error) {
+func (r *userRepository) RollbackTx(ctx context.Context, tx database.Tx) (err error) {
 	return tx.Rollback()
 }
 
 func (r *userRepository) FindManyUser(ctx context.Context) (bunchOfUsers []entity.User, err error) {
-	var cmd sqlCommand = r.dbReadOnly
+	var cmd sqlCommand = r.db.Reader(ctx)
 	q := fmt.Sprintf(`SELECT u.uuid, u.name, u.email, u.created_at FROM %s u`, r.tableName)
 	bunchOfUsers, err = r.query(ctx, cmd, q)
 	if err != nil {
@@ -71,8 +81,65 @@ func (r *userRepository) FindManyUser(ctx context.Context) (bunchOfUsers []entit
 	return
 }
 
+func (r *userRepository) FindManyUserByHandles(ctx context.Context, handles []string) (bunchOfUsers []entity.User, err error) {
+	if len(handles) == 0 {
+		return
+	}
+
+	var cmd sqlCommand = r.db.Reader(ctx)
+	conditions := []string{fmt.Sprintf("LOWER(u.name) IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(handles)), ", "))}
+	args := make([]interface{}, 0, len(handles)*2)
+	for _, handle := range handles {
+		args = append(args, handle)
+	}
+	for _, handle := range handles {
+		conditions = append(conditions, "LOWER(u.email) LIKE ?")
+		args = append(args, likeEscaper.Replace(handle)+"@%")
+	}
+
+	q := fmt.Sprintf(`SELECT u.uuid, u.name, u.email, u.created_at FROM %s u WHERE %s`, r.tableName, strings.Join(conditions, " OR "))
+	bunchOfUsers, err = r.query(ctx, cmd, q, args...)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+	return
+}
+
+func (r *userRepository) FindOneUserByUUID(ctx context.Context, uuid string) (user entity.User, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+	q := fmt.Sprintf(`SELECT u.uuid, u.name, u.email, u.created_at FROM %s u WHERE u.uuid = ?`, r.tableName)
+	bunchOfUsers, err := r.query(ctx, cmd, q, uuid)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if len(bunchOfUsers) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+
+	user = bunchOfUsers[0]
+	return
+}
+
+func (r *userRepository) SaveUser(ctx context.Context, user UserRequest, tx database.Tx) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+	if sqlTx, ok := tx.(*sql.Tx); ok {
+		cmd = sqlTx
+	}
+
+	command := fmt.Sprintf(`INSERT INTO %s (uuid, name, email, created_at) VALUES (?, ?, ?, ?)`, r.tableName)
+	if _, err = r.exec(ctx, cmd, command, user.UUID, user.Name, user.Email, user.CreatedAt); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
 func (r *userRepository) query(ctx context.Context, cmd sqlCommand, query string, args ...interface{}) (bunchOfUsers []entity.User, err error) {
 	var rows *sql.Rows
+	query = r.dialect.Rebind(query)
 	if rows, err = cmd.QueryContext(ctx, query, args...); err != nil {
 		r.logger.WithContext(ctx).Error(query, err)
 		return
@@ -100,14 +167,32 @@ func (r *userRepository) query(ctx context.Context, cmd sqlCommand, query string
 	return
 }
 
+func (r *userRepository) exec(ctx context.Context, cmd sqlCommand, command string, args ...interface{}) (result sql.Result, err error) {
+	var stmt *sql.Stmt
+	command = r.dialect.Rebind(command)
+	if stmt, err = cmd.PrepareContext(ctx, command); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		return
+	}
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(command, err)
+		}
+	}()
+
+	if result, err = stmt.ExecContext(ctx, args...); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+	}
+
+	return
+}
+
 func wrapError(e error) (err error) {
 	if e == sql.ErrNoRows {
 		return exception.ErrNotFound
 	}
-	if driverErr, ok := e.(*mysql.MySQLError); ok {
-		if driverErr.Number == 1062 {
-			return exception.ErrConflict
-		}
+	if database.IsUniqueViolation(e) {
+		return exception.ErrConflict
 	}
 	return exception.ErrInternalServer
 }
@@ -7,11 +7,14 @@ import (
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/response"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type UserUsecase interface {
 	GetManyUsers(ctx context.Context) (resp response.Response)
+	GetOneUser(ctx context.Context, uuid string) (resp response.Response)
+	CreateUser(ctx context.Context, userRequest UserRequest) (resp response.Response)
 	// GetOneTask(ctx context.Context, id int64) (resp response.Response)
 	// CreateTask(ctx context.Context, taskRequest TaskRequest) (resp response.Response)
 	// UpdateTask(ctx context.Context, id int64, taskRequest TaskRequest) (resp response.Response)
@@ -57,3 +60,46 @@ func (u *userUsecase) GetManyUsers(ctx context.Context) (resp response.Response)
 
 	return response.NewSuccessResponse(usersResponse, response.StatOK, "")
 }
+
+// GetOneUser implements Usecase
+func (u *userUsecase) GetOneUser(ctx context.Context, uuid string) (resp response.Response) {
+	user, err := u.userRepository.FindOneUserByUUID(ctx, uuid)
+	if err != nil {
+		if err == exception.ErrNotFound {
+			return response.NewErrorResponse(err, http.StatusNotFound, nil, response.StatNotFound, "")
+		}
+		u.logger.WithContext(ctx).Error(err)
+		return response.NewErrorResponse(err, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+	}
+
+	userResponse := UserResponse{
+		UUID:      user.UUID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+
+	return response.NewSuccessResponse(userResponse, response.StatOK, "")
+}
+
+// CreateUser implements Usecase
+func (u *userUsecase) CreateUser(ctx context.Context, userRequest UserRequest) (resp response.Response) {
+	userRequest.UUID = uuid.NewString()
+	userRequest.CreatedAt = time.Now().In(u.location)
+
+	if err := u.userRepository.SaveUser(ctx, userRequest, nil); err != nil {
+		if err == exception.ErrConflict {
+			return response.NewErrorResponse(err, http.StatusConflict, nil, response.StatDuplicateEmail, "email is already registered")
+		}
+		return response.NewErrorResponse(exception.ErrInternalServer, http.StatusInternalServerError, nil, response.StatUnexpectedError, "")
+	}
+
+	userResponse := UserResponse{
+		UUID:      userRequest.UUID,
+		Name:      userRequest.Name,
+		Email:     userRequest.Email,
+		CreatedAt: userRequest.CreatedAt,
+	}
+
+	return response.NewSuccessResponse(userResponse, response.StatOK, "")
+}
@@ -0,0 +1,51 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"todo-app-api/cmd/user/v1"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/memstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCreateUser(t *testing.T) {
+	ctx := context.Background()
+	usecase := user.NewUserUsecase(logrus.New(), time.UTC, user.NewMemoryUserRepository(memstore.NewTable[string, entity.User]()))
+
+	resp := usecase.CreateUser(ctx, user.UserRequest{Name: "Jane", Email: "jane@example.com"})
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	created := resp.Data().(user.UserResponse)
+
+	if resp := usecase.CreateUser(ctx, user.UserRequest{Name: "Jane again", Email: "jane@example.com"}); resp.HTTPStatusCode() != http.StatusConflict {
+		t.Errorf("expected a registered email to conflict, got %d", resp.HTTPStatusCode())
+	}
+
+	if found := usecase.GetOneUser(ctx, created.UUID).Data().(user.UserResponse); found.Email != "jane@example.com" {
+		t.Errorf("unexpected user %+v", found)
+	}
+	if users := usecase.GetManyUsers(ctx).Data().([]user.UserResponse); len(users) != 1 {
+		t.Errorf("expected a single user, got %+v", users)
+	}
+}
+
+func TestFindManyUserByHandles(t *testing.T) {
+	ctx := context.Background()
+	repository := user.NewMemoryUserRepository(memstore.NewTable[string, entity.User]())
+	repository.SaveUser(ctx, user.UserRequest{UUID: "u-1", Name: "Jane", Email: "jane.doe@example.com"}, nil)
+	repository.SaveUser(ctx, user.UserRequest{UUID: "u-2", Name: "Bob", Email: "bob@example.com"}, nil)
+	repository.SaveUser(ctx, user.UserRequest{UUID: "u-3", Name: "Ann", Email: "ann@example.com"}, nil)
+
+	users, err := repository.FindManyUserByHandles(ctx, []string{"bob", "jane.doe", "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected bob and jane, got %+v", users)
+	}
+}
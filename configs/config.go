@@ -42,6 +42,9 @@ type Config struct {
 	Redis struct {
 		Options *redis.Options
 	}
+	Repository struct {
+		Driver string
+	}
 	MariadbReadWrite struct {
 		Driver             string
 		Host               string
@@ -64,6 +67,41 @@ type Config struct {
 		MaxOpenConnections int
 		MaxIdleConnections int
 	}
+	PostgresReadWrite struct {
+		Driver             string
+		Host               string
+		Port               string
+		Username           string
+		Password           string
+		Database           string
+		SSLMode            string
+		DSN                string
+		MaxOpenConnections int
+		MaxIdleConnections int
+	}
+	PostgresReadOnly struct {
+		Driver             string
+		Host               string
+		Port               string
+		Username           string
+		Password           string
+		Database           string
+		SSLMode            string
+		DSN                string
+		MaxOpenConnections int
+		MaxIdleConnections int
+	}
+	DatabaseRouter struct {
+		HealthCheckInterval  time.Duration
+		HealthCheckTimeout   time.Duration
+		MaxReplicaLag        time.Duration
+		ReadYourWritesWindow time.Duration
+	}
+	Migration struct {
+		OnStart     bool
+		TableName   string
+		LockTimeout time.Duration
+	}
 	Mongodb struct {
 		ClientOptions *options.ClientOptions
 		Database      string
@@ -82,6 +120,51 @@ type Config struct {
 		AccessID   string
 		PrivateKey string
 	}
+	Attachment struct {
+		Bucket     string
+		PathPrefix string
+		PublicHost string
+		Folders    map[string]AttachmentFolder
+		Thumbnail  struct {
+			Sizes     []int
+			Workers   int
+			QueueSize int
+			MaxPixels int64
+		}
+		Resumable struct {
+			SessionTTL   time.Duration
+			MaxChunkSize int64
+		}
+		GC struct {
+			Enabled     bool
+			Interval    time.Duration
+			GracePeriod time.Duration
+			DryRun      bool
+		}
+		Scan struct {
+			Scanner          string
+			ClamdAddress     string
+			ClamdTimeout     time.Duration
+			Mode             string
+			InfectedAction   string
+			QuarantinePrefix string
+			StagingPrefix    string
+			Workers          int
+			QueueSize        int
+			RetryInterval    time.Duration
+		}
+	}
+	Task struct {
+		MaxDepth              int
+		RankMaxLength         int
+		RankRebalanceInterval time.Duration
+	}
+	Reminder struct {
+		Enabled   bool
+		Interval  time.Duration
+		LockTTL   time.Duration
+		BatchSize int
+	}
 	GCPDataStore struct {
 		ProjectID   string
 		ProjectCred string
@@ -93,6 +176,13 @@ type Config struct {
 	}
 }
 
+// AttachmentFolder is an upload rule of an attachment folder.
+type AttachmentFolder struct {
+	AllowedTypes     []string
+	MaxSize          int64
+	ResumableMaxSize int64
+}
+
 // Load will load the configuration.
 func Load() *Config {
 	cfg := new(Config)
@@ -101,12 +191,20 @@ func Load() *Config {
 	cfg.crypto()
 	cfg.logFormatter()
 	cfg.redis()
+	cfg.repository()
 	cfg.mariadbReadOnly()
 	cfg.mariadbReadWrite()
+	cfg.postgresReadOnly()
+	cfg.postgresReadWrite()
+	cfg.databaseRouter()
+	cfg.migration()
 	cfg.mongodb()
 	cfg.sarama()
 	cfg.captcha()
 	cfg.gcpStorage()
+	cfg.attachment()
+	cfg.task()
+	cfg.reminder()
 	cfg.gcpDatastore()
 	cfg.otpDuration()
 	return cfg
@@ -195,6 +293,15 @@ func (cfg *Config) redis() {
 	cfg.Redis.Options = options
 }
 
+func (cfg *Config) repository() {
+	driver := strings.ToLower(os.Getenv("REPOSITORY_DRIVER"))
+	if driver == "" {
+		driver = "mysql"
+	}
+
+	cfg.Repository.Driver = driver
+}
+
 func (cfg *Config) mariadbReadOnly() {
 	host := os.Getenv("MARIADB_RO_HOST")
 	port := os.Getenv("MARIADB_RO_PORT")
@@ -299,6 +406,108 @@ func (cfg *Config) mariadbReadWrite() {
 	cfg.MariadbReadWrite.MaxIdleConnections = int(maxIdleConnections)
 }
 
+func (cfg *Config) postgresReadOnly() {
+	host := os.Getenv("POSTGRES_RO_HOST")
+	port := os.Getenv("POSTGRES_RO_PORT")
+	username := os.Getenv("POSTGRES_RO_USERNAME")
+	password := os.Getenv("POSTGRES_RO_PASSWORD")
+	database := os.Getenv("POSTGRES_RO_DATABASE")
+	sslMode := os.Getenv("POSTGRES_RO_SSL_MODE")
+	maxOpenConnections, _ := strconv.ParseInt(os.Getenv("POSTGRES_RO_MAX_OPEN_CONNECTIONS"), 10, 64)
+	maxIdleConnections, _ := strconv.ParseInt(os.Getenv("POSTGRES_RO_MAX_IDLE_CONNECTIONS"), 10, 64)
+
+	cfg.PostgresReadOnly.Driver = "pgx"
+	cfg.PostgresReadOnly.Host = host
+	cfg.PostgresReadOnly.Port = port
+	cfg.PostgresReadOnly.Username = username
+	cfg.PostgresReadOnly.Password = password
+	cfg.PostgresReadOnly.Database = database
+	cfg.PostgresReadOnly.SSLMode = sslMode
+	cfg.PostgresReadOnly.DSN = PostgresDSN(host, port, username, password, database, sslMode, cfg.Application.Timezone)
+	cfg.PostgresReadOnly.MaxOpenConnections = int(maxOpenConnections)
+	cfg.PostgresReadOnly.MaxIdleConnections = int(maxIdleConnections)
+}
+
+func (cfg *Config) postgresReadWrite() {
+	host := os.Getenv("POSTGRES_RW_HOST")
+	port := os.Getenv("POSTGRES_RW_PORT")
+	username := os.Getenv("POSTGRES_RW_USERNAME")
+	password := os.Getenv("POSTGRES_RW_PASSWORD")
+	database := os.Getenv("POSTGRES_RW_DATABASE")
+	sslMode := os.Getenv("POSTGRES_RW_SSL_MODE")
+	maxOpenConnections, _ := strconv.ParseInt(os.Getenv("POSTGRES_RW_MAX_OPEN_CONNECTIONS"), 10, 64)
+	maxIdleConnections, _ := strconv.ParseInt(os.Getenv("POSTGRES_RW_MAX_IDLE_CONNECTIONS"), 10, 64)
+
+	cfg.PostgresReadWrite.Driver = "pgx"
+	cfg.PostgresReadWrite.Host = host
+	cfg.PostgresReadWrite.Port = port
+	cfg.PostgresReadWrite.Username = username
+	cfg.PostgresReadWrite.Password = password
+	cfg.PostgresReadWrite.Database = database
+	cfg.PostgresReadWrite.SSLMode = sslMode
+	cfg.PostgresReadWrite.DSN = PostgresDSN(host, port, username, password, database, sslMode, cfg.Application.Timezone)
+	cfg.PostgresReadWrite.MaxOpenConnections = int(maxOpenConnections)
+	cfg.PostgresReadWrite.MaxIdleConnections = int(maxIdleConnections)
+}
+
+// PostgresDSN builds a postgres url, the credentials are escaped and the sessions use the time zone of the application.
+func PostgresDSN(host, port, username, password, database, sslMode string, location *time.Location) string {
+	if port == "" {
+		port = "5432"
+	}
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	connVal := url.Values{}
+	connVal.Add("sslmode", sslMode)
+	if location != nil {
+		connVal.Add("timezone", location.String())
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(username, password),
+		Host:     fmt.Sprintf("%s:%s", host, port),
+		Path:     "/" + database,
+		RawQuery: connVal.Encode(),
+	}
+	return dsn.String()
+}
+
+func (cfg *Config) databaseRouter() {
+	// every setting is in second, zero disables the lag check and the read-your-writes window.
+	seconds := func(key string, fallback time.Duration) time.Duration {
+		if raw, err := strconv.Atoi(os.Getenv(key)); err == nil && raw >= 0 {
+			return time.Second * time.Duration(raw)
+		}
+		return fallback
+	}
+
+	cfg.DatabaseRouter.HealthCheckInterval = seconds("DB_ROUTER_HEALTH_CHECK_INTERVAL", time.Second*5)
+	cfg.DatabaseRouter.HealthCheckTimeout = seconds("DB_ROUTER_HEALTH_CHECK_TIMEOUT", time.Second*2)
+	cfg.DatabaseRouter.MaxReplicaLag = seconds("DB_ROUTER_MAX_REPLICA_LAG", time.Second*10)
+	cfg.DatabaseRouter.ReadYourWritesWindow = seconds("DB_ROUTER_READ_YOUR_WRITES_WINDOW", time.Second*5)
+}
+
+func (cfg *Config) migration() {
+	onStart, _ := strconv.ParseBool(os.Getenv("MIGRATION_ON_START"))
+
+	tableName := os.Getenv("MIGRATION_TABLE")
+	if tableName == "" {
+		tableName = "schema_migrations"
+	}
+
+	lockTimeout := time.Minute
+	if rawLockTimeout, err := strconv.Atoi(os.Getenv("MIGRATION_LOCK_TIMEOUT")); err == nil && rawLockTimeout > 0 {
+		lockTimeout = time.Second * time.Duration(rawLockTimeout)
+	}
+
+	cfg.Migration.OnStart = onStart
+	cfg.Migration.TableName = tableName
+	cfg.Migration.LockTimeout = lockTimeout
+}
+
 func (cfg *Config) mongodb() {
 	appName := os.Getenv("APP_NAME")
 	uri := os.Getenv("MONGODB_URL")
@@ -388,6 +597,203 @@ func (cfg *Config) gcpStorage() {
 	cfg.GCPStorage.PrivateKey = string(privateKey)
 }
 
+func (cfg *Config) attachment() {
+	bucket := os.Getenv("ATTACHMENT_BUCKET")
+	pathPrefix := os.Getenv("ATTACHMENT_PATH_PREFIX")
+	publicHost := os.Getenv("ATTACHMENT_PUBLIC_HOST")
+	rawFolders := strings.Trim(os.Getenv("ATTACHMENT_FOLDERS"), " ")
+
+	if bucket == "" {
+		bucket = "image-wreg"
+	}
+	if pathPrefix == "" {
+		pathPrefix = "wr"
+	}
+	if publicHost == "" {
+		publicHost = "https://storage.googleapis.com"
+	}
+	if rawFolders == "" {
+		rawFolders = "todo_attachment"
+	}
+
+	folders := make(map[string]AttachmentFolder)
+	for _, folder := range strings.Split(rawFolders, ",") {
+		folder = strings.TrimSpace(folder)
+		if folder == "" {
+			continue
+		}
+
+		// every folder can be tuned by ATTACHMENT_<FOLDER>_TYPES and ATTACHMENT_<FOLDER>_MAX_SIZE.
+		envPrefix := fmt.Sprintf("ATTACHMENT_%s", strings.ToUpper(folder))
+		allowedTypes := []string{"image/jpeg", "image/png", "application/pdf"}
+		if rawTypes := strings.Trim(os.Getenv(envPrefix+"_TYPES"), " "); rawTypes != "" {
+			allowedTypes = strings.Split(rawTypes, ",")
+		}
+
+		maxSize := int64(10 << 20)
+		if rawMaxSize, err := strconv.ParseInt(os.Getenv(envPrefix+"_MAX_SIZE"), 10, 64); err == nil && rawMaxSize > 0 {
+			maxSize = rawMaxSize
+		}
+
+		resumableMaxSize := int64(1 << 30)
+		if rawResumableMaxSize, err := strconv.ParseInt(os.Getenv(envPrefix+"_RESUMABLE_MAX_SIZE"), 10, 64); err == nil && rawResumableMaxSize > 0 {
+			resumableMaxSize = rawResumableMaxSize
+		}
+
+		folders[folder] = AttachmentFolder{
+			AllowedTypes:     allowedTypes,
+			MaxSize:          maxSize,
+			ResumableMaxSize: resumableMaxSize,
+		}
+	}
+
+	thumbnailSizes := make([]int, 0)
+	rawThumbnailSizes := strings.Trim(os.Getenv("ATTACHMENT_THUMBNAIL_SIZES"), " ")
+	if rawThumbnailSizes == "" {
+		rawThumbnailSizes = "64,256,1024"
+	}
+	for _, rawSize := range strings.Split(rawThumbnailSizes, ",") {
+		if size, err := strconv.Atoi(strings.TrimSpace(rawSize)); err == nil && size > 0 {
+			thumbnailSizes = append(thumbnailSizes, size)
+		}
+	}
+
+	thumbnailWorkers, err := strconv.Atoi(os.Getenv("ATTACHMENT_THUMBNAIL_WORKERS"))
+	if err != nil || thumbnailWorkers < 1 {
+		thumbnailWorkers = 2
+	}
+
+	thumbnailQueueSize, err := strconv.Atoi(os.Getenv("ATTACHMENT_THUMBNAIL_QUEUE_SIZE"))
+	if err != nil || thumbnailQueueSize < 0 {
+		thumbnailQueueSize = 32
+	}
+
+	// the decoded image takes 4 bytes a pixel, 40 megapixels is about 160MB.
+	thumbnailMaxPixels, err := strconv.ParseInt(os.Getenv("ATTACHMENT_THUMBNAIL_MAX_PIXELS"), 10, 64)
+	if err != nil || thumbnailMaxPixels < 1 {
+		thumbnailMaxPixels = 40000000
+	}
+
+	scanner := strings.ToLower(os.Getenv("ATTACHMENT_SCANNER"))
+	if scanner == "" {
+		scanner = "noop"
+	}
+
+	clamdTimeout := time.Second * 30
+	if rawClamdTimeout, err := strconv.Atoi(os.Getenv("CLAMD_TIMEOUT_MS")); err == nil && rawClamdTimeout > 0 {
+		clamdTimeout = time.Millisecond * time.Duration(rawClamdTimeout)
+	}
+
+	scanWorkers, err := strconv.Atoi(os.Getenv("ATTACHMENT_SCAN_WORKERS"))
+	if err != nil || scanWorkers < 1 {
+		scanWorkers = 2
+	}
+
+	scanQueueSize, err := strconv.Atoi(os.Getenv("ATTACHMENT_SCAN_QUEUE_SIZE"))
+	if err != nil || scanQueueSize < 0 {
+		scanQueueSize = 32
+	}
+
+	scanRetryInterval := time.Minute
+	if rawScanRetryInterval, err := strconv.Atoi(os.Getenv("ATTACHMENT_SCAN_RETRY_INTERVAL")); err == nil && rawScanRetryInterval > 0 {
+		scanRetryInterval = time.Second * time.Duration(rawScanRetryInterval)
+	}
+
+	sessionTTL := time.Hour * 24
+	if rawSessionTTL, err := strconv.Atoi(os.Getenv("ATTACHMENT_RESUMABLE_SESSION_TTL")); err == nil && rawSessionTTL > 0 {
+		sessionTTL = time.Second * time.Duration(rawSessionTTL)
+	}
+
+	maxChunkSize := int64(64 << 20)
+	if rawMaxChunkSize, err := strconv.ParseInt(os.Getenv("ATTACHMENT_RESUMABLE_MAX_CHUNK_SIZE"), 10, 64); err == nil && rawMaxChunkSize > 0 {
+		maxChunkSize = rawMaxChunkSize
+	}
+
+	gcEnabled, _ := strconv.ParseBool(os.Getenv("ATTACHMENT_GC_ENABLED"))
+	gcDryRun, _ := strconv.ParseBool(os.Getenv("ATTACHMENT_GC_DRY_RUN"))
+
+	gcInterval := time.Hour
+	if rawGCInterval, err := strconv.Atoi(os.Getenv("ATTACHMENT_GC_INTERVAL")); err == nil && rawGCInterval > 0 {
+		gcInterval = time.Second * time.Duration(rawGCInterval)
+	}
+
+	// longer than a resumable session, so the parts of an upload in progress are kept.
+	gcGracePeriod := sessionTTL * 2
+	if rawGCGracePeriod, err := strconv.Atoi(os.Getenv("ATTACHMENT_GC_GRACE_PERIOD")); err == nil && rawGCGracePeriod > 0 {
+		gcGracePeriod = time.Second * time.Duration(rawGCGracePeriod)
+	}
+
+	cfg.Attachment.Bucket = bucket
+	cfg.Attachment.PathPrefix = strings.Trim(pathPrefix, "/")
+	cfg.Attachment.PublicHost = strings.TrimRight(publicHost, "/")
+	cfg.Attachment.Folders = folders
+	cfg.Attachment.Thumbnail.Sizes = thumbnailSizes
+	cfg.Attachment.Thumbnail.Workers = thumbnailWorkers
+	cfg.Attachment.Thumbnail.QueueSize = thumbnailQueueSize
+	cfg.Attachment.Thumbnail.MaxPixels = thumbnailMaxPixels
+	cfg.Attachment.Resumable.SessionTTL = sessionTTL
+	cfg.Attachment.Resumable.MaxChunkSize = maxChunkSize
+	cfg.Attachment.GC.Enabled = gcEnabled
+	cfg.Attachment.GC.Interval = gcInterval
+	cfg.Attachment.GC.GracePeriod = gcGracePeriod
+	cfg.Attachment.GC.DryRun = gcDryRun
+	cfg.Attachment.Scan.Scanner = scanner
+	cfg.Attachment.Scan.ClamdAddress = os.Getenv("CLAMD_ADDRESS")
+	cfg.Attachment.Scan.ClamdTimeout = clamdTimeout
+	cfg.Attachment.Scan.Mode = strings.ToLower(os.Getenv("ATTACHMENT_SCAN_MODE"))
+	cfg.Attachment.Scan.InfectedAction = strings.ToLower(os.Getenv("ATTACHMENT_SCAN_INFECTED_ACTION"))
+	cfg.Attachment.Scan.QuarantinePrefix = strings.Trim(os.Getenv("ATTACHMENT_QUARANTINE_PREFIX"), "/")
+	cfg.Attachment.Scan.StagingPrefix = strings.Trim(os.Getenv("ATTACHMENT_STAGING_PREFIX"), "/")
+	cfg.Attachment.Scan.Workers = scanWorkers
+	cfg.Attachment.Scan.QueueSize = scanQueueSize
+	cfg.Attachment.Scan.RetryInterval = scanRetryInterval
+}
+
+func (cfg *Config) reminder() {
+	enabled, _ := strconv.ParseBool(os.Getenv("REMINDER_ENABLED"))
+
+	interval := time.Minute
+	if rawInterval, err := strconv.Atoi(os.Getenv("REMINDER_INTERVAL")); err == nil && rawInterval > 0 {
+		interval = time.Second * time.Duration(rawInterval)
+	}
+
+	lockTTL := time.Minute * 10
+	if rawLockTTL, err := strconv.Atoi(os.Getenv("REMINDER_LOCK_TTL")); err == nil && rawLockTTL > 0 {
+		lockTTL = time.Second * time.Duration(rawLockTTL)
+	}
+
+	batchSize := 100
+	if rawBatchSize, err := strconv.Atoi(os.Getenv("REMINDER_BATCH_SIZE")); err == nil && rawBatchSize > 0 {
+		batchSize = rawBatchSize
+	}
+
+	cfg.Reminder.Enabled = enabled
+	cfg.Reminder.Interval = interval
+	cfg.Reminder.LockTTL = lockTTL
+	cfg.Reminder.BatchSize = batchSize
+}
+
+func (cfg *Config) task() {
+	maxDepth := 3
+	if rawMaxDepth, err := strconv.Atoi(os.Getenv("TASK_MAX_DEPTH")); err == nil && rawMaxDepth >= 0 {
+		maxDepth = rawMaxDepth
+	}
+
+	rankMaxLength := 16
+	if rawRankMaxLength, err := strconv.Atoi(os.Getenv("TASK_RANK_MAX_LENGTH")); err == nil && rawRankMaxLength > 0 {
+		rankMaxLength = rawRankMaxLength
+	}
+
+	rankRebalanceInterval := time.Minute * 5
+	if rawInterval, err := strconv.Atoi(os.Getenv("TASK_RANK_REBALANCE_INTERVAL")); err == nil && rawInterval > 0 {
+		rankRebalanceInterval = time.Second * time.Duration(rawInterval)
+	}
+
+	cfg.Task.MaxDepth = maxDepth
+	cfg.Task.RankMaxLength = rankMaxLength
+	cfg.Task.RankRebalanceInterval = rankRebalanceInterval
+}
+
 func (cfg *Config) gcpDatastore() {
 	projectID := os.Getenv("DATASTORE_PROJECT_ID")
 	cfg.GCPDataStore.ProjectID = projectID
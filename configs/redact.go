@@ -0,0 +1,76 @@
+package configs
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// secretFieldPattern matches the names of the fields that are never printed.
+var secretFieldPattern = regexp.MustCompile(`(?i)(password|secret|pepper|privatekey|cred|token|dsn)`)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	locationType = reflect.TypeOf(&time.Location{})
+)
+
+// Redacted returns the plain values of the configuration with the secrets redacted.
+// Values of third party clients, like the redis options, are left out.
+func (cfg *Config) Redacted() map[string]interface{} {
+	value, _ := redact(reflect.ValueOf(*cfg))
+	return value.(map[string]interface{})
+}
+
+func redact(v reflect.Value) (value interface{}, ok bool) {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), true
+	case v.Type() == locationType:
+		if v.IsNil() {
+			return nil, true
+		}
+		return v.Interface().(*time.Location).String(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if secretFieldPattern.MatchString(field.Name) {
+				fields[field.Name] = redacted
+				continue
+			}
+			if fieldValue, ok := redact(v.Field(i)); ok {
+				fields[field.Name] = fieldValue
+			}
+		}
+		return fields, true
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if item, ok := redact(v.Index(i)); ok {
+				items = append(items, item)
+			}
+		}
+		return items, true
+	case reflect.Map:
+		items := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			if item, ok := redact(v.MapIndex(key)); ok {
+				items[key.String()] = item
+			}
+		}
+		return items, true
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return v.Interface(), true
+	}
+
+	return nil, false
+}
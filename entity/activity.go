@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TaskActivityCreate     = "create"
+	TaskActivityUpdate     = "update"
+	TaskActivityStatus     = "status"
+	TaskActivityAttachment = "attachment"
+)
+
+// TaskActivity is an entry of the append-only history of a task, Actor is the subject of the principal that made the change.
+type TaskActivity struct {
+	ID           int64         `json:"id"`
+	TaskID       int64         `json:"task_id"`
+	Action       string        `json:"action"`
+	Actor        string        `json:"actor"`
+	ClientDevice ClientDevice  `json:"client_device"`
+	Changes      []FieldChange `json:"changes"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// FieldChange is the JSON value of a task field before and after a change, a created task has no previous values.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// APIKey is a credential issued to a user, only the hash of the key is stored.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserUUID   string     `json:"userUuid"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+const (
+	AttachmentStatusPendingScan string = "pending_scan"
+	AttachmentStatusClean       string = "clean"
+	AttachmentStatusInfected    string = "infected"
+)
+
+// AttachmentObject is an uploaded object kept in the attachment bucket.
+type AttachmentObject struct {
+	ID          int64      `json:"id"`
+	Bucket      string     `json:"bucket"`
+	ObjectKey   string     `json:"object_key"`
+	Folder      string     `json:"folder"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	Status      string     `json:"status"`
+	Signature   *string    `json:"signature"`
+	UploadedBy  string     `json:"uploaded_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// UploadSession is the state of a resumable upload.
+type UploadSession struct {
+	ID          string     `json:"id"`
+	Bucket      string     `json:"bucket"`
+	Folder      string     `json:"folder"`
+	FileName    string     `json:"file_name"`
+	Extension   string     `json:"extension"`
+	ContentType string     `json:"content_type"`
+	Length      int64      `json:"length"`
+	Offset      int64      `json:"offset"`
+	Parts       []string   `json:"parts"`
+	ObjectKey   string     `json:"object_key"`
+	UploadedBy  string     `json:"uploaded_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// ChecklistItem is a step of a task that is lighter than a subtask, the items are ordered by position.
+type ChecklistItem struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	Name      string     `json:"name"`
+	Done      bool       `json:"done"`
+	Position  int        `json:"position"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Comment is a message on a task, Author is the subject of the principal that wrote it.
+type Comment struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// Dependency is a "blocked by" edge, the task can't start before the blocker is done.
+type Dependency struct {
+	TaskID    int64     `json:"task_id"`
+	BlockerID int64     `json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Label tags tasks, a task has any number of labels.
+type Label struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Project groups tasks, a task is in one project at most. The tasks of an archived project are hidden from the task list.
+type Project struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ArchivedAt  *time.Time `json:"archived_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// StatusCounts is the number of tasks in each status.
+type StatusCounts struct {
+	Initiate   int `json:"initiate"`
+	OnProgress int `json:"on_progress"`
+	Done       int `json:"done"`
+}
+
+// Add counts the tasks of the status.
+func (c *StatusCounts) Add(status int, count int) {
+	switch status {
+	case TaskStatusInitiate:
+		c.Initiate += count
+	case TaskStatusOnProgress:
+		c.OnProgress += count
+	case TaskStatusDone:
+		c.Done += count
+	}
+}
@@ -8,3 +8,12 @@ type ClientDevice struct {
 	XRealIP       string
 	UserAgent     string
 }
+
+type PrincipalContextKey struct{}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject  string
+	Method   string
+	APIKeyID int64
+}
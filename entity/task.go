@@ -8,16 +8,73 @@ const (
 	TaskStatusDone       int = 2
 )
 
+const (
+	TaskPriorityLow    int = 0
+	TaskPriorityMedium int = 1
+	TaskPriorityHigh   int = 2
+	TaskPriorityUrgent int = 3
+)
+
+// TaskPriorityNames are the names of the priorities in the api, indexed by priority.
+var TaskPriorityNames = []string{"low", "medium", "high", "urgent"}
+
 type Task struct {
 	ID          int64      `json:"id"`
 	Name        string     `json:"name"`
 	Description string     `json:"description"`
 	Status      int        `json:"status"`
+	Priority    int        `json:"priority"`
 	Attachment  *string    `json:"attachment"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   *time.Time `json:"updated_at"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	RemindedAt  *time.Time `json:"reminded_at"`
+	// Recurrence is the RFC 5545 RRULE of the series, the occurrences count from 1 at SeriesStart.
+	Recurrence  *string    `json:"recurrence"`
+	SeriesID    *int64     `json:"series_id"`
+	SeriesStart *time.Time `json:"series_start"`
+	Occurrence  int        `json:"occurrence"`
+	// LabelIDs are stored with the task, Labels are loaded from them by the service.
+	LabelIDs []int64 `json:"label_ids"`
+	Labels   []Label `json:"labels"`
+	// Assignees and Watchers are the uuids of users, they are stored with the task.
+	Assignees []string `json:"assignees"`
+	Watchers  []string `json:"watchers"`
+	// ParentID makes the task a subtask, Progress counts its checklist items and is loaded by the service.
+	ParentID  *int64       `json:"parent_id"`
+	Progress  TaskProgress `json:"progress"`
+	ProjectID *int64       `json:"project_id"`
+	// Rank is the fractional index of the task in the manual order, see the rank package.
+	Rank string `json:"rank"`
+	// CommentCount is loaded by the service.
+	CommentCount int        `json:"comment_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	// Highlight is set by the service on the tasks of a search.
+	Highlight *TaskHighlight `json:"highlight,omitempty"`
+}
+
+// TaskHighlight is the html of the name and of a snippet of the description of a task, the words of the search are marked with <mark>.
+type TaskHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TaskProgress is the number of done items out of the total.
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
 }
 
 type Attachment struct {
-	ImageURL string `json:"imageUrl"`
+	ImageURL    string              `json:"imageUrl"`
+	Status      string              `json:"status"`
+	ContentType string              `json:"contentType"`
+	Size        int64               `json:"size"`
+	Variants    []AttachmentVariant `json:"variants,omitempty"`
+}
+
+// AttachmentVariant is a resized copy of an image attachment, it is generated asynchronously.
+type AttachmentVariant struct {
+	Size int    `json:"size"`
+	URL  string `json:"url"`
 }
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// View is a saved filter of the task list, Owner is the subject of the principal that saved it.
+// Query is the query string of the task filters, it is kept as written so that its relative dates follow the day it runs.
+type View struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+	Query string `json:"query"`
+	// Sort overrides the sort of the query when it is set.
+	Sort string `json:"sort"`
+	// Shared views are listed and run by every user, only the owner changes them.
+	Shared    bool       `json:"shared"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,111 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	keyPrefix = "tdk"
+	// lastUsedInterval throttles the writes of the last usage of a key.
+	lastUsedInterval = time.Minute
+)
+
+// Service issues and verifies the api keys.
+// A key looks like tdk_<prefix>_<secret>, the prefix is used for the lookup and only the hash of the whole key is stored.
+type Service struct {
+	logger     *logrus.Logger
+	location   *time.Location
+	repository Repository
+}
+
+// NewService is a constructor.
+func NewService(logger *logrus.Logger, location *time.Location, repository Repository) *Service {
+	return &Service{
+		logger:     logger,
+		location:   location,
+		repository: repository,
+	}
+}
+
+// Issue creates a new key of the user, the key itself is only returned here.
+// The key never expires when ttl is zero.
+func (s *Service) Issue(ctx context.Context, userUUID, name string, ttl time.Duration) (key string, apiKey entity.APIKey, err error) {
+	prefix, err := randomHex(4)
+	if err != nil {
+		return
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return
+	}
+
+	key = strings.Join([]string{keyPrefix, prefix, secret}, "_")
+	apiKey = entity.APIKey{
+		UserUUID:  userUUID,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash(key),
+		CreatedAt: time.Now().In(s.location),
+	}
+	if ttl > 0 {
+		expiresAt := apiKey.CreatedAt.Add(ttl)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	apiKey.ID, err = s.repository.Save(ctx, apiKey)
+	return
+}
+
+// Verify returns the api key of a valid key, exception.ErrUnauthorized otherwise.
+func (s *Service) Verify(ctx context.Context, key string) (apiKey entity.APIKey, err error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return apiKey, exception.ErrUnauthorized
+	}
+
+	apiKey, err = s.repository.FindOneByPrefix(ctx, parts[1])
+	if err != nil {
+		if err == exception.ErrNotFound {
+			err = exception.ErrUnauthorized
+		}
+		return
+	}
+
+	now := time.Now().In(s.location)
+	if subtle.ConstantTimeCompare([]byte(apiKey.Hash), []byte(hash(key))) != 1 ||
+		apiKey.RevokedAt != nil ||
+		(apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return entity.APIKey{}, exception.ErrUnauthorized
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedInterval {
+		if err := s.repository.UpdateLastUsedAt(ctx, apiKey.ID, now); err != nil {
+			s.logger.WithContext(ctx).Error(err)
+		}
+	}
+
+	return
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(size int) (value string, err error) {
+	b := make([]byte, size)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,86 @@
+package apikey_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/apikey"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fakeRepository struct {
+	apiKeys map[string]entity.APIKey
+}
+
+func (r *fakeRepository) Save(ctx context.Context, apiKey entity.APIKey) (id int64, err error) {
+	apiKey.ID = int64(len(r.apiKeys) + 1)
+	r.apiKeys[apiKey.Prefix] = apiKey
+	return apiKey.ID, nil
+}
+
+func (r *fakeRepository) FindOneByPrefix(ctx context.Context, prefix string) (apiKey entity.APIKey, err error) {
+	apiKey, ok := r.apiKeys[prefix]
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (r *fakeRepository) UpdateLastUsedAt(ctx context.Context, id int64, lastUsedAt time.Time) (err error) {
+	for prefix, apiKey := range r.apiKeys {
+		if apiKey.ID == id {
+			apiKey.LastUsedAt = &lastUsedAt
+			r.apiKeys[prefix] = apiKey
+		}
+	}
+	return
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	ctx := context.Background()
+	repository := &fakeRepository{apiKeys: make(map[string]entity.APIKey)}
+	service := apikey.NewService(logrus.New(), time.UTC, repository)
+
+	key, issued, err := service.Issue(ctx, "user-uuid", "ci", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(issued.Hash, key) || issued.ExpiresAt != nil {
+		t.Fatalf("unexpected api key %+v", issued)
+	}
+
+	verified, err := service.Verify(ctx, key)
+	if err != nil || verified.UserUUID != "user-uuid" {
+		t.Fatalf("expected the key to be valid, got %+v, %v", verified, err)
+	}
+	if repository.apiKeys[issued.Prefix].LastUsedAt == nil {
+		t.Error("expected the last usage to be recorded")
+	}
+
+	for _, invalid := range []string{"", "tdk_" + issued.Prefix + "_wrong", "tdk_unknown_secret", key + "x"} {
+		if _, err := service.Verify(ctx, invalid); err != exception.ErrUnauthorized {
+			t.Errorf("expected %q to be unauthorized, got %v", invalid, err)
+		}
+	}
+
+	revokedAt := time.Now()
+	revoked := repository.apiKeys[issued.Prefix]
+	revoked.RevokedAt = &revokedAt
+	repository.apiKeys[issued.Prefix] = revoked
+	if _, err := service.Verify(ctx, key); err != exception.ErrUnauthorized {
+		t.Errorf("expected a revoked key to be unauthorized, got %v", err)
+	}
+
+	expiring, _, err := service.Issue(ctx, "user-uuid", "short", time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := service.Verify(ctx, expiring); err != exception.ErrUnauthorized {
+		t.Errorf("expected an expired key to be unauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"context"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryRepository struct {
+	apiKeys *memstore.Table[string, entity.APIKey]
+}
+
+// NewMemoryRepository is a constructor of the in-memory repository, the keys are indexed by their prefix.
+func NewMemoryRepository(apiKeys *memstore.Table[string, entity.APIKey]) Repository {
+	return &memoryRepository{apiKeys: apiKeys}
+}
+
+func (r *memoryRepository) Save(ctx context.Context, apiKey entity.APIKey) (id int64, err error) {
+	apiKey.ID = r.apiKeys.NextID()
+	if err = r.apiKeys.Insert(nil, apiKey.Prefix, apiKey); err != nil {
+		return
+	}
+	return apiKey.ID, nil
+}
+
+func (r *memoryRepository) FindOneByPrefix(ctx context.Context, prefix string) (apiKey entity.APIKey, err error) {
+	apiKey, ok := r.apiKeys.Get(prefix)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (r *memoryRepository) UpdateLastUsedAt(ctx context.Context, id int64, lastUsedAt time.Time) (err error) {
+	for _, apiKey := range r.apiKeys.List(func(apiKey entity.APIKey) bool { return apiKey.ID == id }) {
+		apiKey.LastUsedAt = &lastUsedAt
+		err = r.apiKeys.Update(nil, apiKey.Prefix, apiKey)
+	}
+	return
+}
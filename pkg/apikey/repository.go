@@ -0,0 +1,157 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Repository interface {
+	Save(ctx context.Context, apiKey entity.APIKey) (id int64, err error)
+	FindOneByPrefix(ctx context.Context, prefix string) (apiKey entity.APIKey, err error)
+	UpdateLastUsedAt(ctx context.Context, id int64, lastUsedAt time.Time) (err error)
+}
+
+type sqlCommand interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type repository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	tableName string
+}
+
+// NewRepository is a constructor
+func NewRepository(logger *logrus.Logger, db *database.Router, tableName string) Repository {
+	return &repository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		tableName: tableName,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, apiKey entity.APIKey) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	command := fmt.Sprintf(`INSERT INTO %s (user_uuid, name, prefix, hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`, r.tableName)
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, command, apiKey.UserUUID, apiKey.Name, apiKey.Prefix, apiKey.Hash, apiKey.ExpiresAt, apiKey.CreatedAt); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		err = wrapError(err)
+		return
+	}
+
+	return
+}
+
+func (r *repository) FindOneByPrefix(ctx context.Context, prefix string) (apiKey entity.APIKey, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	q := fmt.Sprintf(`SELECT k.id, k.user_uuid, k.name, k.prefix, k.hash, k.expires_at, k.last_used_at, k.revoked_at, k.created_at FROM %s k WHERE k.prefix = ?`, r.tableName)
+	apiKeys, err := r.query(ctx, cmd, q, prefix)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if len(apiKeys) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+
+	apiKey = apiKeys[0]
+	return
+}
+
+func (r *repository) UpdateLastUsedAt(ctx context.Context, id int64, lastUsedAt time.Time) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	command := fmt.Sprintf(`UPDATE %s SET last_used_at = ? WHERE id = ?`, r.tableName)
+	if _, err = r.exec(ctx, cmd, command, lastUsedAt, id); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *repository) query(ctx context.Context, cmd sqlCommand, query string, args ...interface{}) (apiKeys []entity.APIKey, err error) {
+	var rows *sql.Rows
+	query = r.dialect.Rebind(query)
+	if rows, err = cmd.QueryContext(ctx, query, args...); err != nil {
+		r.logger.WithContext(ctx).Error(query, err)
+		return
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+		}
+	}()
+
+	for rows.Next() {
+		var apiKey entity.APIKey
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+		err = rows.Scan(&apiKey.ID, &apiKey.UserUUID, &apiKey.Name, &apiKey.Prefix, &apiKey.Hash, &expiresAt, &lastUsedAt, &revokedAt, &apiKey.CreatedAt)
+		if err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+			return
+		}
+
+		if expiresAt.Valid {
+			apiKey.ExpiresAt = &expiresAt.Time
+		}
+
+		if lastUsedAt.Valid {
+			apiKey.LastUsedAt = &lastUsedAt.Time
+		}
+
+		if revokedAt.Valid {
+			apiKey.RevokedAt = &revokedAt.Time
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return
+}
+
+func (r *repository) exec(ctx context.Context, cmd sqlCommand, command string, args ...interface{}) (result sql.Result, err error) {
+	var stmt *sql.Stmt
+	command = r.dialect.Rebind(command)
+	if stmt, err = cmd.PrepareContext(ctx, command); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		return
+	}
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(command, err)
+		}
+	}()
+
+	if result, err = stmt.ExecContext(ctx, args...); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+	}
+
+	return
+}
+
+func wrapError(e error) (err error) {
+	if e == sql.ErrNoRows {
+		return exception.ErrNotFound
+	}
+	if database.IsUniqueViolation(e) {
+		return exception.ErrConflict
+	}
+	return exception.ErrInternalServer
+}
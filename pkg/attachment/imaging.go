@@ -0,0 +1,172 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// resize scales the image down so its longest side equals maxSide, preserving the aspect ratio.
+// Every destination pixel is the average of the source pixels it covers (box filter),
+// which keeps downscaled photos smooth without any third party package.
+func resize(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= maxSide && srcHeight <= maxSide {
+		return src
+	}
+
+	dstWidth, dstHeight := maxSide, maxSide
+	if srcWidth >= srcHeight {
+		dstHeight = max(1, srcHeight*maxSide/srcWidth)
+	} else {
+		dstWidth = max(1, srcWidth*maxSide/srcHeight)
+	}
+
+	rgba := toRGBA(src)
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0 := y * srcHeight / dstHeight
+		y1 := max(y0+1, (y+1)*srcHeight/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			x0 := x * srcWidth / dstWidth
+			x1 := max(x0+1, (x+1)*srcWidth/dstWidth)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*rgba.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+
+	return dst
+}
+
+// toRGBA returns a zero based RGBA copy of the image.
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// orient applies the EXIF orientation to the image, so the variants are stored upright.
+// See https://exiftool.org/TagNames/EXIF.html (0x0112 Orientation).
+func orient(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	rgba := toRGBA(src)
+	width, height := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = width-1-x, y
+			case 3: // rotated 180
+				dx, dy = width-1-x, height-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, height-1-y
+			case 5: // mirrored along the top-left diagonal
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = height-1-y, x
+			case 7: // mirrored along the top-right diagonal
+				dx, dy = height-1-y, width-1-x
+			case 8: // rotated 90 counter clockwise
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, rgba.RGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a jpeg file.
+// It returns 1 (upright) when the file has no or a malformed EXIF segment.
+func jpegOrientation(data []byte) int {
+	const upright = 1
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return upright
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return upright
+		}
+		marker := data[offset+1]
+		segmentLength := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if marker == 0xDA || segmentLength < 2 || offset+2+segmentLength > len(data) {
+			// start of scan, there is no metadata after this point.
+			return upright
+		}
+
+		segment := data[offset+4 : offset+2+segmentLength]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		offset += 2 + segmentLength
+	}
+
+	return upright
+}
+
+func tiffOrientation(tiff []byte) int {
+	const upright = 1
+	if len(tiff) < 8 {
+		return upright
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return upright
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:]))
+	if ifdOffset+2 > len(tiff) {
+		return upright
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset:]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return upright
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return upright
+}
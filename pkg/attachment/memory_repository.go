@@ -0,0 +1,68 @@
+package attachment
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+type memoryRepository struct {
+	objects *memstore.Table[string, entity.AttachmentObject]
+}
+
+// NewMemoryRepository is a constructor of the in-memory repository, the objects are indexed by their key.
+func NewMemoryRepository(objects *memstore.Table[string, entity.AttachmentObject]) Repository {
+	return &memoryRepository{objects: objects}
+}
+
+// Save records the uploaded object, an existing record of the same object key is replaced.
+func (r *memoryRepository) Save(ctx context.Context, object entity.AttachmentObject) (id int64, err error) {
+	if existing, ok := r.objects.Get(object.ObjectKey); ok {
+		updatedAt := object.CreatedAt
+		object.ID, object.CreatedAt, object.UpdatedAt = existing.ID, existing.CreatedAt, &updatedAt
+		return object.ID, r.objects.Update(nil, object.ObjectKey, object)
+	}
+
+	object.ID = r.objects.NextID()
+	if err = r.objects.Insert(nil, object.ObjectKey, object); err != nil {
+		return
+	}
+	return object.ID, nil
+}
+
+func (r *memoryRepository) UpdateStatus(ctx context.Context, objectKey string, status string, signature *string, updatedAt time.Time) (err error) {
+	object, ok := r.objects.Get(objectKey)
+	if !ok {
+		return
+	}
+
+	object.Status, object.Signature, object.UpdatedAt = status, signature, &updatedAt
+	if err = r.objects.Update(nil, objectKey, object); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+func (r *memoryRepository) FindOneByObjectKey(ctx context.Context, objectKey string) (object entity.AttachmentObject, err error) {
+	object, ok := r.objects.Get(objectKey)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (r *memoryRepository) FindManyByStatus(ctx context.Context, status string) (objects []entity.AttachmentObject, err error) {
+	objects = r.objects.List(func(object entity.AttachmentObject) bool { return object.Status == status })
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ID < objects[j].ID })
+	return
+}
+
+func (r *memoryRepository) DeleteByObjectKey(ctx context.Context, objectKey string) (err error) {
+	if err = r.objects.Delete(nil, objectKey); err == exception.ErrNotFound {
+		err = nil
+	}
+	return
+}
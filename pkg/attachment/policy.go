@@ -0,0 +1,160 @@
+package attachment
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrPolicy matches every error caused by a file that is not allowed by the policy.
+var ErrPolicy = errors.New("attachment is not allowed")
+
+type policyError struct {
+	message string
+}
+
+func newPolicyError(format string, args ...interface{}) error {
+	return &policyError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *policyError) Error() string {
+	return e.message
+}
+
+func (e *policyError) Is(target error) bool {
+	return target == ErrPolicy
+}
+
+// Rule is an upload rule of a single attachment folder.
+// MaxSize caps the single request uploads, ResumableMaxSize caps the resumable ones.
+type Rule struct {
+	AllowedTypes     []string
+	MaxSize          int64
+	ResumableMaxSize int64
+}
+
+// Policy decides which attachment is allowed and where it is going to be stored.
+type Policy struct {
+	bucket     string
+	pathPrefix string
+	publicHost string
+	rules      map[string]Rule
+}
+
+// NewPolicy is a constructor.
+func NewPolicy(bucket, pathPrefix, publicHost string, rules map[string]Rule) *Policy {
+	normalizedRules := make(map[string]Rule, len(rules))
+	for folder, rule := range rules {
+		allowedTypes := make([]string, 0, len(rule.AllowedTypes))
+		for _, contentType := range rule.AllowedTypes {
+			allowedTypes = append(allowedTypes, normalizeContentType(contentType))
+		}
+		normalizedRules[strings.ToLower(folder)] = Rule{
+			AllowedTypes:     allowedTypes,
+			MaxSize:          rule.MaxSize,
+			ResumableMaxSize: rule.ResumableMaxSize,
+		}
+	}
+
+	return &Policy{
+		bucket:     bucket,
+		pathPrefix: strings.Trim(pathPrefix, "/"),
+		publicHost: strings.TrimRight(publicHost, "/"),
+		rules:      normalizedRules,
+	}
+}
+
+// Bucket returns the bucket name of the attachments.
+func (p *Policy) Bucket() string {
+	return p.bucket
+}
+
+// PathPrefix returns the object prefix of every attachment.
+func (p *Policy) PathPrefix() string {
+	return p.pathPrefix
+}
+
+// Rule returns the rule of the folder.
+func (p *Policy) Rule(folder string) (rule Rule, err error) {
+	rule, ok := p.rules[strings.ToLower(folder)]
+	if !ok {
+		err = newPolicyError("invalid bucket name '%s'", folder)
+	}
+	return
+}
+
+// Check verifies the sniffed content type and the size of the file against the folder rule.
+func (p *Policy) Check(folder, contentType string, size int64) (err error) {
+	rule, err := p.Rule(folder)
+	if err != nil {
+		return
+	}
+
+	if rule.MaxSize > 0 && size > rule.MaxSize {
+		err = newPolicyError("file size %d exceeds the limit of %d bytes", size, rule.MaxSize)
+		return
+	}
+
+	return rule.checkType(contentType)
+}
+
+// CheckResumableSize verifies the declared size of a resumable upload against the folder rule.
+func (p *Policy) CheckResumableSize(folder string, size int64) (err error) {
+	rule, err := p.Rule(folder)
+	if err != nil {
+		return
+	}
+
+	if size < 1 || (rule.ResumableMaxSize > 0 && size > rule.ResumableMaxSize) {
+		err = newPolicyError("file size %d is not within 1 and %d bytes", size, rule.ResumableMaxSize)
+	}
+	return
+}
+
+// CheckType verifies the sniffed content type against the folder rule.
+func (p *Policy) CheckType(folder, contentType string) (err error) {
+	rule, err := p.Rule(folder)
+	if err != nil {
+		return
+	}
+
+	return rule.checkType(contentType)
+}
+
+func (rule Rule) checkType(contentType string) (err error) {
+	contentType = normalizeContentType(contentType)
+	for _, allowedType := range rule.AllowedTypes {
+		if allowedType == contentType {
+			return
+		}
+	}
+
+	err = newPolicyError("invalid file type '%s'", contentType)
+	return
+}
+
+// UploadPartKey returns the object path of a part of a resumable upload.
+func (p *Policy) UploadPartKey(uploadID string, offset int64, suffix string) string {
+	return path.Join(p.pathPrefix, "uploads", uploadID, fmt.Sprintf("%020d-%s", offset, suffix))
+}
+
+// ObjectKey returns the object path of the attachment inside the bucket.
+// Any directory in the file name is dropped, so the object never leaves its folder.
+func (p *Policy) ObjectKey(folder, fileName, extension string) string {
+	return path.Join(p.pathPrefix, strings.ToLower(folder), path.Base("/"+fileName)+extension)
+}
+
+// ObjectKeyFromURL returns the object key of a public url of the attachment bucket.
+func (p *Policy) ObjectKeyFromURL(url string) (objectKey string, ok bool) {
+	objectKey, ok = strings.CutPrefix(url, fmt.Sprintf("%s/%s/", p.publicHost, p.bucket))
+	if ok {
+		ok = strings.HasPrefix(objectKey, p.pathPrefix+"/")
+	}
+	return
+}
+
+// PublicURL returns the public url of the object.
+func (p *Policy) PublicURL(objectKey string) string {
+	return fmt.Sprintf("%s/%s/%s", p.publicHost, p.bucket, objectKey)
+}
@@ -0,0 +1,65 @@
+package attachment_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"todo-app-api/pkg/attachment"
+)
+
+func TestSniff(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	cases := []struct {
+		name        string
+		file        []byte
+		contentType string
+	}{
+		{name: "pdf", file: pdf, contentType: "application/pdf"},
+		{name: "png", file: png, contentType: "image/png"},
+		{name: "text", file: []byte("just a note"), contentType: "text/plain"},
+	}
+
+	for _, c := range cases {
+		contentType, body, err := attachment.Sniff(bytes.NewReader(c.file))
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if contentType != c.contentType {
+			t.Errorf("%s: expected %s, got %s", c.name, c.contentType, contentType)
+		}
+
+		replayed, _ := io.ReadAll(body)
+		if !bytes.Equal(replayed, c.file) {
+			t.Errorf("%s: body is not replayed", c.name)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := attachment.NewPolicy("bucket", "/wr/", "https://storage.googleapis.com/", map[string]attachment.Rule{
+		"todo_attachment": {AllowedTypes: []string{"image/png", "application/pdf"}, MaxSize: 100},
+	})
+
+	if err := policy.Check("todo_attachment", "image/png", 100); err != nil {
+		t.Errorf("expected png to be allowed, got %v", err)
+	}
+	if err := policy.Check("todo_attachment", "image/jpeg", 10); err == nil {
+		t.Error("expected jpeg to be rejected")
+	}
+	if err := policy.Check("todo_attachment", "application/pdf", 101); err == nil {
+		t.Error("expected oversized file to be rejected")
+	}
+	if err := policy.Check("avatar", "image/png", 10); err == nil {
+		t.Error("expected unknown folder to be rejected")
+	}
+
+	key := policy.ObjectKey("todo_attachment", "invoice", attachment.Extension("application/pdf", "invoice.PDF"))
+	if key != "wr/todo_attachment/invoice.pdf" {
+		t.Errorf("unexpected object key %s", key)
+	}
+	if url := policy.PublicURL(key); url != "https://storage.googleapis.com/bucket/wr/todo_attachment/invoice.pdf" {
+		t.Errorf("unexpected public url %s", url)
+	}
+}
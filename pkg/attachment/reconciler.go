@@ -0,0 +1,252 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/lock"
+	"todo-app-api/pkg/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+// variantKeyPattern matches the keys produced by VariantKey.
+var variantKeyPattern = regexp.MustCompile(`^(.+)_\d+px\.[a-z]+$`)
+
+// ReferenceFinder lists the attachment urls that are still in use.
+type ReferenceFinder interface {
+	FindAttachments(ctx context.Context) (attachments []string, err error)
+}
+
+// reconcileLockKey is the lock that picks the replica that reconciles the bucket.
+const reconcileLockKey = "attachment:reconcile"
+
+// ReconcileReport is the outcome of a single reconciliation, Skipped is set when another replica holds the lock.
+type ReconcileReport struct {
+	DryRun  bool
+	Skipped bool
+	Scanned int
+	Kept    int
+	Deleted []string
+}
+
+// Reconciler deletes the objects under the attachment prefix that nothing references anymore.
+// An object is only deleted once it is older than the grace period, so a fresh upload
+// has the time to be linked to a task and a resumable upload has the time to complete.
+// The staged copies whose object is no longer pending are deleted as well.
+type Reconciler struct {
+	logger          *logrus.Logger
+	storage         storage.Storage
+	policy          *Policy
+	repository      Repository
+	sessionStore    SessionStore
+	scanWorker      *ScanWorker
+	referenceFinder ReferenceFinder
+	locker          lock.Locker
+	lockTTL         time.Duration
+	gracePeriod     time.Duration
+	dryRun          bool
+	stop            chan struct{}
+	wg              sync.WaitGroup
+}
+
+// NewReconciler is a constructor. The session store and the scan worker are optional, the lock is held for lockTTL at most.
+func NewReconciler(logger *logrus.Logger, storage storage.Storage, policy *Policy, repository Repository, sessionStore SessionStore, scanWorker *ScanWorker, referenceFinder ReferenceFinder, locker lock.Locker, lockTTL time.Duration, gracePeriod time.Duration, dryRun bool) *Reconciler {
+	if lockTTL <= 0 {
+		lockTTL = time.Hour
+	}
+
+	return &Reconciler{
+		logger:          logger,
+		storage:         storage,
+		policy:          policy,
+		repository:      repository,
+		sessionStore:    sessionStore,
+		scanWorker:      scanWorker,
+		referenceFinder: referenceFinder,
+		locker:          locker,
+		lockTTL:         lockTTL,
+		gracePeriod:     gracePeriod,
+		dryRun:          dryRun,
+		stop:            make(chan struct{}),
+	}
+}
+
+// Start reconciles the bucket on every interval until Close is called.
+func (r *Reconciler) Start(interval time.Duration) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				report, err := r.Run(context.Background())
+				if err != nil {
+					r.logger.Errorf("failed to reconcile attachments: %v", err)
+					continue
+				}
+				if report.Skipped {
+					continue
+				}
+				r.logger.WithFields(logrus.Fields{
+					"reconciler.dry_run": report.DryRun,
+					"reconciler.scanned": report.Scanned,
+					"reconciler.kept":    report.Kept,
+					"reconciler.deleted": len(report.Deleted),
+				}).Info("attachments are reconciled")
+			}
+		}
+	}()
+}
+
+// Close stops the periodic reconciliation.
+func (r *Reconciler) Close() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+// Run reconciles the bucket once, unless another replica is reconciling it.
+func (r *Reconciler) Run(ctx context.Context) (report ReconcileReport, err error) {
+	report.DryRun = r.dryRun
+	report.Deleted = make([]string, 0)
+
+	token, acquired, err := r.locker.Acquire(ctx, reconcileLockKey, r.lockTTL)
+	if err != nil {
+		return
+	}
+	if !acquired {
+		report.Skipped = true
+		return
+	}
+	defer func() {
+		if err := r.locker.Release(ctx, reconcileLockKey, token); err != nil {
+			r.logger.WithContext(ctx).Error(err)
+		}
+	}()
+
+	attachments, err := r.referenceFinder.FindAttachments(ctx)
+	if err != nil {
+		return
+	}
+
+	referenced := make(map[string]bool, len(attachments))
+	referencedBases := make(map[string]bool, len(attachments))
+	for _, url := range attachments {
+		if objectKey, ok := r.policy.ObjectKeyFromURL(url); ok {
+			referenced[objectKey] = true
+			referencedBases[strings.TrimSuffix(objectKey, path.Ext(objectKey))] = true
+		}
+	}
+
+	// listing the objects after the references, so an object linked in between is too young to be deleted.
+	objects, err := r.storage.ListObjects(ctx, r.policy.Bucket(), r.policy.PathPrefix()+"/")
+	if err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(-r.gracePeriod)
+	for _, object := range objects {
+		report.Scanned++
+
+		if object.CreatedAt.After(deadline) || r.isReferenced(ctx, object.Name, referenced, referencedBases) {
+			report.Kept++
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, object.Name)
+		if r.dryRun {
+			continue
+		}
+
+		if err = r.delete(ctx, object.Name); err != nil {
+			return
+		}
+	}
+
+	if r.scanWorker != nil {
+		err = r.sweepStaging(ctx, deadline, &report)
+	}
+	return
+}
+
+// sweepStaging deletes the staged copies that are left behind once their object is published, rejected or removed.
+// The copy of a pending object is kept, the scan worker publishes it.
+func (r *Reconciler) sweepStaging(ctx context.Context, deadline time.Time, report *ReconcileReport) (err error) {
+	stagingPrefix := r.scanWorker.StagingKey("") + "/"
+	objects, err := r.storage.ListObjects(ctx, r.policy.Bucket(), r.scanWorker.StagingKey(r.policy.PathPrefix())+"/")
+	if err != nil {
+		return
+	}
+
+	for _, object := range objects {
+		report.Scanned++
+
+		if object.CreatedAt.After(deadline) {
+			report.Kept++
+			continue
+		}
+
+		record, err := r.repository.FindOneByObjectKey(ctx, strings.TrimPrefix(object.Name, stagingPrefix))
+		if err != nil && !errors.Is(err, exception.ErrNotFound) {
+			return err
+		}
+		if err == nil && record.Status == entity.AttachmentStatusPendingScan {
+			report.Kept++
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, object.Name)
+		if r.dryRun {
+			continue
+		}
+
+		if err = r.storage.DeleteObject(ctx, r.policy.Bucket(), object.Name); err != nil && !errors.Is(err, exception.ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delete removes the object and its record, another replica or a client may have removed them already.
+func (r *Reconciler) delete(ctx context.Context, objectKey string) (err error) {
+	if err = r.storage.DeleteObject(ctx, r.policy.Bucket(), objectKey); err != nil && !errors.Is(err, exception.ErrNotFound) {
+		return
+	}
+	if err = r.repository.DeleteByObjectKey(ctx, objectKey); errors.Is(err, exception.ErrNotFound) {
+		err = nil
+	}
+	return
+}
+
+func (r *Reconciler) isReferenced(ctx context.Context, objectKey string, referenced, referencedBases map[string]bool) bool {
+	if referenced[objectKey] {
+		return true
+	}
+
+	if matches := variantKeyPattern.FindStringSubmatch(objectKey); matches != nil && referencedBases[matches[1]] {
+		return true
+	}
+
+	// a part of a resumable upload that is still in progress.
+	uploadsPrefix := path.Join(r.policy.PathPrefix(), "uploads") + "/"
+	if uploadID, _, ok := strings.Cut(strings.TrimPrefix(objectKey, uploadsPrefix), "/"); ok && r.sessionStore != nil && strings.HasPrefix(objectKey, uploadsPrefix) {
+		if session, err := r.sessionStore.Get(ctx, uploadID); err == nil && session.CompletedAt == nil {
+			return true
+		}
+	}
+
+	return false
+}
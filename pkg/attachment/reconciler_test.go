@@ -0,0 +1,110 @@
+package attachment
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/lock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// goneStorage deletes like another replica got there first.
+type goneStorage struct {
+	*fakeStorage
+}
+
+func (s goneStorage) DeleteObject(ctx context.Context, bucketName string, filepath string) (err error) {
+	s.fakeStorage.DeleteObject(ctx, bucketName, filepath)
+	return exception.ErrNotFound
+}
+
+type fakeReferenceFinder []string
+
+func (f fakeReferenceFinder) FindAttachments(ctx context.Context) (attachments []string, err error) {
+	return f, nil
+}
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage()
+	policy := NewPolicy("bucket", "wr", "https://storage.googleapis.com", nil)
+
+	for _, key := range []string{
+		"wr/todo_attachment/linked.png",
+		"wr/todo_attachment/linked_256px.jpg",
+		"wr/todo_attachment/orphan.png",
+		"wr/todo_attachment/orphan_256px.jpg",
+		"wr/todo_attachment/fresh.png",
+	} {
+		storage.PutObject(ctx, "bucket", key, strings.NewReader(key), "", nil)
+		storage.createdAt[key] = time.Now().Add(-72 * time.Hour)
+	}
+	storage.createdAt["wr/todo_attachment/fresh.png"] = time.Now()
+
+	references := fakeReferenceFinder{policy.PublicURL("wr/todo_attachment/linked.png"), "https://example.com/external.png"}
+
+	report, err := NewReconciler(logrus.New(), storage, policy, newFakeRepository(), nil, nil, references, lock.NewMemoryLocker(), time.Hour, 48*time.Hour, true).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(report.Deleted)
+	if report.Scanned != 5 || report.Kept != 3 || len(report.Deleted) != 2 || report.Deleted[0] != "wr/todo_attachment/orphan.png" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(storage.keys()) != 5 {
+		t.Fatal("expected a dry run to keep every object")
+	}
+
+	// another replica holds the lock.
+	locker := lock.NewMemoryLocker()
+	locker.Acquire(ctx, "attachment:reconcile", time.Hour)
+	if report, err = NewReconciler(logrus.New(), storage, policy, newFakeRepository(), nil, nil, references, locker, time.Hour, 48*time.Hour, false).Run(ctx); err != nil || !report.Skipped {
+		t.Fatalf("expected the run to be skipped, got %+v, %v", report, err)
+	}
+	if len(storage.keys()) != 5 {
+		t.Fatal("expected a skipped run to keep every object")
+	}
+
+	if _, err = NewReconciler(logrus.New(), goneStorage{storage}, policy, newFakeRepository(), nil, nil, references, lock.NewMemoryLocker(), time.Hour, 48*time.Hour, false).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if keys := storage.keys(); len(keys) != 3 {
+		t.Errorf("expected the orphans to be deleted, got %v", keys)
+	}
+}
+
+func TestReconcilerSweepsStaging(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage()
+	repository := newFakeRepository()
+	policy := NewPolicy("bucket", "wr", "https://storage.googleapis.com", nil)
+	scanWorker := NewScanWorker(logrus.New(), time.UTC, storage, repository, fakeScanner{}, nil, nil, ScanWorkerOptions{Mode: ScanAfterUpload})
+
+	for key, status := range map[string]string{
+		"wr/todo_attachment/pending.pdf":   entity.AttachmentStatusPendingScan,
+		"wr/todo_attachment/published.pdf": entity.AttachmentStatusClean,
+	} {
+		repository.Save(ctx, entity.AttachmentObject{ObjectKey: key, Status: status})
+	}
+	for _, key := range []string{"pending.pdf", "published.pdf", "removed.pdf"} {
+		stagingKey := scanWorker.StagingKey("wr/todo_attachment/" + key)
+		storage.PutObject(ctx, "bucket", stagingKey, strings.NewReader(key), "", nil)
+		storage.createdAt[stagingKey] = time.Now().Add(-72 * time.Hour)
+	}
+
+	report, err := NewReconciler(logrus.New(), storage, policy, repository, nil, scanWorker, fakeReferenceFinder{}, lock.NewMemoryLocker(), time.Hour, 48*time.Hour, false).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 3 || report.Kept != 1 || len(report.Deleted) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if keys := storage.keys(); len(keys) != 1 || keys[0] != "staging/wr/todo_attachment/pending.pdf" {
+		t.Errorf("expected only the pending object to stay staged, got %v", keys)
+	}
+}
@@ -0,0 +1,182 @@
+package attachment
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Repository interface {
+	Save(ctx context.Context, object entity.AttachmentObject) (id int64, err error)
+	UpdateStatus(ctx context.Context, objectKey string, status string, signature *string, updatedAt time.Time) (err error)
+	FindOneByObjectKey(ctx context.Context, objectKey string) (object entity.AttachmentObject, err error)
+	// FindManyByStatus returns the objects of the status, the oldest first.
+	FindManyByStatus(ctx context.Context, status string) (objects []entity.AttachmentObject, err error)
+	DeleteByObjectKey(ctx context.Context, objectKey string) (err error)
+}
+
+type sqlCommand interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type repository struct {
+	logger    *logrus.Logger
+	db        *database.Router
+	dialect   database.Dialect
+	tableName string
+}
+
+// NewRepository is a constructor
+func NewRepository(logger *logrus.Logger, db *database.Router, tableName string) Repository {
+	return &repository{
+		logger:    logger,
+		db:        db,
+		dialect:   db.Dialect(),
+		tableName: tableName,
+	}
+}
+
+// Save records the uploaded object, an existing record of the same object key is replaced.
+func (r *repository) Save(ctx context.Context, object entity.AttachmentObject) (id int64, err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	command := fmt.Sprintf(`INSERT INTO %s (bucket, object_key, folder, content_type, size, status, signature, uploaded_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), content_type = VALUES(content_type), size = VALUES(size), status = VALUES(status), signature = VALUES(signature), uploaded_by = VALUES(uploaded_by), updated_at = VALUES(created_at)`, r.tableName)
+	if r.dialect == database.Postgres {
+		command = fmt.Sprintf(`INSERT INTO %s (bucket, object_key, folder, content_type, size, status, signature, uploaded_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (object_key) DO UPDATE SET content_type = EXCLUDED.content_type, size = EXCLUDED.size, status = EXCLUDED.status, signature = EXCLUDED.signature, uploaded_by = EXCLUDED.uploaded_by, updated_at = EXCLUDED.created_at`, r.tableName)
+	}
+
+	if id, err = r.dialect.InsertReturningID(ctx, cmd, command, object.Bucket, object.ObjectKey, object.Folder, object.ContentType, object.Size, object.Status, object.Signature, object.UploadedBy, object.CreatedAt); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *repository) UpdateStatus(ctx context.Context, objectKey string, status string, signature *string, updatedAt time.Time) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	command := fmt.Sprintf(`UPDATE %s SET status = ?, signature = ?, updated_at = ? WHERE object_key = ?`, r.tableName)
+	if _, err = r.exec(ctx, cmd, command, status, signature, updatedAt, objectKey); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *repository) DeleteByObjectKey(ctx context.Context, objectKey string) (err error) {
+	var cmd sqlCommand = r.db.Writer(ctx)
+
+	command := fmt.Sprintf(`DELETE FROM %s WHERE object_key = ?`, r.tableName)
+	if _, err = r.exec(ctx, cmd, command, objectKey); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *repository) FindOneByObjectKey(ctx context.Context, objectKey string) (object entity.AttachmentObject, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	q := fmt.Sprintf(`SELECT a.id, a.bucket, a.object_key, a.folder, a.content_type, a.size, a.status, a.signature, a.uploaded_by, a.created_at, a.updated_at FROM %s a WHERE a.object_key = ?`, r.tableName)
+	objects, err := r.query(ctx, cmd, q, objectKey)
+	if err != nil {
+		err = wrapError(err)
+		return
+	}
+
+	if len(objects) < 1 {
+		err = exception.ErrNotFound
+		return
+	}
+
+	object = objects[0]
+	return
+}
+
+func (r *repository) FindManyByStatus(ctx context.Context, status string) (objects []entity.AttachmentObject, err error) {
+	var cmd sqlCommand = r.db.Reader(ctx)
+
+	q := fmt.Sprintf(`SELECT a.id, a.bucket, a.object_key, a.folder, a.content_type, a.size, a.status, a.signature, a.uploaded_by, a.created_at, a.updated_at FROM %s a WHERE a.status = ? ORDER BY a.id`, r.tableName)
+	if objects, err = r.query(ctx, cmd, q, status); err != nil {
+		err = wrapError(err)
+	}
+	return
+}
+
+func (r *repository) query(ctx context.Context, cmd sqlCommand, query string, args ...interface{}) (objects []entity.AttachmentObject, err error) {
+	var rows *sql.Rows
+	query = r.dialect.Rebind(query)
+	if rows, err = cmd.QueryContext(ctx, query, args...); err != nil {
+		r.logger.WithContext(ctx).Error(query, err)
+		return
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+		}
+	}()
+
+	for rows.Next() {
+		var object entity.AttachmentObject
+		var signature sql.NullString
+		var updatedAt sql.NullTime
+
+		err = rows.Scan(&object.ID, &object.Bucket, &object.ObjectKey, &object.Folder, &object.ContentType, &object.Size, &object.Status, &signature, &object.UploadedBy, &object.CreatedAt, &updatedAt)
+		if err != nil {
+			r.logger.WithContext(ctx).Error(query, err)
+			return
+		}
+
+		if signature.Valid {
+			object.Signature = &signature.String
+		}
+
+		if updatedAt.Valid {
+			object.UpdatedAt = &updatedAt.Time
+		}
+
+		objects = append(objects, object)
+	}
+
+	return
+}
+
+func (r *repository) exec(ctx context.Context, cmd sqlCommand, command string, args ...interface{}) (result sql.Result, err error) {
+	var stmt *sql.Stmt
+	command = r.dialect.Rebind(command)
+	if stmt, err = cmd.PrepareContext(ctx, command); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+		return
+	}
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			r.logger.WithContext(ctx).Error(command, err)
+		}
+	}()
+
+	if result, err = stmt.ExecContext(ctx, args...); err != nil {
+		r.logger.WithContext(ctx).Error(command, err)
+	}
+
+	return
+}
+
+func wrapError(e error) (err error) {
+	if e == sql.ErrNoRows {
+		return exception.ErrNotFound
+	}
+	if database.IsUniqueViolation(e) {
+		return exception.ErrConflict
+	}
+	return exception.ErrInternalServer
+}
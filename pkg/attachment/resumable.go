@@ -0,0 +1,257 @@
+package attachment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrChunkTooLarge is returned when a chunk goes beyond the declared length or the chunk size limit.
+var ErrChunkTooLarge = errors.New("chunk is too large")
+
+// ResumableUploader implements tus-like resumable uploads on top of the storage.
+// Every chunk is stored as a part object and the parts are composed into the attachment once the upload is complete.
+// See https://tus.io/protocols/resumable-upload for the protocol.
+type ResumableUploader struct {
+	logger       *logrus.Logger
+	location     *time.Location
+	storage      storage.Storage
+	policy       *Policy
+	sessionStore SessionStore
+	uploader     *Uploader
+	sessionTTL   time.Duration
+	maxChunkSize int64
+}
+
+// NewResumableUploader is a constructor.
+func NewResumableUploader(logger *logrus.Logger, location *time.Location, storage storage.Storage, policy *Policy, sessionStore SessionStore, uploader *Uploader, sessionTTL time.Duration, maxChunkSize int64) *ResumableUploader {
+	return &ResumableUploader{
+		logger:       logger,
+		location:     location,
+		storage:      storage,
+		policy:       policy,
+		sessionStore: sessionStore,
+		uploader:     uploader,
+		sessionTTL:   sessionTTL,
+		maxChunkSize: maxChunkSize,
+	}
+}
+
+// MaxChunkSize returns the maximum size of a single chunk.
+func (u *ResumableUploader) MaxChunkSize() int64 {
+	return u.maxChunkSize
+}
+
+// Create starts a new upload session.
+func (u *ResumableUploader) Create(ctx context.Context, folder, fileName string, length int64, uploadedBy string) (session entity.UploadSession, err error) {
+	if err = u.policy.CheckResumableSize(folder, length); err != nil {
+		return
+	}
+
+	id, err := randomID(16)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().In(u.location)
+	session = entity.UploadSession{
+		ID:         id,
+		Bucket:     u.policy.Bucket(),
+		Folder:     folder,
+		FileName:   fileName,
+		Length:     length,
+		Parts:      make([]string, 0),
+		UploadedBy: uploadedBy,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.sessionTTL),
+	}
+
+	err = u.sessionStore.Create(ctx, session)
+	return
+}
+
+// Get returns the upload session of the folder.
+func (u *ResumableUploader) Get(ctx context.Context, folder, id string) (session entity.UploadSession, err error) {
+	session, err = u.sessionStore.Get(ctx, id)
+	if err == nil && session.Folder != folder {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+// Attachment returns the attachment of a completed session.
+func (u *ResumableUploader) Attachment(session entity.UploadSession) (attachment *entity.Attachment) {
+	if session.CompletedAt == nil {
+		return
+	}
+
+	return &entity.Attachment{
+		ImageURL:    u.policy.PublicURL(session.ObjectKey),
+		ContentType: session.ContentType,
+		Size:        session.Length,
+	}
+}
+
+// WriteChunk appends the chunk at the offset of the session.
+// exception.ErrConflict is returned when the offset is not the current offset of the session.
+// The attachment is returned once the last chunk is written.
+func (u *ResumableUploader) WriteChunk(ctx context.Context, folder, id string, offset int64, chunk io.Reader) (session entity.UploadSession, attachment *entity.Attachment, err error) {
+	if session, err = u.Get(ctx, folder, id); err != nil {
+		return
+	}
+	if session.CompletedAt != nil || offset != session.Offset {
+		err = exception.ErrConflict
+		return
+	}
+
+	// a complete session that failed to finalize is retried with an empty chunk.
+	if session.Offset == session.Length {
+		return u.finalize(ctx, session)
+	}
+
+	if offset == 0 {
+		var contentType string
+		if contentType, chunk, err = Sniff(chunk); err != nil {
+			return
+		}
+		if err = u.policy.CheckType(folder, contentType); err != nil {
+			return
+		}
+		session.ContentType = contentType
+		session.Extension = Extension(contentType, session.FileName)
+		session.ObjectKey = u.policy.ObjectKey(folder, session.FileName, session.Extension)
+	}
+
+	limit := min(session.Length-session.Offset, u.maxChunkSize)
+	counter := &countingReader{reader: io.LimitReader(chunk, limit)}
+
+	suffix, err := randomID(4)
+	if err != nil {
+		return
+	}
+	partKey := u.policy.UploadPartKey(session.ID, offset, suffix)
+	if err = u.storage.PutObject(ctx, session.Bucket, partKey, counter, session.ContentType, nil); err != nil {
+		return
+	}
+
+	// anything beyond the limit is rejected, the stored part is kept since it is valid.
+	var extra [1]byte
+	tooLarge := false
+	if n, _ := chunk.Read(extra[:]); n > 0 {
+		tooLarge = true
+	}
+
+	if counter.n == 0 {
+		u.storage.DeleteObject(ctx, session.Bucket, partKey)
+	} else {
+		session.Parts = append(session.Parts, partKey)
+		session.Offset += counter.n
+		session.ExpiresAt = time.Now().In(u.location).Add(u.sessionTTL)
+
+		if err = u.sessionStore.Swap(ctx, session, offset); err != nil {
+			// a concurrent request has written the same offset.
+			u.storage.DeleteObject(ctx, session.Bucket, partKey)
+			return
+		}
+	}
+
+	if tooLarge {
+		err = ErrChunkTooLarge
+		return
+	}
+
+	if session.Offset == session.Length {
+		return u.finalize(ctx, session)
+	}
+
+	return
+}
+
+// Terminate cancels the upload session and removes its parts.
+func (u *ResumableUploader) Terminate(ctx context.Context, folder, id string) (err error) {
+	session, err := u.Get(ctx, folder, id)
+	if err != nil {
+		return
+	}
+
+	if err = u.sessionStore.Delete(ctx, id); err != nil {
+		return
+	}
+
+	if session.CompletedAt == nil {
+		u.deleteParts(ctx, session)
+	}
+	return
+}
+
+func (u *ResumableUploader) finalize(ctx context.Context, session entity.UploadSession) (entity.UploadSession, *entity.Attachment, error) {
+	if err := u.storage.ComposeObject(ctx, session.Bucket, u.uploader.storedKey(session.ObjectKey), session.Parts, session.ContentType); err != nil {
+		return session, nil, err
+	}
+
+	object := entity.AttachmentObject{
+		Bucket:      session.Bucket,
+		ObjectKey:   session.ObjectKey,
+		Folder:      session.Folder,
+		ContentType: session.ContentType,
+		Size:        session.Length,
+		UploadedBy:  session.UploadedBy,
+	}
+	attachment, err := u.uploader.register(ctx, object, nil)
+	if err != nil {
+		if errors.Is(err, ErrInfected) {
+			u.deleteParts(ctx, session)
+			u.sessionStore.Delete(ctx, session.ID)
+		}
+		return session, nil, err
+	}
+
+	u.deleteParts(ctx, session)
+
+	// the completed session is kept until it expires, so the client can still look it up.
+	completedAt := time.Now().In(u.location)
+	session.CompletedAt = &completedAt
+	if err := u.sessionStore.Swap(ctx, session, session.Offset); err != nil {
+		u.logger.WithContext(ctx).Errorf("failed to complete upload session %s: %v", session.ID, err)
+	}
+
+	return session, &attachment, nil
+}
+
+func (u *ResumableUploader) deleteParts(ctx context.Context, session entity.UploadSession) {
+	for _, part := range session.Parts {
+		if err := u.storage.DeleteObject(ctx, session.Bucket, part); err != nil {
+			// the orphaned parts are left to the garbage collector.
+			u.logger.WithContext(ctx).Errorf("failed to delete %s: %v", part, err)
+		}
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.n += int64(n)
+	return
+}
+
+func randomID(size int) (id string, err error) {
+	b := make([]byte, size)
+	if _, err = rand.Read(b); err != nil {
+		err = fmt.Errorf("failed to generate id: %w", err)
+		return
+	}
+	return hex.EncodeToString(b), nil
+}
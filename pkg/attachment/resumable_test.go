@@ -0,0 +1,90 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestResumableUploader(storage *fakeStorage, repository *fakeRepository) *ResumableUploader {
+	policy := NewPolicy("bucket", "wr", "https://storage.googleapis.com", map[string]Rule{
+		"todo_attachment": {AllowedTypes: []string{"application/pdf"}, MaxSize: 10, ResumableMaxSize: 1 << 20},
+	})
+	uploader := NewUploader(logrus.New(), time.UTC, storage, policy, repository, nil, nil)
+	return NewResumableUploader(logrus.New(), time.UTC, storage, policy, NewMemorySessionStore(), uploader, time.Hour, 16)
+}
+
+func TestResumableUpload(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage()
+	repository := newFakeRepository()
+	uploader := newTestResumableUploader(storage, repository)
+
+	file := []byte("%PDF-1.7\nsome large document")
+	session, err := uploader.Create(ctx, "todo_attachment", "report", int64(len(file)), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := uploader.WriteChunk(ctx, "todo_attachment", session.ID, 5, bytes.NewReader(file)); !errors.Is(err, exception.ErrConflict) {
+		t.Fatalf("expected a conflict on a wrong offset, got %v", err)
+	}
+
+	// the chunk is capped to 16 bytes, the rest has to be sent again.
+	session, _, err = uploader.WriteChunk(ctx, "todo_attachment", session.ID, 0, bytes.NewReader(file[:16]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Offset != 16 || session.ContentType != "application/pdf" {
+		t.Fatalf("unexpected session %+v", session)
+	}
+
+	session, uploaded, err := uploader.WriteChunk(ctx, "todo_attachment", session.ID, 16, bytes.NewReader(file[16:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded == nil || session.CompletedAt == nil {
+		t.Fatal("expected the upload to be completed")
+	}
+	if uploaded.ImageURL != "https://storage.googleapis.com/bucket/wr/todo_attachment/report.pdf" {
+		t.Errorf("unexpected url %s", uploaded.ImageURL)
+	}
+
+	if keys := storage.keys(); len(keys) != 1 || keys[0] != "wr/todo_attachment/report.pdf" {
+		t.Errorf("expected only the composed object to be left, got %v", keys)
+	}
+	composed, _ := storage.GetObject(ctx, "bucket", "wr/todo_attachment/report.pdf")
+	if data, _ := io.ReadAll(composed); !bytes.Equal(data, file) {
+		t.Errorf("unexpected composed object %q", data)
+	}
+
+	object, err := repository.FindOneByObjectKey(ctx, "wr/todo_attachment/report.pdf")
+	if err != nil || object.Status != entity.AttachmentStatusClean || object.Size != int64(len(file)) {
+		t.Errorf("unexpected record %+v, %v", object, err)
+	}
+}
+
+func TestResumableUploadRejectsDisallowedType(t *testing.T) {
+	ctx := context.Background()
+	uploader := newTestResumableUploader(newFakeStorage(), newFakeRepository())
+
+	session, err := uploader.Create(ctx, "todo_attachment", "notes", 5, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := uploader.WriteChunk(ctx, "todo_attachment", session.ID, 0, strings.NewReader("notes")); !errors.Is(err, ErrPolicy) {
+		t.Errorf("expected a policy error, got %v", err)
+	}
+	if _, err := uploader.Create(ctx, "todo_attachment", "huge", 2<<20, "admin"); !errors.Is(err, ErrPolicy) {
+		t.Errorf("expected a policy error, got %v", err)
+	}
+}
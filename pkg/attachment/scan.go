@@ -0,0 +1,424 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/notifier"
+	"todo-app-api/pkg/scanner"
+	"todo-app-api/pkg/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Scan modes.
+const (
+	ScanBeforeUpload string = "before"
+	ScanAfterUpload  string = "after"
+)
+
+// Actions taken on an infected object.
+const (
+	InfectedActionQuarantine string = "quarantine"
+	InfectedActionDelete     string = "delete"
+)
+
+// maxRetryBackoff caps the wait between the scans of an object that keeps failing.
+const maxRetryBackoff = time.Hour
+
+// ErrInfected is returned when a file is rejected by the scanner before it is uploaded.
+var ErrInfected = errors.New("the file is infected")
+
+// scanJob is an uploaded object waiting for its verdict, stored under key until it is published.
+// The object is streamed back from the storage when data is nil.
+type scanJob struct {
+	object entity.AttachmentObject
+	key    string
+	data   []byte
+}
+
+// scanRetry is the backoff of an object whose scan or publication failed.
+type scanRetry struct {
+	attempts int
+	nextAt   time.Time
+}
+
+// ScanWorker scans the uploaded objects in a bounded worker pool and deals with the infected ones.
+type ScanWorker struct {
+	logger           *logrus.Logger
+	storage          storage.Storage
+	repository       Repository
+	scanner          scanner.Scanner
+	notifier         notifier.Notifier
+	thumbnailer      *Thumbnailer
+	location         *time.Location
+	mode             string
+	infectedAction   string
+	quarantinePrefix string
+	stagingPrefix    string
+	workers          int
+	retryInterval    time.Duration
+	jobs             chan scanJob
+	wg               sync.WaitGroup
+	mu               sync.Mutex
+	queued           map[string]bool
+	retries          map[string]scanRetry
+	stop             chan struct{}
+	retryWG          sync.WaitGroup
+}
+
+// ScanWorkerOptions is the behavior of the scan worker.
+type ScanWorkerOptions struct {
+	Mode             string
+	InfectedAction   string
+	QuarantinePrefix string
+	// StagingPrefix holds the objects that are scanned after the upload until they are found clean.
+	StagingPrefix string
+	Workers       int
+	QueueSize     int
+	// RetryInterval is how often the pending objects are looked for, and the first wait before a failed scan is retried.
+	RetryInterval time.Duration
+}
+
+// NewScanWorker is a constructor. The thumbnailer is optional.
+func NewScanWorker(logger *logrus.Logger, location *time.Location, storage storage.Storage, repository Repository, scanner scanner.Scanner, notifier notifier.Notifier, thumbnailer *Thumbnailer, opts ScanWorkerOptions) *ScanWorker {
+	if opts.Mode != ScanBeforeUpload {
+		opts.Mode = ScanAfterUpload
+	}
+	if opts.InfectedAction != InfectedActionDelete {
+		opts.InfectedAction = InfectedActionQuarantine
+	}
+	if opts.QuarantinePrefix == "" {
+		opts.QuarantinePrefix = "quarantine"
+	}
+	if opts.StagingPrefix == "" {
+		opts.StagingPrefix = "staging"
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize < 0 {
+		opts.QueueSize = 0
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Minute
+	}
+
+	return &ScanWorker{
+		logger:           logger,
+		storage:          storage,
+		repository:       repository,
+		scanner:          scanner,
+		notifier:         notifier,
+		thumbnailer:      thumbnailer,
+		location:         location,
+		mode:             opts.Mode,
+		infectedAction:   opts.InfectedAction,
+		quarantinePrefix: opts.QuarantinePrefix,
+		stagingPrefix:    opts.StagingPrefix,
+		workers:          opts.Workers,
+		retryInterval:    opts.RetryInterval,
+		jobs:             make(chan scanJob, opts.QueueSize),
+		queued:           make(map[string]bool),
+		retries:          make(map[string]scanRetry),
+		stop:             make(chan struct{}),
+	}
+}
+
+// Mode returns whether the files are scanned before or after they are uploaded.
+func (w *ScanWorker) Mode() string {
+	return w.mode
+}
+
+// StagingKey returns the private key of an object that waits for its scan, the public key is only written once
+// the object is found clean.
+func (w *ScanWorker) StagingKey(objectKey string) string {
+	return path.Join(w.stagingPrefix, objectKey)
+}
+
+// Start spawns the workers. When the files are scanned after the upload, the pending objects are scanned
+// again at startup and on every retry interval, so an object whose scan failed or was lost is not pending forever.
+func (w *ScanWorker) Start() {
+	for i := 0; i < w.workers; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for job := range w.jobs {
+				w.process(job)
+			}
+		}()
+	}
+
+	if w.mode != ScanAfterUpload {
+		return
+	}
+
+	w.retryWG.Add(1)
+	go func() {
+		defer w.retryWG.Done()
+
+		ticker := time.NewTicker(w.retryInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := w.retryPending(context.Background()); err != nil {
+				w.logger.Errorf("failed to find the pending attachments: %v", err)
+			}
+
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops accepting jobs and waits until the queued ones are processed.
+func (w *ScanWorker) Close() {
+	close(w.stop)
+	w.retryWG.Wait()
+	close(w.jobs)
+	w.wg.Wait()
+}
+
+// retryPending enqueues the pending objects that are due, an object is left to its first scan for a retry interval.
+func (w *ScanWorker) retryPending(ctx context.Context) (err error) {
+	objects, err := w.repository.FindManyByStatus(ctx, entity.AttachmentStatusPendingScan)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	pending := make(map[string]bool, len(objects))
+	due := make([]entity.AttachmentObject, 0)
+
+	w.mu.Lock()
+	for _, object := range objects {
+		pending[object.ObjectKey] = true
+
+		retry, ok := w.retries[object.ObjectKey]
+		if !ok {
+			savedAt := object.CreatedAt
+			if object.UpdatedAt != nil {
+				savedAt = *object.UpdatedAt
+			}
+			retry.nextAt = savedAt.Add(w.retryInterval)
+		}
+		if !w.queued[object.ObjectKey] && !now.Before(retry.nextAt) {
+			due = append(due, object)
+		}
+	}
+	for objectKey := range w.retries {
+		if !pending[objectKey] {
+			delete(w.retries, objectKey)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, object := range due {
+		w.logger.WithContext(ctx).Infof("scanning the pending %s again", object.ObjectKey)
+		w.Enqueue(object, nil)
+	}
+	return
+}
+
+// failed delays the next scan of the object, the wait doubles on every failure.
+func (w *ScanWorker) failed(objectKey string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	retry := w.retries[objectKey]
+	retry.attempts++
+	backoff := maxRetryBackoff
+	if retry.attempts <= 16 && w.retryInterval<<(retry.attempts-1) < maxRetryBackoff {
+		backoff = w.retryInterval << (retry.attempts - 1)
+	}
+	retry.nextAt = time.Now().Add(backoff)
+	w.retries[objectKey] = retry
+}
+
+func (w *ScanWorker) done(objectKey string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.queued, objectKey)
+}
+
+// ScanBeforeUpload scans the file synchronously and returns ErrInfected when it must not be uploaded.
+func (w *ScanWorker) ScanBeforeUpload(ctx context.Context, data []byte) (err error) {
+	result, err := w.scanner.Scan(ctx, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	if result.Infected {
+		err = fmt.Errorf("%w: %s", ErrInfected, result.Signature)
+	}
+	return
+}
+
+// Enqueue schedules the scan of an object that is uploaded to its staging key, it is moved to its key when it is clean.
+// The scan runs in the caller's goroutine when the queue is full, so no object stays pending forever.
+// An object that is already queued is skipped.
+func (w *ScanWorker) Enqueue(object entity.AttachmentObject, data []byte) {
+	w.mu.Lock()
+	if w.queued[object.ObjectKey] {
+		w.mu.Unlock()
+		return
+	}
+	w.queued[object.ObjectKey] = true
+	w.mu.Unlock()
+
+	job := scanJob{object: object, key: w.StagingKey(object.ObjectKey), data: data}
+	select {
+	case w.jobs <- job:
+	default:
+		w.logger.Warnf("scan queue is full, scanning %s synchronously", object.ObjectKey)
+		w.process(job)
+	}
+}
+
+func (w *ScanWorker) process(job scanJob) {
+	ctx := context.Background()
+	object := job.object
+	defer w.done(object.ObjectKey)
+
+	result, err := w.scan(ctx, job)
+	if err != nil {
+		// the object stays pending, so it is never served as clean, and it is scanned again later.
+		w.logger.WithContext(ctx).Errorf("failed to scan %s: %v", object.ObjectKey, err)
+		w.failed(object.ObjectKey)
+		return
+	}
+
+	if result.Infected {
+		w.handleInfected(ctx, job, result.Signature)
+		return
+	}
+
+	if err := w.publish(ctx, job); err != nil {
+		// the object stays pending in the staging area until it is published again.
+		w.logger.WithContext(ctx).Errorf("failed to publish %s: %v", object.ObjectKey, err)
+		w.failed(object.ObjectKey)
+		return
+	}
+
+	if w.thumbnailer != nil && w.thumbnailer.Supports(object.ContentType) {
+		w.thumbnailer.Enqueue(object.Bucket, object.ObjectKey, object.ContentType, job.data)
+	}
+}
+
+// publish moves a clean object from its staging key to its public key. The staged copy is kept until the object
+// is recorded as clean, so a failed publication is retried from it.
+func (w *ScanWorker) publish(ctx context.Context, job scanJob) (err error) {
+	object := job.object
+	if err = w.storage.ComposeObject(ctx, object.Bucket, object.ObjectKey, []string{job.key}, object.ContentType); err != nil {
+		return
+	}
+	if err = w.repository.UpdateStatus(ctx, object.ObjectKey, entity.AttachmentStatusClean, nil, time.Now().In(w.location)); err != nil {
+		return
+	}
+	if err := w.storage.DeleteObject(ctx, object.Bucket, job.key); err != nil {
+		// the object is published, the staged copy is left to the reconciler.
+		w.logger.WithContext(ctx).Errorf("failed to delete %s: %v", job.key, err)
+	}
+	w.mu.Lock()
+	delete(w.retries, object.ObjectKey)
+	w.mu.Unlock()
+	return
+}
+
+func (w *ScanWorker) scan(ctx context.Context, job scanJob) (result scanner.Result, err error) {
+	if job.data != nil {
+		return w.scanner.Scan(ctx, bytes.NewReader(job.data))
+	}
+
+	file, err := w.storage.GetObject(ctx, job.object.Bucket, job.key)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	return w.scanner.Scan(ctx, file)
+}
+
+// ScanStored scans an object that is already in the storage.
+// An infected object is dealt with right away and ErrInfected is returned.
+func (w *ScanWorker) ScanStored(ctx context.Context, object entity.AttachmentObject) (err error) {
+	job := scanJob{object: object, key: object.ObjectKey}
+	result, err := w.scan(ctx, job)
+	if err != nil {
+		return
+	}
+	if result.Infected {
+		w.handleInfected(ctx, job, result.Signature)
+		err = fmt.Errorf("%w: %s", ErrInfected, result.Signature)
+	}
+	return
+}
+
+func (w *ScanWorker) quarantine(ctx context.Context, job scanJob, signature string) (err error) {
+	object := job.object
+	var file io.Reader = bytes.NewReader(job.data)
+	if job.data == nil {
+		stored, err := w.storage.GetObject(ctx, object.Bucket, job.key)
+		if err != nil {
+			return err
+		}
+		defer stored.Close()
+		file = stored
+	}
+
+	quarantineKey := path.Join(w.quarantinePrefix, object.ObjectKey)
+	meta := map[string]string{"signature": signature, "original": object.ObjectKey}
+	return w.storage.PutObject(ctx, object.Bucket, quarantineKey, file, object.ContentType, meta)
+}
+
+func (w *ScanWorker) handleInfected(ctx context.Context, job scanJob, signature string) {
+	object := job.object
+	w.logger.WithContext(ctx).Warnf("%s is infected by %s, taking action '%s'", object.ObjectKey, signature, w.infectedAction)
+
+	if w.infectedAction == InfectedActionQuarantine {
+		if err := w.quarantine(ctx, job, signature); err != nil {
+			// keep the original, it is still recorded as infected below.
+			w.logger.WithContext(ctx).Errorf("failed to quarantine %s: %v", object.ObjectKey, err)
+		} else if err := w.storage.DeleteObject(ctx, object.Bucket, job.key); err != nil {
+			w.logger.WithContext(ctx).Errorf("failed to delete %s: %v", job.key, err)
+		}
+	} else if err := w.storage.DeleteObject(ctx, object.Bucket, job.key); err != nil {
+		w.logger.WithContext(ctx).Errorf("failed to delete %s: %v", job.key, err)
+	}
+
+	w.mu.Lock()
+	delete(w.retries, object.ObjectKey)
+	w.mu.Unlock()
+
+	if err := w.repository.UpdateStatus(ctx, object.ObjectKey, entity.AttachmentStatusInfected, &signature, time.Now().In(w.location)); err != nil {
+		w.logger.WithContext(ctx).Errorf("failed to mark %s as infected: %v", object.ObjectKey, err)
+	}
+
+	if w.notifier == nil {
+		return
+	}
+
+	notification := notifier.Notification{
+		Recipient: object.UploadedBy,
+		Subject:   "Infected attachment removed",
+		Message:   fmt.Sprintf("The attachment %s was found infected by %s and has been removed (%s).", object.ObjectKey, signature, w.infectedAction),
+		Data: map[string]string{
+			"bucket":    object.Bucket,
+			"objectKey": object.ObjectKey,
+			"signature": signature,
+			"action":    w.infectedAction,
+		},
+	}
+	if err := w.notifier.Notify(ctx, notification); err != nil {
+		w.logger.WithContext(ctx).Errorf("failed to notify %s: %v", object.UploadedBy, err)
+	}
+}
@@ -0,0 +1,123 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeScanner finds the files that contain EICAR infected.
+type fakeScanner struct{}
+
+func (fakeScanner) Scan(ctx context.Context, file io.Reader) (result scanner.Result, err error) {
+	data, err := io.ReadAll(file)
+	if strings.Contains(string(data), "EICAR") {
+		result = scanner.Result{Infected: true, Signature: "Eicar-Test-Signature"}
+	}
+	return
+}
+
+func TestScanAfterUploadStagesTheObject(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage()
+	repository := newFakeRepository()
+	policy := NewPolicy("bucket", "wr", "https://storage.googleapis.com", map[string]Rule{
+		"todo_attachment": {AllowedTypes: []string{"application/pdf"}, MaxSize: 1 << 10},
+	})
+	scanWorker := NewScanWorker(logrus.New(), time.UTC, storage, repository, fakeScanner{}, nil, nil, ScanWorkerOptions{Mode: ScanAfterUpload, QueueSize: 2})
+	uploader := NewUploader(logrus.New(), time.UTC, storage, policy, repository, scanWorker, nil)
+
+	for name, body := range map[string]string{"clean": "%PDF-1.4", "infected": "%PDF-1.4 EICAR"} {
+		attachment, err := uploader.Upload(ctx, "todo_attachment", File{Body: strings.NewReader(body), Name: name, Extension: ".pdf", ContentType: "application/pdf"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attachment.Status != entity.AttachmentStatusPendingScan {
+			t.Errorf("expected %s to be pending, got %s", name, attachment.Status)
+		}
+	}
+	if _, ok := storage.objects["wr/todo_attachment/clean.pdf"]; ok {
+		t.Fatal("expected the pending object to stay off its public key")
+	}
+	if _, ok := storage.objects["staging/wr/todo_attachment/clean.pdf"]; !ok {
+		t.Fatal("expected the pending object to be staged")
+	}
+
+	scanWorker.Start()
+	scanWorker.Close()
+
+	if _, ok := storage.objects["wr/todo_attachment/clean.pdf"]; !ok {
+		t.Error("expected the clean object to be published")
+	}
+	if _, ok := storage.objects["wr/todo_attachment/infected.pdf"]; ok {
+		t.Error("expected the infected object to never be published")
+	}
+	if _, ok := storage.objects["quarantine/wr/todo_attachment/infected.pdf"]; !ok {
+		t.Error("expected the infected object to be quarantined")
+	}
+	for key := range storage.objects {
+		if strings.HasPrefix(key, "staging/") {
+			t.Errorf("expected the staging area to be emptied, got %s", key)
+		}
+	}
+	if object, _ := repository.FindOneByObjectKey(ctx, "wr/todo_attachment/clean.pdf"); object.Status != entity.AttachmentStatusClean {
+		t.Errorf("expected the published object to be clean, got %s", object.Status)
+	}
+}
+
+// flakyScanner fails its first scans.
+type flakyScanner struct {
+	mu       sync.Mutex
+	failures int
+}
+
+func (s *flakyScanner) Scan(ctx context.Context, file io.Reader) (result scanner.Result, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return result, errors.New("the scanner is unavailable")
+	}
+	return fakeScanner{}.Scan(ctx, file)
+}
+
+func TestScanAfterUploadRetriesThePendingObject(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage()
+	repository := newFakeRepository()
+	policy := NewPolicy("bucket", "wr", "https://storage.googleapis.com", map[string]Rule{
+		"todo_attachment": {AllowedTypes: []string{"application/pdf"}, MaxSize: 1 << 10},
+	})
+	scanWorker := NewScanWorker(logrus.New(), time.UTC, storage, repository, &flakyScanner{failures: 2}, nil, nil, ScanWorkerOptions{Mode: ScanAfterUpload, QueueSize: 2, RetryInterval: time.Millisecond * 10})
+	uploader := NewUploader(logrus.New(), time.UTC, storage, policy, repository, scanWorker, nil)
+
+	if _, err := uploader.Upload(ctx, "todo_attachment", File{Body: strings.NewReader("%PDF-1.4"), Name: "clean", Extension: ".pdf", ContentType: "application/pdf"}); err != nil {
+		t.Fatal(err)
+	}
+
+	scanWorker.Start()
+	deadline := time.Now().Add(time.Second * 2)
+	for {
+		object, _ := repository.FindOneByObjectKey(ctx, "wr/todo_attachment/clean.pdf")
+		if object.Status == entity.AttachmentStatusClean {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the pending object to be scanned again, got %s", object.Status)
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+	scanWorker.Close()
+
+	if keys := storage.keys(); len(keys) != 1 || keys[0] != "wr/todo_attachment/clean.pdf" {
+		t.Errorf("expected only the published object, got %v", keys)
+	}
+}
@@ -0,0 +1,176 @@
+package attachment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SessionStore keeps the state of the resumable uploads, the sessions expire on their own.
+type SessionStore interface {
+	Create(ctx context.Context, session entity.UploadSession) (err error)
+	Get(ctx context.Context, id string) (session entity.UploadSession, err error)
+	// Swap replaces the session only if its stored offset still equals expectedOffset,
+	// otherwise exception.ErrConflict is returned.
+	Swap(ctx context.Context, session entity.UploadSession, expectedOffset int64) (err error)
+	Delete(ctx context.Context, id string) (err error)
+}
+
+type redisSessionStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisSessionStore is a constructor.
+func NewRedisSessionStore(client *redis.Client, keyPrefix string) SessionStore {
+	return &redisSessionStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (s *redisSessionStore) key(id string) string {
+	return s.keyPrefix + id
+}
+
+func (s *redisSessionStore) Create(ctx context.Context, session entity.UploadSession) (err error) {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
+	created, err := s.client.SetNX(ctx, s.key(session.ID), value, time.Until(session.ExpiresAt)).Result()
+	if err != nil {
+		return
+	}
+	if !created {
+		err = exception.ErrConflict
+	}
+	return
+}
+
+func (s *redisSessionStore) Get(ctx context.Context, id string) (session entity.UploadSession, err error) {
+	value, err := s.client.Get(ctx, s.key(id)).Bytes()
+	if err == redis.Nil {
+		err = exception.ErrNotFound
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(value, &session)
+	return
+}
+
+func (s *redisSessionStore) Swap(ctx context.Context, session entity.UploadSession, expectedOffset int64) (err error) {
+	key := s.key(session.ID)
+	value, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
+	err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return exception.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var current entity.UploadSession
+		if err := json.Unmarshal(stored, &current); err != nil {
+			return err
+		}
+		if current.Offset != expectedOffset {
+			return exception.ErrConflict
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, time.Until(session.ExpiresAt))
+			return nil
+		})
+		return err
+	}, key)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		err = exception.ErrConflict
+	}
+	return
+}
+
+func (s *redisSessionStore) Delete(ctx context.Context, id string) (err error) {
+	return s.client.Del(ctx, s.key(id)).Err()
+}
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]entity.UploadSession
+}
+
+// NewMemorySessionStore returns a session store that lives in the process memory.
+// It is meant for a single instance deployment and tests.
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{sessions: make(map[string]entity.UploadSession)}
+}
+
+func (s *memorySessionStore) get(id string) (session entity.UploadSession, ok bool) {
+	session, ok = s.sessions[id]
+	if ok && time.Now().After(session.ExpiresAt) {
+		delete(s.sessions, id)
+		ok = false
+	}
+	return
+}
+
+func (s *memorySessionStore) Create(ctx context.Context, session entity.UploadSession) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.get(session.ID); ok {
+		return exception.ErrConflict
+	}
+	s.sessions[session.ID] = session
+	return
+}
+
+func (s *memorySessionStore) Get(ctx context.Context, id string) (session entity.UploadSession, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.get(id)
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
+
+func (s *memorySessionStore) Swap(ctx context.Context, session entity.UploadSession, expectedOffset int64) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.get(session.ID)
+	if !ok {
+		return exception.ErrNotFound
+	}
+	if current.Offset != expectedOffset {
+		return exception.ErrConflict
+	}
+	s.sessions[session.ID] = session
+	return
+}
+
+func (s *memorySessionStore) Delete(ctx context.Context, id string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	return
+}
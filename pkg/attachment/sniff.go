@@ -0,0 +1,70 @@
+package attachment
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLength is the number of bytes http.DetectContentType considers.
+const sniffLength = 512
+
+// typeExtensions maps the supported content types to their known file extensions.
+// The first extension is the canonical one.
+var typeExtensions = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"application/pdf": {".pdf"},
+	"text/plain":      {".txt"},
+}
+
+// Sniff detects the content type of the file from its first bytes.
+// The returned reader replays the consumed bytes, so it must be used instead of the given one.
+func Sniff(file io.Reader) (contentType string, body io.Reader, err error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return
+	}
+	err = nil
+	head = head[:n]
+
+	contentType = normalizeContentType(http.DetectContentType(head))
+	body = io.MultiReader(bytes.NewReader(head), file)
+	return
+}
+
+// Extension returns the extension that will be used to store the file.
+// The extension of the original file name is kept when it is a known extension of the content type.
+func Extension(contentType, fileName string) string {
+	extensions, ok := typeExtensions[normalizeContentType(contentType)]
+	if !ok {
+		return ""
+	}
+
+	lowerFileName := strings.ToLower(fileName)
+	for _, extension := range extensions {
+		if strings.HasSuffix(lowerFileName, extension) {
+			return extension
+		}
+	}
+
+	return extensions[0]
+}
+
+// IsImage reports whether the content type is an image.
+func IsImage(contentType string) bool {
+	return strings.HasPrefix(normalizeContentType(contentType), "image/")
+}
+
+func normalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
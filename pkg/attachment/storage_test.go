@@ -0,0 +1,146 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/storage"
+)
+
+type fakeStorage struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	createdAt map[string]time.Time
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{objects: make(map[string][]byte), createdAt: make(map[string]time.Time)}
+}
+
+func (s *fakeStorage) PutObject(ctx context.Context, bucketName string, filepath string, file io.Reader, contentType string, meta map[string]string) (err error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[filepath] = data
+	s.createdAt[filepath] = time.Now()
+	return
+}
+
+func (s *fakeStorage) SignURL(ctx context.Context, method, bucketName, filepath string, expiresIn time.Duration) (url string, err error) {
+	return filepath, nil
+}
+
+func (s *fakeStorage) DeleteObject(ctx context.Context, bucketName string, filepath string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, filepath)
+	return
+}
+
+func (s *fakeStorage) GetObject(ctx context.Context, bucketName string, filepath string) (file io.ReadCloser, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[filepath]
+	if !ok {
+		return nil, exception.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeStorage) ComposeObject(ctx context.Context, bucketName string, filepath string, sources []string, contentType string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var composed []byte
+	for _, source := range sources {
+		composed = append(composed, s.objects[source]...)
+	}
+	s.objects[filepath] = composed
+	s.createdAt[filepath] = time.Now()
+	return
+}
+
+func (s *fakeStorage) ListObjects(ctx context.Context, bucketName string, prefix string) (objects []storage.ObjectAttrs, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, data := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, storage.ObjectAttrs{Name: key, Size: int64(len(data)), CreatedAt: s.createdAt[key]})
+		}
+	}
+	return
+}
+
+func (s *fakeStorage) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type fakeRepository struct {
+	mu      sync.Mutex
+	objects map[string]entity.AttachmentObject
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{objects: make(map[string]entity.AttachmentObject)}
+}
+
+func (r *fakeRepository) Save(ctx context.Context, object entity.AttachmentObject) (id int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	object.ID = int64(len(r.objects) + 1)
+	r.objects[object.ObjectKey] = object
+	return object.ID, nil
+}
+
+func (r *fakeRepository) UpdateStatus(ctx context.Context, objectKey string, status string, signature *string, updatedAt time.Time) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	object := r.objects[objectKey]
+	object.Status = status
+	object.Signature = signature
+	r.objects[objectKey] = object
+	return
+}
+
+func (r *fakeRepository) DeleteByObjectKey(ctx context.Context, objectKey string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.objects, objectKey)
+	return
+}
+
+func (r *fakeRepository) FindManyByStatus(ctx context.Context, status string) (objects []entity.AttachmentObject, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, object := range r.objects {
+		if object.Status == status {
+			objects = append(objects, object)
+		}
+	}
+	return
+}
+
+func (r *fakeRepository) FindOneByObjectKey(ctx context.Context, objectKey string) (object entity.AttachmentObject, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	object, ok := r.objects[objectKey]
+	if !ok {
+		err = exception.ErrNotFound
+	}
+	return
+}
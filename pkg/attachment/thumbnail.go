@@ -0,0 +1,196 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"todo-app-api/pkg/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	thumbnailJPEGQuality = 85
+	// thumbnailMaxSourceSize caps the images loaded back from the storage, they are decoded in memory.
+	thumbnailMaxSourceSize = 50 << 20
+)
+
+// thumbnailJob is a single image waiting to get its variants.
+type thumbnailJob struct {
+	bucket      string
+	objectKey   string
+	contentType string
+	data        []byte
+}
+
+// Thumbnailer generates resized variants of the uploaded images in a bounded worker pool.
+type Thumbnailer struct {
+	logger  *logrus.Logger
+	storage storage.Storage
+	sizes   []int
+	workers int
+	// maxPixels skips the images that declare more pixels, a small file can declare a huge image.
+	maxPixels int64
+	jobs      chan thumbnailJob
+	wg        sync.WaitGroup
+}
+
+// NewThumbnailer is a constructor.
+func NewThumbnailer(logger *logrus.Logger, storage storage.Storage, sizes []int, workers, queueSize int, maxPixels int64) *Thumbnailer {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	return &Thumbnailer{
+		logger:    logger,
+		storage:   storage,
+		sizes:     sizes,
+		workers:   workers,
+		maxPixels: maxPixels,
+		jobs:      make(chan thumbnailJob, queueSize),
+	}
+}
+
+// Start spawns the workers.
+func (t *Thumbnailer) Start() {
+	for i := 0; i < t.workers; i++ {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			for job := range t.jobs {
+				t.process(job)
+			}
+		}()
+	}
+}
+
+// Close stops accepting jobs and waits until the queued ones are processed.
+func (t *Thumbnailer) Close() {
+	close(t.jobs)
+	t.wg.Wait()
+}
+
+// Supports reports whether the variants of the content type can be generated.
+func (t *Thumbnailer) Supports(contentType string) bool {
+	switch normalizeContentType(contentType) {
+	case "image/jpeg", "image/png":
+		return len(t.sizes) > 0
+	}
+	return false
+}
+
+// Enqueue schedules the variants generation without blocking the caller.
+// The image is loaded back from the storage when data is nil.
+// It returns false when the queue is full, the upload itself is not affected by it.
+func (t *Thumbnailer) Enqueue(bucket, objectKey, contentType string, data []byte) bool {
+	select {
+	case t.jobs <- thumbnailJob{bucket: bucket, objectKey: objectKey, contentType: contentType, data: data}:
+		return true
+	default:
+		t.logger.Warnf("thumbnail queue is full, skipping %s", objectKey)
+		return false
+	}
+}
+
+// Sizes returns the sizes of the generated variants.
+func (t *Thumbnailer) Sizes() []int {
+	return t.sizes
+}
+
+// VariantKey returns the object key of the variant of the original object.
+func VariantKey(objectKey, contentType string, size int) string {
+	extension := path.Ext(objectKey)
+	if normalizeContentType(contentType) == "image/jpeg" {
+		extension = ".jpg"
+	}
+	return fmt.Sprintf("%s_%dpx%s", strings.TrimSuffix(objectKey, path.Ext(objectKey)), size, extension)
+}
+
+func (t *Thumbnailer) process(job thumbnailJob) {
+	ctx := context.Background()
+
+	if job.data == nil {
+		data, err := t.load(ctx, job)
+		if err != nil {
+			t.logger.WithContext(ctx).Errorf("failed to load %s: %v", job.objectKey, err)
+			return
+		}
+		job.data = data
+	}
+
+	// the header is read first, decoding allocates the declared size.
+	if err := checkPixels(job.data, t.maxPixels); err != nil {
+		t.logger.WithContext(ctx).Warnf("skipping the variants of %s: %v", job.objectKey, err)
+		return
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(job.data))
+	if err != nil {
+		t.logger.WithContext(ctx).Errorf("failed to decode %s: %v", job.objectKey, err)
+		return
+	}
+
+	contentType := normalizeContentType(job.contentType)
+	if contentType == "image/jpeg" {
+		src = orient(src, jpegOrientation(job.data))
+	}
+
+	for _, size := range t.sizes {
+		variant := resize(src, size)
+
+		var buf bytes.Buffer
+		if contentType == "image/png" {
+			err = png.Encode(&buf, variant)
+		} else {
+			err = jpeg.Encode(&buf, variant, &jpeg.Options{Quality: thumbnailJPEGQuality})
+		}
+		if err != nil {
+			t.logger.WithContext(ctx).Errorf("failed to encode %dpx variant of %s: %v", size, job.objectKey, err)
+			continue
+		}
+
+		variantKey := VariantKey(job.objectKey, contentType, size)
+		meta := map[string]string{"original": job.objectKey}
+		if err := t.storage.PutObject(ctx, job.bucket, variantKey, &buf, contentType, meta); err != nil {
+			t.logger.WithContext(ctx).Errorf("failed to store %s: %v", variantKey, err)
+		}
+	}
+}
+
+// checkPixels returns an error when the image declares more than maxPixels pixels.
+func checkPixels(data []byte, maxPixels int64) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxPixels {
+		return fmt.Errorf("image is %dx%d, more than %d pixels", config.Width, config.Height, maxPixels)
+	}
+	return nil
+}
+
+func (t *Thumbnailer) load(ctx context.Context, job thumbnailJob) (data []byte, err error) {
+	file, err := t.storage.GetObject(ctx, job.bucket, job.objectKey)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	if data, err = io.ReadAll(io.LimitReader(file, thumbnailMaxSourceSize+1)); err != nil {
+		return
+	}
+	if len(data) > thumbnailMaxSourceSize {
+		err = fmt.Errorf("image is larger than %d bytes", thumbnailMaxSourceSize)
+	}
+	return
+}
@@ -0,0 +1,108 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestResizeKeepsAspectRatio(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	dst := resize(src, 100)
+	if dst.Bounds().Dx() != 100 || dst.Bounds().Dy() != 50 {
+		t.Errorf("expected 100x50, got %v", dst.Bounds())
+	}
+
+	small := resize(src, 1024)
+	if small.Bounds().Dx() != 400 {
+		t.Errorf("expected no upscaling, got %v", small.Bounds())
+	}
+}
+
+func TestOrientRotatesClockwise(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+
+	dst := orient(src, 6)
+	if dst.Bounds().Dx() != 1 || dst.Bounds().Dy() != 2 {
+		t.Fatalf("expected 1x2, got %v", dst.Bounds())
+	}
+	if r, _, _, _ := dst.At(0, 0).RGBA(); r == 0 {
+		t.Error("expected the top-left pixel to stay on top after a clockwise rotation")
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	// big endian TIFF header with a single IFD entry: Orientation (0x0112), SHORT, count 1, value 6.
+	tiff := []byte{'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x00}
+	exif := append([]byte("Exif\x00\x00"), tiff...)
+	segmentLength := len(exif) + 2
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(segmentLength >> 8), byte(segmentLength)}
+	data = append(data, exif...)
+	data = append(data, 0xFF, 0xDA, 0x00, 0x02)
+
+	if orientation := jpegOrientation(data); orientation != 6 {
+		t.Errorf("expected orientation 6, got %d", orientation)
+	}
+	if orientation := jpegOrientation([]byte("not a jpeg")); orientation != 1 {
+		t.Errorf("expected orientation 1, got %d", orientation)
+	}
+}
+
+func TestThumbnailerStoresVariants(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 300, 150)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := newFakeStorage()
+	thumbnailer := NewThumbnailer(logrus.New(), storage, []int{64, 256}, 1, 1, 1<<20)
+	thumbnailer.Start()
+	if !thumbnailer.Enqueue("bucket", "wr/todo_attachment/photo.jpeg", "image/jpeg", buf.Bytes()) {
+		t.Fatal("expected the job to be queued")
+	}
+	thumbnailer.Close()
+
+	variant, ok := storage.objects["wr/todo_attachment/photo_64px.jpg"]
+	if !ok {
+		t.Fatalf("expected the 64px variant to be stored, got %d objects", len(storage.objects))
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(variant))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Bounds().Dx() != 64 || decoded.Bounds().Dy() != 32 {
+		t.Errorf("expected 64x32, got %v", decoded.Bounds())
+	}
+	if _, ok := storage.objects["wr/todo_attachment/photo_256px.jpg"]; !ok {
+		t.Error("expected the 256px variant to be stored")
+	}
+}
+
+func TestThumbnailerSkipsDecompressionBombs(t *testing.T) {
+	// a png header that declares 50000x50000 pixels, decoding it would allocate 10GB.
+	ihdr := []byte{'I', 'H', 'D', 'R', 0x00, 0x00, 0xC3, 0x50, 0x00, 0x00, 0xC3, 0x50, 0x08, 0x06, 0x00, 0x00, 0x00}
+	bomb := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"), ihdr...)
+	bomb = binary.BigEndian.AppendUint32(bomb, crc32.ChecksumIEEE(ihdr))
+
+	if err := checkPixels(bomb, 40000000); err == nil {
+		t.Error("expected the declared size to be rejected")
+	}
+
+	storage := newFakeStorage()
+	thumbnailer := NewThumbnailer(logrus.New(), storage, []int{64}, 1, 1, 40000000)
+	thumbnailer.Start()
+	thumbnailer.Enqueue("bucket", "wr/todo_attachment/bomb.png", "image/png", bomb)
+	thumbnailer.Close()
+
+	if len(storage.objects) != 0 {
+		t.Errorf("expected no variant, got %d objects", len(storage.objects))
+	}
+}
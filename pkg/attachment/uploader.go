@@ -0,0 +1,188 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+// File is an attachment that has passed the policy check.
+type File struct {
+	Body        io.Reader
+	Name        string
+	Extension   string
+	ContentType string
+	Size        int64
+	UploadedBy  string
+}
+
+// Uploader stores the attachments and schedules their post processing.
+type Uploader struct {
+	logger      *logrus.Logger
+	location    *time.Location
+	storage     storage.Storage
+	policy      *Policy
+	repository  Repository
+	scanWorker  *ScanWorker
+	thumbnailer *Thumbnailer
+}
+
+// NewUploader is a constructor. The scan worker and the thumbnailer are optional.
+func NewUploader(logger *logrus.Logger, location *time.Location, storage storage.Storage, policy *Policy, repository Repository, scanWorker *ScanWorker, thumbnailer *Thumbnailer) *Uploader {
+	return &Uploader{
+		logger:      logger,
+		location:    location,
+		storage:     storage,
+		policy:      policy,
+		repository:  repository,
+		scanWorker:  scanWorker,
+		thumbnailer: thumbnailer,
+	}
+}
+
+// Upload puts the file into the attachment bucket.
+// When the file is scanned after the upload, it stays in the pending_scan state under its private staging key
+// until the verdict is out, so its public url serves nothing before it is found clean.
+func (u *Uploader) Upload(ctx context.Context, folder string, file File) (attachment entity.Attachment, err error) {
+	bucketName := u.policy.Bucket()
+	objectKey := u.policy.ObjectKey(folder, file.Name, file.Extension)
+
+	// the policy has capped the size, so the file can be kept in memory for the scanner and the thumbnailer.
+	data, err := io.ReadAll(file.Body)
+	if err != nil {
+		return
+	}
+
+	if u.scanWorker != nil && u.scanWorker.Mode() == ScanBeforeUpload {
+		if err = u.scanWorker.ScanBeforeUpload(ctx, data); err != nil {
+			return
+		}
+	}
+
+	// the upload should not be cancelled when the client goes away in the middle of it.
+	if err = u.storage.PutObject(context.Background(), bucketName, u.storedKey(objectKey), bytes.NewReader(data), file.ContentType, nil); err != nil {
+		return
+	}
+
+	object := entity.AttachmentObject{
+		Bucket:      bucketName,
+		ObjectKey:   objectKey,
+		Folder:      folder,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		UploadedBy:  file.UploadedBy,
+	}
+
+	return u.register(ctx, object, data)
+}
+
+// register records the stored object and schedules its scan and variants.
+// The object is read back from the storage when data is nil.
+func (u *Uploader) register(ctx context.Context, object entity.AttachmentObject, data []byte) (attachment entity.Attachment, err error) {
+	scanAfterUpload := u.scanWorker != nil && u.scanWorker.Mode() == ScanAfterUpload
+	// an object that is not in memory yet has not been scanned before the upload.
+	scanStored := u.scanWorker != nil && !scanAfterUpload && data == nil
+
+	object.Status = entity.AttachmentStatusClean
+	if scanAfterUpload || scanStored {
+		object.Status = entity.AttachmentStatusPendingScan
+	}
+	object.CreatedAt = time.Now().In(u.location)
+
+	if object.ID, err = u.repository.Save(ctx, object); err != nil {
+		return
+	}
+
+	if scanStored {
+		if err = u.scanWorker.ScanStored(ctx, object); err != nil {
+			return
+		}
+		object.Status = entity.AttachmentStatusClean
+		if err = u.repository.UpdateStatus(ctx, object.ObjectKey, object.Status, nil, time.Now().In(u.location)); err != nil {
+			return
+		}
+	}
+
+	attachment = entity.Attachment{
+		ImageURL:    u.policy.PublicURL(object.ObjectKey),
+		Status:      object.Status,
+		ContentType: object.ContentType,
+		Size:        object.Size,
+	}
+
+	withVariants := u.thumbnailer != nil && u.thumbnailer.Supports(object.ContentType)
+	if scanAfterUpload {
+		// the variants are generated once the object is known to be clean.
+		u.scanWorker.Enqueue(object, data)
+	} else if withVariants {
+		withVariants = u.thumbnailer.Enqueue(object.Bucket, object.ObjectKey, object.ContentType, data)
+	}
+
+	if withVariants {
+		for _, size := range u.thumbnailer.Sizes() {
+			attachment.Variants = append(attachment.Variants, entity.AttachmentVariant{
+				Size: size,
+				URL:  u.policy.PublicURL(VariantKey(object.ObjectKey, object.ContentType, size)),
+			})
+		}
+	}
+
+	return
+}
+
+// storedKey returns the key an object is uploaded to, the staging key when it is scanned after the upload.
+func (u *Uploader) storedKey(objectKey string) string {
+	if u.scanWorker != nil && u.scanWorker.Mode() == ScanAfterUpload {
+		return u.scanWorker.StagingKey(objectKey)
+	}
+	return objectKey
+}
+
+// DeleteByURL removes the attachment of the public url.
+// exception.ErrNotFound is returned when the url is not an attachment of the bucket.
+func (u *Uploader) DeleteByURL(ctx context.Context, url string) (err error) {
+	objectKey, ok := u.policy.ObjectKeyFromURL(url)
+	if !ok {
+		return exception.ErrNotFound
+	}
+	return u.Delete(ctx, objectKey)
+}
+
+// Delete removes the object, its variants and its record.
+// Variants of sizes that are no longer configured are left to the reconciler.
+func (u *Uploader) Delete(ctx context.Context, objectKey string) (err error) {
+	object, err := u.repository.FindOneByObjectKey(ctx, objectKey)
+	if err != nil && err != exception.ErrNotFound {
+		return
+	}
+	recorded := err == nil
+
+	bucketName := u.policy.Bucket()
+	storedKey := objectKey
+	if recorded && object.Status == entity.AttachmentStatusPendingScan {
+		storedKey = u.storedKey(objectKey)
+	}
+	if err = u.storage.DeleteObject(ctx, bucketName, storedKey); err != nil {
+		return
+	}
+
+	if !recorded {
+		return
+	}
+
+	if u.thumbnailer != nil && u.thumbnailer.Supports(object.ContentType) {
+		for _, size := range u.thumbnailer.Sizes() {
+			if err = u.storage.DeleteObject(ctx, bucketName, VariantKey(objectKey, object.ContentType, size)); err != nil {
+				return
+			}
+		}
+	}
+
+	return u.repository.DeleteByObjectKey(ctx, objectKey)
+}
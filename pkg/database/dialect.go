@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Dialect is the sql flavor of a database, the repositories write their queries with ? placeholders.
+type Dialect interface {
+	// Name is the name of the dialect, it is also the directory of its migrations.
+	Name() string
+	// Placeholder is the placeholder format of the squirrel builders.
+	Placeholder() sq.PlaceholderFormat
+	// Rebind replaces the ? placeholders of a raw query with the placeholders of the dialect.
+	Rebind(query string) string
+	// InsertReturningID runs an insert and returns the id of the inserted row.
+	InsertReturningID(ctx context.Context, cmd Command, query string, args ...interface{}) (id int64, err error)
+	// ReplicationLag returns how far the replica is behind its primary, zero when the database isn't a replica.
+	ReplicationLag(ctx context.Context, db *sql.DB) (lag time.Duration, err error)
+	// FullText returns the condition and the relevance of a natural language search of the text columns, both take
+	// the query as their only argument. The columns need a full-text index, see the migrations of the dialect.
+	FullText(columns ...string) (match string, score string)
+}
+
+// ErrReplicationStopped is returned by ReplicationLag when the replica doesn't replicate.
+var ErrReplicationStopped = errors.New("replication is stopped")
+
+// Command is either a *sql.DB, a *sql.Tx or a *sql.Conn.
+type Command interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var (
+	// MySQL is the dialect of MySQL and MariaDB.
+	MySQL Dialect = mysqlDialect{}
+	// Postgres is the dialect of PostgreSQL.
+	Postgres Dialect = postgresDialect{}
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+// FullText searches a FULLTEXT index of the columns, MySQL computes the repeated MATCH once.
+func (mysqlDialect) FullText(columns ...string) (match string, score string) {
+	match = "MATCH(" + strings.Join(columns, ", ") + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	return match, match
+}
+
+func (mysqlDialect) Placeholder() sq.PlaceholderFormat { return sq.Question }
+
+func (mysqlDialect) Rebind(query string) string { return query }
+
+func (mysqlDialect) InsertReturningID(ctx context.Context, cmd Command, query string, args ...interface{}) (id int64, err error) {
+	res, err := cmd.ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	return res.LastInsertId()
+}
+
+// ReplicationLag reads Seconds_Behind_Master, it is NULL when the replication threads are stopped.
+func (mysqlDialect) ReplicationLag(ctx context.Context, db *sql.DB) (lag time.Duration, err error) {
+	rows, err := db.QueryContext(ctx, `SHOW SLAVE STATUS`)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = rows.Scan(dest...); err != nil {
+		return
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Master" {
+			continue
+		}
+		if values[i] == nil {
+			return 0, ErrReplicationStopped
+		}
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+// FullText searches the simple tsvector of the columns, the expression of the GIN index of the migrations.
+// The words of the query are or-ed like in the natural language mode of MySQL.
+func (postgresDialect) FullText(columns ...string) (match string, score string) {
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = "coalesce(" + column + ", '')"
+	}
+	document := "to_tsvector('simple', " + strings.Join(parts, " || ' ' || ") + ")"
+	query := "replace(plainto_tsquery('simple', ?)::text, ' & ', ' | ')::tsquery"
+	return document + " @@ " + query, "ts_rank(" + document + ", " + query + ")"
+}
+
+func (postgresDialect) Placeholder() sq.PlaceholderFormat { return sq.Dollar }
+
+// Rebind numbers the placeholders, a ? inside a quoted literal is kept.
+func (postgresDialect) Rebind(query string) string {
+	var b strings.Builder
+	var quote rune
+	n := 0
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// InsertReturningID appends RETURNING id, postgres drivers don't support LastInsertId.
+func (d postgresDialect) InsertReturningID(ctx context.Context, cmd Command, query string, args ...interface{}) (id int64, err error) {
+	err = cmd.QueryRowContext(ctx, d.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return
+}
+
+// ReplicationLag is the age of the latest replayed transaction, a replica without writes to replay isn't lagging.
+func (postgresDialect) ReplicationLag(ctx context.Context, db *sql.DB) (lag time.Duration, err error) {
+	var seconds float64
+	err = db.QueryRowContext(ctx, `SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`).Scan(&seconds)
+	lag = time.Duration(seconds * float64(time.Second))
+	return
+}
+
+// IsUniqueViolation tells whether the error is a duplicate key error of MySQL (1062) or PostgreSQL (23505).
+func IsUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+	"todo-app-api/pkg/database"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRebind(t *testing.T) {
+	query := `SELECT u.uuid FROM user_encrypt u WHERE u.uuid = ? AND u.name <> '?' AND u.email = ?`
+
+	if rebound := database.MySQL.Rebind(query); rebound != query {
+		t.Errorf("mysql changed the query to %s", rebound)
+	}
+
+	expected := `SELECT u.uuid FROM user_encrypt u WHERE u.uuid = $1 AND u.name <> '?' AND u.email = $2`
+	if rebound := database.Postgres.Rebind(query); rebound != expected {
+		t.Errorf("expected %s, got %s", expected, rebound)
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{&mysql.MySQLError{Number: 1062}, true},
+		{&mysql.MySQLError{Number: 1045}, false},
+		{&pgconn.PgError{Code: "23505"}, true},
+		{fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"}), true},
+		{&pgconn.PgError{Code: "23503"}, false},
+		{fmt.Errorf("other"), false},
+	}
+
+	for _, c := range cases {
+		if actual := database.IsUniqueViolation(c.err); actual != c.expected {
+			t.Errorf("%v: expected %t, got %t", c.err, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RouterOptions are the health check and the routing settings of a Router.
+type RouterOptions struct {
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+	// MaxReplicaLag is the lag past which the reads go to the primary, zero disables the lag check.
+	MaxReplicaLag time.Duration
+	// ReadYourWritesWindow is how long the reads of a client go to the primary after it writes, zero disables it.
+	ReadYourWritesWindow time.Duration
+	// ClientKey identifies the client of a context, the reads of a context without a client are never pinned.
+	ClientKey func(ctx context.Context) string
+}
+
+// primaryContextKey marks a context whose reads go to the primary.
+type primaryContextKey struct{}
+
+// WithPrimary returns a context whose reads go to the primary, for a read that a write is decided on.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// Router routes the reads to the replica and the writes to the primary.
+// The reads fall back to the primary when the replica is unhealthy or lagging,
+// and right after the same client wrote, so it reads its own writes.
+type Router struct {
+	logger  *logrus.Logger
+	primary *sql.DB
+	replica *sql.DB
+	dialect Dialect
+	options RouterOptions
+
+	mu             sync.RWMutex
+	primaryHealthy bool
+	replicaHealthy bool
+	replicaLag     time.Duration
+	writes         map[string]time.Time
+	// pruneAt is when the writes are forgotten next, so the writes don't pile up without health checks.
+	pruneAt time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// RouterStatus is the last known state of the pools.
+type RouterStatus struct {
+	PrimaryHealthy bool
+	ReplicaHealthy bool
+	ReplicaLag     time.Duration
+}
+
+// NewRouter is a constructor. Both pools are assumed healthy until the first health check.
+func NewRouter(logger *logrus.Logger, primary *sql.DB, replica *sql.DB, dialect Dialect, options RouterOptions) *Router {
+	if options.HealthCheckTimeout <= 0 {
+		options.HealthCheckTimeout = time.Second * 2
+	}
+	return &Router{
+		logger:         logger,
+		primary:        primary,
+		replica:        replica,
+		dialect:        dialect,
+		options:        options,
+		primaryHealthy: true,
+		replicaHealthy: true,
+		writes:         make(map[string]time.Time),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Dialect is the dialect of both pools.
+func (r *Router) Dialect() Dialect {
+	return r.dialect
+}
+
+// Writer returns the primary and pins the reads of the client to it for the read-your-writes window.
+func (r *Router) Writer(ctx context.Context) *sql.DB {
+	if key := r.clientKey(ctx); key != "" && r.options.ReadYourWritesWindow > 0 {
+		now := time.Now()
+		r.mu.Lock()
+		r.writes[key] = now.Add(r.options.ReadYourWritesWindow)
+		if now.After(r.pruneAt) {
+			r.forgetWrites(now)
+		}
+		r.mu.Unlock()
+	}
+	return r.primary
+}
+
+// Reader returns the pool that serves the reads of the context.
+func (r *Router) Reader(ctx context.Context) *sql.DB {
+	if primary, _ := ctx.Value(primaryContextKey{}).(bool); primary {
+		return r.primary
+	}
+	key := r.clientKey(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if key != "" {
+		if until, ok := r.writes[key]; ok && time.Now().Before(until) {
+			return r.primary
+		}
+	}
+	if !r.replicaHealthy {
+		return r.primary
+	}
+	// a lagging replica is still better than a primary that is down.
+	if r.options.MaxReplicaLag > 0 && r.replicaLag > r.options.MaxReplicaLag && r.primaryHealthy {
+		return r.primary
+	}
+	return r.replica
+}
+
+// Status returns the result of the latest health check.
+func (r *Router) Status() RouterStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return RouterStatus{PrimaryHealthy: r.primaryHealthy, ReplicaHealthy: r.replicaHealthy, ReplicaLag: r.replicaLag}
+}
+
+// Start checks the health of the pools once, then in the background.
+func (r *Router) Start() {
+	r.Check(context.Background())
+	if r.options.HealthCheckInterval <= 0 {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.options.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.Check(context.Background())
+			}
+		}
+	}()
+}
+
+// Close stops the health checks, the pools are closed by their owner.
+func (r *Router) Close() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+// Check pings both pools, measures the lag of the replica and forgets the expired writes.
+func (r *Router) Check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, r.options.HealthCheckTimeout)
+	defer cancel()
+
+	primaryErr := r.primary.PingContext(ctx)
+	replicaErr := r.replica.PingContext(ctx)
+
+	var lag time.Duration
+	if replicaErr == nil && r.options.MaxReplicaLag > 0 {
+		var err error
+		if lag, err = r.dialect.ReplicationLag(ctx, r.replica); err != nil {
+			if err == ErrReplicationStopped {
+				replicaErr = err
+			} else {
+				// the lag can't be read without the privilege, the ping alone decides then.
+				r.logger.Warn("failed to read the replication lag: ", err)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if healthy := primaryErr == nil; healthy != r.primaryHealthy {
+		r.primaryHealthy = healthy
+		r.logTransition("primary", healthy, primaryErr)
+	}
+	if healthy := replicaErr == nil; healthy != r.replicaHealthy {
+		r.replicaHealthy = healthy
+		r.logTransition("replica", healthy, replicaErr)
+	}
+
+	maxLag := r.options.MaxReplicaLag
+	if wasLagging, lagging := r.replicaLag > maxLag, lag > maxLag; maxLag > 0 && wasLagging != lagging {
+		if lagging {
+			r.logger.WithField("database.replica_lag", lag.String()).Warn("replica is lagging, reads are routed to the primary")
+		} else {
+			r.logger.WithField("database.replica_lag", lag.String()).Info("replica caught up, reads are routed to the replica")
+		}
+	}
+	r.replicaLag = lag
+	r.forgetWrites(time.Now())
+}
+
+// forgetWrites deletes the expired writes, the caller holds the lock.
+func (r *Router) forgetWrites(now time.Time) {
+	for key, until := range r.writes {
+		if now.After(until) {
+			delete(r.writes, key)
+		}
+	}
+	r.pruneAt = now.Add(r.options.ReadYourWritesWindow)
+}
+
+func (r *Router) logTransition(pool string, healthy bool, err error) {
+	logger := r.logger.WithField("database.pool", pool)
+	if healthy {
+		logger.Info(pool + " is healthy again")
+		return
+	}
+	logger.Error(pool+" is unhealthy: ", err)
+}
+
+func (r *Router) clientKey(ctx context.Context) string {
+	if r.options.ClientKey == nil {
+		return ""
+	}
+	return r.options.ClientKey(ctx)
+}
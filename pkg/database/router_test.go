@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+)
+
+type clientKey struct{}
+
+func newTestRouter(t *testing.T) (router *Router, primary *sql.DB, replica *sql.DB) {
+	// the pools connect on first use, nothing listens on the port.
+	primary, _ = sql.Open("mysql", "root@tcp(127.0.0.1:1)/primary")
+	replica, _ = sql.Open("mysql", "root@tcp(127.0.0.1:1)/replica")
+	t.Cleanup(func() {
+		primary.Close()
+		replica.Close()
+	})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	router = NewRouter(logger, primary, replica, MySQL, RouterOptions{
+		HealthCheckTimeout:   time.Second,
+		MaxReplicaLag:        time.Second * 5,
+		ReadYourWritesWindow: time.Minute,
+		ClientKey: func(ctx context.Context) string {
+			key, _ := ctx.Value(clientKey{}).(string)
+			return key
+		},
+	})
+	return
+}
+
+func TestRouterReadYourWrites(t *testing.T) {
+	router, primary, replica := newTestRouter(t)
+	alice := context.WithValue(context.Background(), clientKey{}, "alice")
+	bob := context.WithValue(context.Background(), clientKey{}, "bob")
+
+	if router.Reader(alice) != replica {
+		t.Fatal("expected the reads to go to the replica")
+	}
+	if router.Writer(alice) != primary {
+		t.Fatal("expected the writes to go to the primary")
+	}
+	if router.Reader(alice) != primary {
+		t.Error("expected the reads of the writer to go to the primary")
+	}
+	if router.Reader(bob) != replica || router.Reader(context.Background()) != replica {
+		t.Error("expected the reads of the other clients to go to the replica")
+	}
+	if router.Reader(WithPrimary(bob)) != primary {
+		t.Error("expected the reads of a context with the primary to go to the primary")
+	}
+
+	router.writes["alice"] = time.Now().Add(-time.Second)
+	if router.Reader(alice) != replica {
+		t.Error("expected the reads to go back to the replica after the window")
+	}
+
+	// the writes are forgotten without a health check too.
+	router.pruneAt = time.Time{}
+	router.Writer(bob)
+	if _, ok := router.writes["alice"]; ok || len(router.writes) != 1 {
+		t.Errorf("expected the expired write to be forgotten, got %v", router.writes)
+	}
+}
+
+func TestRouterReplicaHealth(t *testing.T) {
+	router, primary, replica := newTestRouter(t)
+	ctx := context.Background()
+
+	router.replicaLag = time.Second * 10
+	if router.Reader(ctx) != primary {
+		t.Error("expected the reads to go to the primary while the replica lags")
+	}
+
+	router.primaryHealthy = false
+	if router.Reader(ctx) != replica {
+		t.Error("expected the reads to stay on the lagging replica while the primary is down")
+	}
+
+	router.Check(ctx)
+	if status := router.Status(); status.PrimaryHealthy || status.ReplicaHealthy {
+		t.Fatalf("expected both unreachable pools to be unhealthy, got %+v", status)
+	}
+	if router.Reader(ctx) != primary {
+		t.Error("expected the reads to go to the primary while the replica is down")
+	}
+}
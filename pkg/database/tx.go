@@ -0,0 +1,7 @@
+package database
+
+// Tx is a transaction of any repository driver, *sql.Tx implements it.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
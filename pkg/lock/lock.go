@@ -0,0 +1,97 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Locker hands a key to a single holder across the replicas, the key is freed once the ttl expires.
+// A holder releases the key with the token of its Acquire, so a holder that outlived the ttl doesn't free the key of the next one.
+type Locker interface {
+	// Acquire returns false when another holder has the key already.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error)
+	// Release frees the key unless another holder has it by now.
+	Release(ctx context.Context, key string, token string) (err error)
+}
+
+// releaseScript deletes the key only while it holds the token of the caller.
+var releaseScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+
+type redisLocker struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisLocker is a constructor.
+func NewRedisLocker(client *redis.Client, keyPrefix string) Locker {
+	return &redisLocker{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (l *redisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error) {
+	if token, err = newToken(); err != nil {
+		return
+	}
+	acquired, err = l.client.SetNX(ctx, l.keyPrefix+key, token, ttl).Result()
+	return
+}
+
+func (l *redisLocker) Release(ctx context.Context, key string, token string) (err error) {
+	return releaseScript.Run(ctx, l.client, []string{l.keyPrefix + key}, token).Err()
+}
+
+type memoryHold struct {
+	token string
+	until time.Time
+}
+
+type memoryLocker struct {
+	mu   sync.Mutex
+	keys map[string]memoryHold
+}
+
+// NewMemoryLocker returns a locker of a single process, it is meant for the memory driver.
+func NewMemoryLocker() Locker {
+	return &memoryLocker{keys: make(map[string]memoryHold)}
+}
+
+func (l *memoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if hold, ok := l.keys[key]; ok && now.Before(hold.until) {
+		return "", false, nil
+	}
+	if token, err = newToken(); err != nil {
+		return
+	}
+	l.keys[key] = memoryHold{token: token, until: now.Add(ttl)}
+	return token, true, nil
+}
+
+func (l *memoryLocker) Release(ctx context.Context, key string, token string) (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if hold, ok := l.keys[key]; ok && hold.token == token {
+		delete(l.keys, key)
+	}
+	return
+}
+
+// newToken returns a random token of a holder.
+func newToken() (token string, err error) {
+	value := make([]byte, 16)
+	if _, err = rand.Read(value); err != nil {
+		return
+	}
+	return hex.EncodeToString(value), nil
+}
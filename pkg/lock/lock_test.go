@@ -0,0 +1,34 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app-api/pkg/lock"
+)
+
+func TestMemoryLockerReleasesItsOwnHold(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewMemoryLocker()
+
+	expired, acquired, _ := locker.Acquire(ctx, "job", time.Millisecond)
+	if !acquired {
+		t.Fatal("expected the key to be free")
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// the first holder outlived the ttl, its release must not free the key of the second one.
+	current, acquired, _ := locker.Acquire(ctx, "job", time.Minute)
+	if !acquired {
+		t.Fatal("expected the expired key to be acquired again")
+	}
+	locker.Release(ctx, "job", expired)
+	if _, acquired, _ := locker.Acquire(ctx, "job", time.Minute); acquired {
+		t.Error("expected the key to stay with the second holder")
+	}
+
+	locker.Release(ctx, "job", current)
+	if _, acquired, _ := locker.Acquire(ctx, "job", time.Minute); !acquired {
+		t.Error("expected the key to be freed by its holder")
+	}
+}
@@ -0,0 +1,163 @@
+package memstore
+
+import (
+	"database/sql"
+	"sync"
+	"todo-app-api/pkg/database"
+	"todo-app-api/pkg/exception"
+)
+
+// Table is a thread safe in-memory table that keeps the insertion order of its rows.
+// The write methods take an optional transaction, a *Tx undoes the write on rollback.
+type Table[K comparable, V any] struct {
+	mu     sync.RWMutex
+	keys   []K
+	rows   map[K]V
+	lastID int64
+}
+
+// NewTable is a constructor.
+func NewTable[K comparable, V any]() *Table[K, V] {
+	return &Table[K, V]{rows: make(map[K]V)}
+}
+
+// NextID returns the next value of the auto increment sequence of the table.
+func (t *Table[K, V]) NextID() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastID++
+	return t.lastID
+}
+
+// Insert adds a row, exception.ErrConflict is returned when the key exists
+// or when an existing row matches one of the unique predicates.
+func (t *Table[K, V]) Insert(tx database.Tx, key K, value V, unique ...func(existing V) bool) (err error) {
+	if err = checkTx(tx); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	if _, ok := t.rows[key]; ok {
+		t.mu.Unlock()
+		return exception.ErrConflict
+	}
+	for _, existing := range t.rows {
+		for _, conflicts := range unique {
+			if conflicts(existing) {
+				t.mu.Unlock()
+				return exception.ErrConflict
+			}
+		}
+	}
+	t.keys = append(t.keys, key)
+	t.rows[key] = value
+	t.mu.Unlock()
+
+	return t.undoWith(tx, func() { t.remove(key) })
+}
+
+// Update replaces a row, exception.ErrNotFound is returned when the key doesn't exist.
+func (t *Table[K, V]) Update(tx database.Tx, key K, value V) (err error) {
+	if err = checkTx(tx); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	previous, ok := t.rows[key]
+	if !ok {
+		t.mu.Unlock()
+		return exception.ErrNotFound
+	}
+	t.rows[key] = value
+	t.mu.Unlock()
+
+	return t.undoWith(tx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.rows[key]; ok {
+			t.rows[key] = previous
+		}
+	})
+}
+
+// Delete removes a row, exception.ErrNotFound is returned when the key doesn't exist.
+func (t *Table[K, V]) Delete(tx database.Tx, key K) (err error) {
+	if err = checkTx(tx); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	previous, ok := t.rows[key]
+	t.mu.Unlock()
+	if !ok {
+		return exception.ErrNotFound
+	}
+	t.remove(key)
+
+	return t.undoWith(tx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.rows[key]; !ok {
+			t.keys = append(t.keys, key)
+			t.rows[key] = previous
+		}
+	})
+}
+
+// Get returns the row of the key.
+func (t *Table[K, V]) Get(key K) (value V, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	value, ok = t.rows[key]
+	return
+}
+
+// List returns the rows that match the filter in insertion order, every row when the filter is nil.
+func (t *Table[K, V]) List(filter func(V) bool) (values []V) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	values = make([]V, 0, len(t.keys))
+	for _, key := range t.keys {
+		if value := t.rows[key]; filter == nil || filter(value) {
+			values = append(values, value)
+		}
+	}
+	return
+}
+
+func (t *Table[K, V]) remove(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.rows[key]; !ok {
+		return
+	}
+	delete(t.rows, key)
+	for i, k := range t.keys {
+		if k == key {
+			t.keys = append(t.keys[:i], t.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (t *Table[K, V]) undoWith(tx database.Tx, fn func()) error {
+	if memTx, ok := tx.(*Tx); ok {
+		return memTx.onRollback(fn)
+	}
+	return nil
+}
+
+func checkTx(tx database.Tx) error {
+	if memTx, ok := tx.(*Tx); ok {
+		memTx.mu.Lock()
+		defer memTx.mu.Unlock()
+		if memTx.done {
+			return sql.ErrTxDone
+		}
+	}
+	return nil
+}
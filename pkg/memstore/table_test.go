@@ -0,0 +1,57 @@
+package memstore_test
+
+import (
+	"database/sql"
+	"testing"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/memstore"
+)
+
+func TestTableRollback(t *testing.T) {
+	table := memstore.NewTable[int64, string]()
+	if err := table.Insert(nil, 1, "kept"); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := memstore.Begin()
+	table.Insert(tx, 2, "inserted")
+	table.Update(tx, 1, "updated")
+	if rows := table.List(nil); len(rows) != 2 || rows[0] != "updated" {
+		t.Fatalf("expected the writes to be visible, got %v", rows)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if rows := table.List(nil); len(rows) != 1 || rows[0] != "kept" {
+		t.Errorf("expected the writes to be undone, got %v", rows)
+	}
+	if err := table.Insert(tx, 3, "late"); err != sql.ErrTxDone {
+		t.Errorf("expected a finished transaction to be rejected, got %v", err)
+	}
+
+	tx = memstore.Begin()
+	table.Delete(tx, 1)
+	tx.Commit()
+	if _, ok := table.Get(1); ok || tx.Rollback() != sql.ErrTxDone {
+		t.Error("expected the delete to be committed")
+	}
+}
+
+func TestTableUnique(t *testing.T) {
+	table := memstore.NewTable[string, string]()
+	sameValue := func(value string) func(string) bool {
+		return func(existing string) bool { return existing == value }
+	}
+
+	table.Insert(nil, "a", "jane@example.com", sameValue("jane@example.com"))
+	if err := table.Insert(nil, "b", "jane@example.com", sameValue("jane@example.com")); err != exception.ErrConflict {
+		t.Errorf("expected a conflict on a unique value, got %v", err)
+	}
+	if err := table.Insert(nil, "a", "john@example.com"); err != exception.ErrConflict {
+		t.Errorf("expected a conflict on an existing key, got %v", err)
+	}
+	if err := table.Update(nil, "c", "x"); err != exception.ErrNotFound {
+		t.Errorf("expected a missing row, got %v", err)
+	}
+}
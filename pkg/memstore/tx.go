@@ -0,0 +1,60 @@
+package memstore
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// Tx is a fake transaction of the in-memory tables.
+// Writes are visible right away and a rollback undoes them in reverse order,
+// which is enough to exercise the commit and rollback paths of the usecases.
+type Tx struct {
+	mu   sync.Mutex
+	undo []func()
+	done bool
+}
+
+// Begin starts a transaction.
+func Begin() *Tx {
+	return &Tx{}
+}
+
+// Commit keeps the writes of the transaction.
+func (tx *Tx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done, tx.undo = true, nil
+	return nil
+}
+
+// Rollback undoes the writes of the transaction.
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	if tx.done {
+		tx.mu.Unlock()
+		return sql.ErrTxDone
+	}
+	undo := tx.undo
+	tx.done, tx.undo = true, nil
+	tx.mu.Unlock()
+
+	for i := len(undo) - 1; i >= 0; i-- {
+		undo[i]()
+	}
+	return nil
+}
+
+func (tx *Tx) onRollback(fn func()) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.undo = append(tx.undo, fn)
+	return nil
+}
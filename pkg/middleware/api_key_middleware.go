@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"todo-app-api/entity"
+	"todo-app-api/pkg/exception"
+	"todo-app-api/pkg/response"
+)
+
+// APIKeyVerifier verifies a raw api key.
+type APIKeyVerifier interface {
+	Verify(ctx context.Context, key string) (apiKey entity.APIKey, err error)
+}
+
+// APIKeyAuth is a concrete struct of api key verifier.
+// A request without an api key is handed over to the fallback middleware.
+type APIKeyAuth struct {
+	verifier APIKeyVerifier
+	fallback RouteMiddleware
+}
+
+// NewAPIKeyAuth is a constructor.
+func NewAPIKeyAuth(verifier APIKeyVerifier, fallback RouteMiddleware) RouteMiddleware {
+	return &APIKeyAuth{verifier, fallback}
+}
+
+// Verify will verify the api key of the X-API-Key header or of the bearer token.
+func (ak *APIKeyAuth) Verify(next http.HandlerFunc) http.HandlerFunc {
+	fallback := ak.fallback.Verify(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && key == "" {
+			key = strings.TrimSpace(bearer)
+		}
+		if key == "" {
+			fallback(w, r)
+			return
+		}
+
+		apiKey, err := ak.verifier.Verify(r.Context(), key)
+		if err != nil {
+			resp := response.NewErrorResponse(exception.ErrUnauthorized, http.StatusUnauthorized, nil, response.StatUnauthorized, errorMessage)
+			response.JSON(w, resp)
+			return
+		}
+
+		principal := entity.Principal{Subject: apiKey.UserUUID, Method: "api_key", APIKeyID: apiKey.ID}
+		next(w, r.WithContext(context.WithValue(r.Context(), entity.PrincipalContextKey{}, principal)))
+	})
+}
+
+// PrincipalFromContext returns the principal that is set by the auth middlewares.
+func PrincipalFromContext(ctx context.Context) (principal entity.Principal, ok bool) {
+	principal, ok = ctx.Value(entity.PrincipalContextKey{}).(entity.Principal)
+	return
+}
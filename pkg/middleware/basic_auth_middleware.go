@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
+	"todo-app-api/entity"
+
 	"todo-app-api/pkg/exception"
 	"todo-app-api/pkg/response"
 )
@@ -39,6 +42,8 @@ func (ba *BasicAuth) Verify(next http.HandlerFunc) http.HandlerFunc {
 			ba.respondUnauthorized(w)
 			return
 		}
-		next(w, r)
+
+		principal := entity.Principal{Subject: username, Method: "basic"}
+		next(w, r.WithContext(context.WithValue(r.Context(), entity.PrincipalContextKey{}, principal)))
 	})
 }
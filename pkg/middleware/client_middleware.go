@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"todo-app-api/entity"
 )
@@ -24,3 +27,33 @@ func ClientDeviceMiddleware(handler http.Handler) http.Handler {
 		handler.ServeHTTP(w, r)
 	})
 }
+
+// ClientKey identifies the caller of a request, its api key or else its client device. The basic auth principal is
+// shared by every client of the credentials, it only identifies a request without a device.
+// An empty key is returned outside of a request.
+func ClientKey(ctx context.Context) string {
+	principal, authenticated := PrincipalFromContext(ctx)
+	if authenticated && principal.APIKeyID > 0 {
+		return "api_key:" + strconv.FormatInt(principal.APIKeyID, 10)
+	}
+
+	clientDevice, ok := ctx.Value(entity.ClientContextKey{}).(entity.ClientDevice)
+	if !ok {
+		if authenticated && principal.Subject != "" {
+			return principal.Method + ":" + principal.Subject
+		}
+		return ""
+	}
+
+	address := clientDevice.XRealIP
+	if forwardedFor, _, _ := strings.Cut(clientDevice.XForwardedFor, ","); strings.TrimSpace(forwardedFor) != "" {
+		address = strings.TrimSpace(forwardedFor)
+	}
+	if address == "" {
+		address = clientDevice.RemoteAddress
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+	}
+	return "device:" + address + "|" + clientDevice.UserAgent
+}
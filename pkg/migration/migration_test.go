@@ -0,0 +1,79 @@
+package migration
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/000002_add_index.up.sql":      {Data: []byte("CREATE INDEX idx ON task (name);")},
+		"sql/000002_add_index.down.sql":    {Data: []byte("DROP INDEX idx ON task;")},
+		"sql/000001_create_task.up.sql":    {Data: []byte("CREATE TABLE task (id BIGINT);")},
+		"sql/000001_create_task.down.sql":  {Data: []byte("DROP TABLE task;")},
+		"sql/000003_missing_down.up.sql":   {Data: []byte("SELECT 1;")},
+		"other/000001_create_task.up.sql":  {Data: []byte("-")},
+		"other/000001_create_task.down.sq": {Data: []byte("-")},
+	}
+
+	if _, err := Load(fsys, "sql"); err == nil {
+		t.Fatal("expected an error on a migration without its down file")
+	}
+	if _, err := Load(fsys, "other"); err == nil {
+		t.Fatal("expected an error on an invalid file name")
+	}
+
+	delete(fsys, "sql/000003_missing_down.up.sql")
+	migrations, err := Load(fsys, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Name != "add_index" {
+		t.Errorf("unexpected migrations %+v", migrations)
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	files, err := fs.Sub(Files, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mysql, err := Load(files, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgres, err := Load(files, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every dialect ships the same versions.
+	if len(mysql) == 0 || len(mysql) != len(postgres) {
+		t.Fatalf("got %d mysql and %d postgres migrations", len(mysql), len(postgres))
+	}
+	for i := range mysql {
+		if mysql[i].Version != postgres[i].Version || mysql[i].Name != postgres[i].Name {
+			t.Errorf("migration %d_%s has no postgres counterpart", mysql[i].Version, mysql[i].Name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- a comment; with a semicolon
+CREATE TABLE task (name VARCHAR(255) DEFAULT 'a;b'); /* block; comment */
+INSERT INTO task (name) VALUES ('it\'s; fine');
+# another comment;
+UPDATE task SET name = "x;y"`
+
+	expected := []string{
+		"CREATE TABLE task (name VARCHAR(255) DEFAULT 'a;b')",
+		`INSERT INTO task (name) VALUES ('it\'s; fine')`,
+		`UPDATE task SET name = "x;y"`,
+	}
+	if statements := splitStatements(script); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("unexpected statements %q", statements)
+	}
+}
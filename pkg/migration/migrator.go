@@ -0,0 +1,341 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"todo-app-api/pkg/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrLocked is returned when another runner holds the migration lock past the timeout.
+	ErrLocked = errors.New("migrations are locked by another runner")
+	// ErrDirty is returned when a previous run failed in the middle of a migration.
+	ErrDirty = errors.New("database is dirty, fix the failed migration by hand and remove its row from the migrations table")
+)
+
+// Status is the state of a single migration.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+type record struct {
+	version   int64
+	name      string
+	dirty     bool
+	appliedAt time.Time
+}
+
+// Migrator applies the migrations and tracks them in the migrations table.
+// Runners are serialized with a named lock, so several instances can migrate at startup.
+type Migrator struct {
+	logger      *logrus.Logger
+	location    *time.Location
+	db          *sql.DB
+	dialect     database.Dialect
+	migrations  []Migration
+	tableName   string
+	lockTimeout time.Duration
+}
+
+// NewMigrator is a constructor.
+func NewMigrator(logger *logrus.Logger, location *time.Location, db *sql.DB, dialect database.Dialect, migrations []Migration, tableName string, lockTimeout time.Duration) *Migrator {
+	return &Migrator{
+		logger:      logger,
+		location:    location,
+		db:          db,
+		dialect:     dialect,
+		migrations:  migrations,
+		tableName:   tableName,
+		lockTimeout: lockTimeout,
+	}
+}
+
+// Up applies the pending migrations, every one of them when steps is below 1.
+func (m *Migrator) Up(ctx context.Context, steps int) (applied []Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, records map[int64]record) (err error) {
+		for _, migration := range m.migrations {
+			if steps > 0 && len(applied) == steps {
+				return
+			}
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+
+			if err = m.apply(ctx, conn, migration, migration.Up, true); err != nil {
+				return
+			}
+			applied = append(applied, migration)
+		}
+		return
+	})
+	return
+}
+
+// Down rolls back the latest applied migrations, one of them when steps is below 1.
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	if steps < 1 {
+		steps = 1
+	}
+
+	err = m.withLock(ctx, func(conn *sql.Conn, records map[int64]record) (err error) {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+
+			if err = m.apply(ctx, conn, migration, migration.Down, false); err != nil {
+				return
+			}
+			reverted = append(reverted, migration)
+		}
+		return
+	})
+	return
+}
+
+// Status returns the state of every known migration, and of the applied ones that are no longer known.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if err = m.ensureTable(ctx, conn); err != nil {
+		return
+	}
+
+	records, err := m.records(ctx, conn)
+	if err != nil {
+		return
+	}
+
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if r, ok := records[migration.Version]; ok {
+			appliedAt := r.appliedAt
+			status.Applied, status.Dirty, status.AppliedAt = true, r.dirty, &appliedAt
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, r := range records {
+		appliedAt := r.appliedAt
+		statuses = append(statuses, Status{Version: r.version, Name: r.name, Applied: true, Dirty: r.dirty, AppliedAt: &appliedAt})
+	}
+	return
+}
+
+// apply runs the script of the migration. The row is marked dirty until the script succeeds,
+// since MySQL commits every DDL statement implicitly and a failed script can't be rolled back.
+// PostgreSQL could roll it back, the scripts run the same way on both to keep them interchangeable.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, script string, up bool) (err error) {
+	logger := m.logger.WithContext(ctx).WithFields(logrus.Fields{"migration.version": migration.Version, "migration.name": migration.Name})
+
+	now := time.Now().In(m.location)
+	if up {
+		_, err = conn.ExecContext(ctx, m.dialect.Rebind(fmt.Sprintf(`INSERT INTO %s (version, name, dirty, applied_at) VALUES (?, ?, ?, ?)`, m.tableName)), migration.Version, migration.Name, true, now)
+	} else {
+		_, err = conn.ExecContext(ctx, m.dialect.Rebind(fmt.Sprintf(`UPDATE %s SET dirty = ? WHERE version = ?`, m.tableName)), true, migration.Version)
+	}
+	if err != nil {
+		return
+	}
+
+	for _, statement := range splitStatements(script) {
+		if _, err = conn.ExecContext(ctx, statement); err != nil {
+			logger.Error(statement, err)
+			return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	if up {
+		_, err = conn.ExecContext(ctx, m.dialect.Rebind(fmt.Sprintf(`UPDATE %s SET dirty = ?, applied_at = ? WHERE version = ?`, m.tableName)), false, time.Now().In(m.location), migration.Version)
+	} else {
+		_, err = conn.ExecContext(ctx, m.dialect.Rebind(fmt.Sprintf(`DELETE FROM %s WHERE version = ?`, m.tableName)), migration.Version)
+	}
+	if err != nil {
+		return
+	}
+
+	if up {
+		logger.Info("migration is applied")
+	} else {
+		logger.Info("migration is rolled back")
+	}
+	return
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, records map[int64]record) error) (err error) {
+	// the lock belongs to the session, so everything runs on a single connection.
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	release, err := m.lock(ctx, conn)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err := release(); err != nil {
+			m.logger.WithContext(ctx).Error(err)
+		}
+	}()
+
+	if err = m.ensureTable(ctx, conn); err != nil {
+		return
+	}
+
+	records, err := m.records(ctx, conn)
+	if err != nil {
+		return
+	}
+	for _, r := range records {
+		if r.dirty {
+			return fmt.Errorf("migration %d_%s: %w", r.version, r.name, ErrDirty)
+		}
+	}
+
+	return fn(conn, records)
+}
+
+// lock takes the named lock of the database, MySQL waits on GET_LOCK and PostgreSQL polls an advisory lock.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (release func() error, err error) {
+	lockName := m.tableName
+
+	if m.dialect == database.Postgres {
+		deadline := time.Now().Add(m.lockTimeout)
+		for {
+			var locked bool
+			if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext(current_database() || '.' || $1))`, lockName).Scan(&locked); err != nil {
+				return
+			}
+			if locked {
+				break
+			}
+			if time.Now().After(deadline) {
+				return nil, ErrLocked
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+		return func() error {
+			_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext(current_database() || '.' || $1))`, lockName)
+			return err
+		}, nil
+	}
+
+	var locked sql.NullInt64
+	if err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(CONCAT(DATABASE(), '.', ?), ?)`, lockName, int(m.lockTimeout.Seconds())).Scan(&locked); err != nil {
+		return
+	}
+	if locked.Int64 != 1 {
+		return nil, ErrLocked
+	}
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.', ?))`, lockName)
+		return err
+	}, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) (err error) {
+	appliedAt := "DATETIME"
+	if m.dialect == database.Postgres {
+		appliedAt = "TIMESTAMPTZ"
+	}
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		dirty BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_at %s NOT NULL
+	)`, m.tableName, appliedAt))
+	return
+}
+
+func (m *Migrator) records(ctx context.Context, conn *sql.Conn) (records map[int64]record, err error) {
+	q := fmt.Sprintf(`SELECT m.version, m.name, m.dirty, m.applied_at FROM %s m`, m.tableName)
+	rows, err := conn.QueryContext(ctx, q)
+	if err != nil {
+		m.logger.WithContext(ctx).Error(q, err)
+		return
+	}
+	defer rows.Close()
+
+	records = make(map[int64]record)
+	for rows.Next() {
+		var r record
+		if err = rows.Scan(&r.version, &r.name, &r.dirty, &r.appliedAt); err != nil {
+			m.logger.WithContext(ctx).Error(q, err)
+			return
+		}
+		records[r.version] = r
+	}
+
+	err = rows.Err()
+	return
+}
+
+// Create writes an empty up and down file of the next version into the directory,
+// or into each of its subdirectories when it holds a directory per dialect.
+func Create(dir, name string) (paths []string, err error) {
+	if !fileNamePattern.MatchString(fmt.Sprintf("1_%s.up.sql", name)) {
+		return nil, fmt.Errorf("invalid migration name %s, use lowercase letters, digits and underscores", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = []string{dir}
+	}
+
+	// the dialects share the versions, so the next one follows the latest of any of them.
+	version := int64(1)
+	for _, d := range dirs {
+		migrations, err := Load(os.DirFS(d), ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(migrations) > 0 && migrations[len(migrations)-1].Version >= version {
+			version = migrations[len(migrations)-1].Version + 1
+		}
+	}
+
+	for _, d := range dirs {
+		base := filepath.Join(d, fmt.Sprintf("%06d_%s", version, name))
+		if err = os.WriteFile(base+".up.sql", []byte("-- write the migration here\n"), 0o644); err != nil {
+			return
+		}
+		if err = os.WriteFile(base+".down.sql", []byte("-- revert the migration here\n"), 0o644); err != nil {
+			return
+		}
+		paths = append(paths, base+".up.sql", base+".down.sql")
+	}
+	return
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS task (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    status TINYINT NOT NULL DEFAULT 0,
+    attachment VARCHAR(1024) NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    KEY idx_task_name (name),
+    KEY idx_task_attachment (attachment(255))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
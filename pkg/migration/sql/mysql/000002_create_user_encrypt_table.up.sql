@@ -0,0 +1,9 @@
+-- name and email are stored encrypted, hence the wide columns.
+CREATE TABLE IF NOT EXISTS user_encrypt (
+    uuid CHAR(36) NOT NULL,
+    name VARCHAR(512) NOT NULL,
+    email VARCHAR(512) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (uuid),
+    UNIQUE KEY uq_user_encrypt_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
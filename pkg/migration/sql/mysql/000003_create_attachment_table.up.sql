@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS attachment (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    bucket VARCHAR(255) NOT NULL,
+    object_key VARCHAR(768) NOT NULL,
+    folder VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL,
+    signature VARCHAR(255) NULL,
+    uploaded_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_attachment_object_key (object_key),
+    KEY idx_attachment_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_key (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_uuid CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_api_key_prefix (prefix),
+    KEY idx_api_key_user_uuid (user_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
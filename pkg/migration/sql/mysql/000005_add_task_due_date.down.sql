@@ -0,0 +1,6 @@
+ALTER TABLE task
+    DROP KEY idx_task_remind_at,
+    DROP KEY idx_task_due_at,
+    DROP COLUMN reminded_at,
+    DROP COLUMN remind_at,
+    DROP COLUMN due_at;
@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN due_at DATETIME NULL,
+    ADD COLUMN remind_at DATETIME NULL,
+    ADD COLUMN reminded_at DATETIME NULL,
+    ADD KEY idx_task_due_at (due_at),
+    ADD KEY idx_task_remind_at (remind_at);
@@ -0,0 +1,6 @@
+ALTER TABLE task
+    DROP KEY idx_task_series_id,
+    DROP COLUMN occurrence,
+    DROP COLUMN series_start,
+    DROP COLUMN series_id,
+    DROP COLUMN recurrence;
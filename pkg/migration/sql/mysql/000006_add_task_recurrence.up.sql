@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN recurrence VARCHAR(255) NULL,
+    ADD COLUMN series_id BIGINT NULL,
+    ADD COLUMN series_start DATETIME NULL,
+    ADD COLUMN occurrence INT NOT NULL DEFAULT 0,
+    ADD KEY idx_task_series_id (series_id);
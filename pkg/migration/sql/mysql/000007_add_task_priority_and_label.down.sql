@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS task_label;
+DROP TABLE IF EXISTS label;
+ALTER TABLE task
+    DROP KEY idx_task_priority,
+    DROP COLUMN priority;
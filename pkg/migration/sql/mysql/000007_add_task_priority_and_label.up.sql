@@ -0,0 +1,22 @@
+ALTER TABLE task
+    ADD COLUMN priority TINYINT NOT NULL DEFAULT 0,
+    ADD KEY idx_task_priority (priority);
+
+CREATE TABLE IF NOT EXISTS label (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(64) NOT NULL,
+    color CHAR(7) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_label_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS task_label (
+    task_id BIGINT NOT NULL,
+    label_id BIGINT NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    KEY idx_task_label_label_id (label_id),
+    CONSTRAINT fk_task_label_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_label_label FOREIGN KEY (label_id) REFERENCES label (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS task_checklist_item;
+ALTER TABLE task
+    DROP FOREIGN KEY fk_task_parent,
+    DROP KEY idx_task_parent_id,
+    DROP COLUMN parent_id;
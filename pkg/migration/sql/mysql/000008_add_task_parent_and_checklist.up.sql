@@ -0,0 +1,17 @@
+ALTER TABLE task
+    ADD COLUMN parent_id BIGINT NULL,
+    ADD KEY idx_task_parent_id (parent_id),
+    ADD CONSTRAINT fk_task_parent FOREIGN KEY (parent_id) REFERENCES task (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS task_checklist_item (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    task_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    KEY idx_task_checklist_item_task_id (task_id, position),
+    CONSTRAINT fk_task_checklist_item_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS task_dependency (
+    task_id BIGINT NOT NULL,
+    blocker_id BIGINT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    KEY idx_task_dependency_blocker_id (blocker_id),
+    CONSTRAINT fk_task_dependency_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_dependency_blocker FOREIGN KEY (blocker_id) REFERENCES task (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+ALTER TABLE task
+    DROP FOREIGN KEY fk_task_project,
+    DROP KEY idx_task_project_id,
+    DROP COLUMN project_id;
+DROP TABLE IF EXISTS project;
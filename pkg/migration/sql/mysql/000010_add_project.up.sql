@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS project (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(128) NOT NULL,
+    description TEXT NULL,
+    archived_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_project_name (name),
+    KEY idx_project_archived_at (archived_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE task
+    ADD COLUMN project_id BIGINT NULL,
+    ADD KEY idx_task_project_id (project_id, status),
+    ADD CONSTRAINT fk_task_project FOREIGN KEY (project_id) REFERENCES project (id) ON DELETE SET NULL;
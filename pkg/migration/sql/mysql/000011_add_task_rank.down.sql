@@ -0,0 +1,3 @@
+ALTER TABLE task
+    DROP KEY idx_task_rank_key,
+    DROP COLUMN rank_key;
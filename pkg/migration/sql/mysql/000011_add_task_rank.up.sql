@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN rank_key VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+    ADD KEY idx_task_rank_key (rank_key);
+
+-- the existing tasks keep the order of their ids, a rank never ends with 0.
+UPDATE task SET rank_key = CONCAT(LPAD(id, 12, '0'), '1');
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS task_activity (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    task_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    remote_address VARCHAR(255) NOT NULL,
+    x_forwarded_for VARCHAR(1024) NOT NULL,
+    x_real_ip VARCHAR(255) NOT NULL,
+    user_agent VARCHAR(1024) NOT NULL,
+    changes TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_task_activity_task_id (task_id, id),
+    CONSTRAINT fk_task_activity_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_watcher;
+DROP TABLE IF EXISTS task_assignee;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS task_assignee (
+    task_id BIGINT NOT NULL,
+    user_uuid CHAR(36) NOT NULL,
+    PRIMARY KEY (task_id, user_uuid),
+    KEY idx_task_assignee_user_uuid (user_uuid),
+    CONSTRAINT fk_task_assignee_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS task_watcher (
+    task_id BIGINT NOT NULL,
+    user_uuid CHAR(36) NOT NULL,
+    PRIMARY KEY (task_id, user_uuid),
+    KEY idx_task_watcher_user_uuid (user_uuid),
+    CONSTRAINT fk_task_watcher_task FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
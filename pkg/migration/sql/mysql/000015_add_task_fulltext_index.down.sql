@@ -0,0 +1 @@
+ALTER TABLE task DROP INDEX idx_task_fulltext;
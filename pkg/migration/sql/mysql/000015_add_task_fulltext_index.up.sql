@@ -0,0 +1 @@
+ALTER TABLE task ADD FULLTEXT INDEX idx_task_fulltext (name, description);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS task_view (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    owner VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    query_string TEXT NOT NULL,
+    sort VARCHAR(16) NOT NULL DEFAULT '',
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    PRIMARY KEY (id),
+    KEY idx_task_view_owner (owner),
+    KEY idx_task_view_shared (shared)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
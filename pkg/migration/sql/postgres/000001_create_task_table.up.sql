@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    status SMALLINT NOT NULL DEFAULT 0,
+    attachment VARCHAR(1024) NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_name ON task (name);
+CREATE INDEX IF NOT EXISTS idx_task_attachment ON task (attachment);
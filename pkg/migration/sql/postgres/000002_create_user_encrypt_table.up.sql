@@ -0,0 +1,8 @@
+-- name and email are stored encrypted, hence the wide columns.
+CREATE TABLE IF NOT EXISTS user_encrypt (
+    uuid CHAR(36) NOT NULL PRIMARY KEY,
+    name VARCHAR(512) NOT NULL,
+    email VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT uq_user_encrypt_email UNIQUE (email)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS attachment (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    bucket VARCHAR(255) NOT NULL,
+    object_key VARCHAR(768) NOT NULL,
+    folder VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(32) NOT NULL,
+    signature VARCHAR(255) NULL,
+    uploaded_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_attachment_object_key UNIQUE (object_key)
+);
+CREATE INDEX IF NOT EXISTS idx_attachment_status ON attachment (status);
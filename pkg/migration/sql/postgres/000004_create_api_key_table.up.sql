@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_key (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_uuid CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT uq_api_key_prefix UNIQUE (prefix)
+);
+CREATE INDEX IF NOT EXISTS idx_api_key_user_uuid ON api_key (user_uuid);
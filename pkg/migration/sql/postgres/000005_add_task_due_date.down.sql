@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_remind_at;
+DROP INDEX IF EXISTS idx_task_due_at;
+ALTER TABLE task
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS remind_at,
+    DROP COLUMN IF EXISTS due_at;
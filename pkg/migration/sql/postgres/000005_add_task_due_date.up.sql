@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS remind_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ NULL;
+CREATE INDEX IF NOT EXISTS idx_task_due_at ON task (due_at);
+CREATE INDEX IF NOT EXISTS idx_task_remind_at ON task (remind_at) WHERE reminded_at IS NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_series_id;
+ALTER TABLE task
+    DROP COLUMN IF EXISTS occurrence,
+    DROP COLUMN IF EXISTS series_start,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN IF NOT EXISTS recurrence VARCHAR(255) NULL,
+    ADD COLUMN IF NOT EXISTS series_id BIGINT NULL,
+    ADD COLUMN IF NOT EXISTS series_start TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_task_series_id ON task (series_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS task_label;
+DROP TABLE IF EXISTS label;
+DROP INDEX IF EXISTS idx_task_priority;
+ALTER TABLE task DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,18 @@
+ALTER TABLE task ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_task_priority ON task (priority);
+
+CREATE TABLE IF NOT EXISTS label (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    color CHAR(7) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_label_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS task_label (
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    label_id BIGINT NOT NULL REFERENCES label (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_label_label_id ON task_label (label_id);
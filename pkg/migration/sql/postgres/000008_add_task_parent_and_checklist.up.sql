@@ -0,0 +1,13 @@
+ALTER TABLE task ADD COLUMN IF NOT EXISTS parent_id BIGINT NULL REFERENCES task (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_task_parent_id ON task (parent_id);
+
+CREATE TABLE IF NOT EXISTS task_checklist_item (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_checklist_item_task_id ON task_checklist_item (task_id, position);
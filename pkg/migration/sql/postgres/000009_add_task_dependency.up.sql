@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS task_dependency (
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    blocker_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (task_id, blocker_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_dependency_blocker_id ON task_dependency (blocker_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_project_id;
+ALTER TABLE task DROP COLUMN IF EXISTS project_id;
+DROP TABLE IF EXISTS project;
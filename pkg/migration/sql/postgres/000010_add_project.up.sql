@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS project (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    description TEXT NULL,
+    archived_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_project_name UNIQUE (name)
+);
+CREATE INDEX IF NOT EXISTS idx_project_archived_at ON project (archived_at);
+
+ALTER TABLE task ADD COLUMN IF NOT EXISTS project_id BIGINT NULL REFERENCES project (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_task_project_id ON task (project_id, status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_rank_key;
+ALTER TABLE task DROP COLUMN IF EXISTS rank_key;
@@ -0,0 +1,5 @@
+ALTER TABLE task ADD COLUMN IF NOT EXISTS rank_key VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_task_rank_key ON task (rank_key);
+
+-- the existing tasks keep the order of their ids, a rank never ends with 0.
+UPDATE task SET rank_key = LPAD(id::text, 12, '0') || '1';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS task_activity (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    remote_address VARCHAR(255) NOT NULL,
+    x_forwarded_for VARCHAR(1024) NOT NULL,
+    x_real_ip VARCHAR(255) NOT NULL,
+    user_agent VARCHAR(1024) NOT NULL,
+    changes TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_activity_task_id ON task_activity (task_id, id);
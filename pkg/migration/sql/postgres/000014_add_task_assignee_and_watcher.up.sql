@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS task_assignee (
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    user_uuid CHAR(36) NOT NULL,
+    PRIMARY KEY (task_id, user_uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_task_assignee_user_uuid ON task_assignee (user_uuid);
+
+CREATE TABLE IF NOT EXISTS task_watcher (
+    task_id BIGINT NOT NULL REFERENCES task (id) ON DELETE CASCADE,
+    user_uuid CHAR(36) NOT NULL,
+    PRIMARY KEY (task_id, user_uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_task_watcher_user_uuid ON task_watcher (user_uuid);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_task_fulltext ON task USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, '')));
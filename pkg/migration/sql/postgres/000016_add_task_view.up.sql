@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS task_view (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    query_string TEXT NOT NULL,
+    sort VARCHAR(16) NOT NULL DEFAULT '',
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_view_owner ON task_view (owner);
+CREATE INDEX IF NOT EXISTS idx_task_view_shared ON task_view (shared);
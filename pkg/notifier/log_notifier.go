@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type logNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier returns a notifier that writes the notifications into the log.
+func NewLogNotifier(logger *logrus.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+// Notify implements Notifier.
+func (n *logNotifier) Notify(ctx context.Context, notification Notification) (err error) {
+	fields := logrus.Fields{
+		"notification.recipient": notification.Recipient,
+		"notification.subject":   notification.Subject,
+	}
+	for key, value := range notification.Data {
+		fields["notification.data."+key] = value
+	}
+
+	n.logger.WithContext(ctx).WithFields(fields).Info(notification.Message)
+	return
+}
@@ -0,0 +1,16 @@
+package notifier
+
+import "context"
+
+// Notification is a message addressed to a single recipient.
+type Notification struct {
+	Recipient string
+	Subject   string
+	Message   string
+	Data      map[string]string
+}
+
+// Notifier is an abstraction of a notification channel.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) (err error)
+}
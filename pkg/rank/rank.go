@@ -0,0 +1,121 @@
+// Package rank implements lexicographic fractional indexing: ranks are strings that sort by byte order,
+// and there is always a rank between two others, so moving an item rewrites its rank only.
+//
+// A rank is made of the digits 0-9 and a-z and never ends with 0, which keeps room below every rank.
+// The digits sort the same way in binary and in case insensitive collations.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var (
+	// ErrInvalid is returned for a rank with a digit out of the alphabet or a trailing 0.
+	ErrInvalid = errors.New("invalid rank")
+	// ErrOrder is returned when the lower rank isn't below the upper rank.
+	ErrOrder = errors.New("the lower rank must sort before the upper rank")
+)
+
+// Between returns a rank that sorts after lower and before upper, an empty lower is the start
+// of the list and an empty upper is its end. The rank is as short as possible.
+func Between(lower, upper string) (string, error) {
+	if err := Validate(lower); err != nil {
+		return "", err
+	}
+	if err := Validate(upper); err != nil {
+		return "", err
+	}
+	if upper != "" && lower >= upper {
+		return "", ErrOrder
+	}
+	return midpoint(lower, upper), nil
+}
+
+// Validate returns ErrInvalid for a rank that Between can't work with, the empty rank is valid.
+func Validate(rank string) error {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(digits, rank[i]) < 0 {
+			return ErrInvalid
+		}
+	}
+	if strings.HasSuffix(rank, digits[:1]) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// Spread returns n ranks in order that are spaced evenly and have the same length at most,
+// they replace the ranks of a list whose ranks grew too long.
+func Spread(n int) []string {
+	length, capacity := 1, base
+	for capacity <= n*2 {
+		length++
+		capacity *= base
+	}
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		ranks[i] = strings.TrimRight(encode((i+1)*capacity/(n+1), length), digits[:1])
+	}
+	return ranks
+}
+
+// midpoint returns the rank between lower and upper, an empty upper is past every rank.
+func midpoint(lower, upper string) string {
+	// the common prefix is kept, a missing digit of lower counts as 0.
+	if upper != "" {
+		n := 0
+		for n < len(upper) && digitAt(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			if n > len(lower) {
+				return upper[:n] + midpoint("", upper[n:])
+			}
+			return upper[:n] + midpoint(lower[n:], upper[n:])
+		}
+	}
+
+	low := 0
+	if lower != "" {
+		low = strings.IndexByte(digits, lower[0])
+	}
+	high := base
+	if upper != "" {
+		high = strings.IndexByte(digits, upper[0])
+	}
+	if high-low > 1 {
+		return string(digits[(low+high+1)/2])
+	}
+
+	// the first digits are consecutive, the first digit of a longer upper sorts before it.
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+	rest := ""
+	if len(lower) > 1 {
+		rest = lower[1:]
+	}
+	return string(digits[low]) + midpoint(rest, "")
+}
+
+func digitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return digits[0]
+}
+
+func encode(value int, length int) string {
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		encoded[i] = digits[value%base]
+		value /= base
+	}
+	return string(encoded)
+}
@@ -0,0 +1,78 @@
+package rank_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"todo-app-api/pkg/rank"
+)
+
+func TestBetween(t *testing.T) {
+	cases := []struct{ lower, upper, expected string }{
+		{"", "", "i"},
+		{"i", "", "r"},
+		{"", "i", "9"},
+		{"z", "", "zi"},
+		{"", "1", "0i"},
+		{"a", "b", "ai"},
+		{"a", "a05", "a03"},
+		{"a1", "b", "aj"},
+	}
+	for _, c := range cases {
+		between, err := rank.Between(c.lower, c.upper)
+		if err != nil || between != c.expected {
+			t.Errorf("between %q and %q: expected %q, got %q %v", c.lower, c.upper, c.expected, between, err)
+		}
+	}
+
+	if _, err := rank.Between("b", "a"); err != rank.ErrOrder {
+		t.Errorf("expected a wrong order, got %v", err)
+	}
+	if _, err := rank.Between("a0", ""); err != rank.ErrInvalid {
+		t.Errorf("expected a trailing 0 to be invalid, got %v", err)
+	}
+	if _, err := rank.Between("A", ""); err != rank.ErrInvalid {
+		t.Errorf("expected an upper case digit to be invalid, got %v", err)
+	}
+}
+
+func TestBetweenKeepsTheOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 2000; i++ {
+		position := random.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if position > 0 {
+			lower = ranks[position-1]
+		}
+		if position < len(ranks) {
+			upper = ranks[position]
+		}
+
+		between, err := rank.Between(lower, upper)
+		if err != nil {
+			t.Fatalf("between %q and %q: %v", lower, upper, err)
+		}
+		if between <= lower || (upper != "" && between >= upper) || rank.Validate(between) != nil {
+			t.Fatalf("between %q and %q: got %q", lower, upper, between)
+		}
+		ranks = append(ranks[:position], append([]string{between}, ranks[position:]...)...)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 17, 36, 1000} {
+		ranks := rank.Spread(n)
+		if len(ranks) != n || !sort.StringsAreSorted(ranks) {
+			t.Errorf("%d: unexpected ranks %v", n, ranks)
+		}
+		for i, r := range ranks {
+			if r == "" || rank.Validate(r) != nil || (i > 0 && ranks[i-1] == r) {
+				t.Errorf("%d: invalid rank %q", n, r)
+			}
+		}
+	}
+	if ranks := rank.Spread(1000); len(ranks[len(ranks)-1]) > 3 {
+		t.Errorf("expected short ranks, got %q", ranks[len(ranks)-1])
+	}
+}
@@ -2,7 +2,7 @@ package response
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 )
 
 // BuildErrorFromResponse wrap the error that contains error, and translate to error interface with informatif description.
@@ -19,5 +19,5 @@ func BuildErrorFromResponse(r Response) error {
 	}
 
 	errMessageBuff, _ := json.Marshal(errMessage)
-	return fmt.Errorf(string(errMessageBuff))
+	return errors.New(string(errMessageBuff))
 }
@@ -0,0 +1,319 @@
+// Package rrule implements the subset of the RFC 5545 recurrence rules used by the tasks:
+// DAILY, WEEKLY and MONTHLY with INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL. The weeks start on monday.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned for a rule that can't be parsed or isn't supported.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// maxPeriods bounds the search of an occurrence, so a rule that never matches again ends.
+const maxPeriods = 10000
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// Weekday is a BYDAY value, N is the ordinal of the day within the month, zero for every such day.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// Parse parses a rule like FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10, the RRULE: prefix is optional.
+// An UNTIL without a zone is read in the location.
+func Parse(value string, location *time.Location) (rule Rule, err error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return rule, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule.Interval = 1
+	for _, part := range strings.Split(value, ";") {
+		key, raw, ok := strings.Cut(part, "=")
+		if !ok || raw == "" {
+			return rule, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(raw))
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				return rule, fmt.Errorf("%w: unsupported frequency %s", ErrInvalidRule, raw)
+			}
+		case "INTERVAL":
+			if rule.Interval, err = strconv.Atoi(raw); err != nil || rule.Interval < 1 {
+				return rule, fmt.Errorf("%w: invalid interval %s", ErrInvalidRule, raw)
+			}
+		case "COUNT":
+			if rule.Count, err = strconv.Atoi(raw); err != nil || rule.Count < 1 {
+				return rule, fmt.Errorf("%w: invalid count %s", ErrInvalidRule, raw)
+			}
+		case "UNTIL":
+			until, err := parseUntil(raw, location)
+			if err != nil {
+				return rule, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(raw), ",") {
+				weekday, err := parseWeekday(day)
+				if err != nil {
+					return rule, err
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(raw, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return rule, fmt.Errorf("%w: invalid month day %s", ErrInvalidRule, day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		case "WKST":
+			if strings.ToUpper(raw) != "MO" {
+				return rule, fmt.Errorf("%w: the weeks start on monday", ErrInvalidRule)
+			}
+		default:
+			return rule, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return rule, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && rule.Until != nil:
+		return rule, fmt.Errorf("%w: COUNT and UNTIL are exclusive", ErrInvalidRule)
+	case rule.Freq != Monthly && len(rule.ByMonthDay) > 0:
+		return rule, fmt.Errorf("%w: BYMONTHDAY is only supported by MONTHLY", ErrInvalidRule)
+	}
+	for _, weekday := range rule.ByDay {
+		if weekday.N != 0 && rule.Freq != Monthly {
+			return rule, fmt.Errorf("%w: an ordinal BYDAY is only supported by MONTHLY", ErrInvalidRule)
+		}
+	}
+	return
+}
+
+func parseUntil(raw string, location *time.Location) (until time.Time, err error) {
+	if until, err = time.Parse("20060102T150405Z", raw); err == nil {
+		return
+	}
+	if until, err = time.ParseInLocation("20060102T150405", raw, location); err == nil {
+		return
+	}
+	if until, err = time.ParseInLocation("20060102", raw, location); err == nil {
+		// a date includes the whole day.
+		return until.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return until, fmt.Errorf("%w: invalid until %s", ErrInvalidRule, raw)
+}
+
+func parseWeekday(raw string) (weekday Weekday, err error) {
+	day, ok := weekdays[raw[max(len(raw)-2, 0):]]
+	if !ok {
+		return weekday, fmt.Errorf("%w: invalid day %s", ErrInvalidRule, raw)
+	}
+	weekday.Day = day
+
+	if ordinal := raw[:len(raw)-2]; ordinal != "" {
+		if weekday.N, err = strconv.Atoi(ordinal); err != nil || weekday.N == 0 || weekday.N < -5 || weekday.N > 5 {
+			return weekday, fmt.Errorf("%w: invalid day %s", ErrInvalidRule, raw)
+		}
+	}
+	return weekday, nil
+}
+
+// String returns the rule in its canonical form.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			days[i] = strings.ToUpper(weekday.Day.String()[:2])
+			if weekday.N != 0 {
+				days[i] = strconv.Itoa(weekday.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Nth returns the nth occurrence of the series that starts at start, the first occurrence is start itself.
+// The occurrences keep the wall clock of start in its location, ok is false past the end of the series.
+func (r Rule) Nth(start time.Time, n int) (occurrence time.Time, ok bool) {
+	if n < 1 || (r.Count > 0 && n > r.Count) {
+		return
+	}
+
+	count := 0
+	r.each(start, func(candidate time.Time) bool {
+		if r.Until != nil && candidate.After(*r.Until) {
+			return false
+		}
+		count++
+		if count == n {
+			occurrence, ok = candidate, true
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// each calls fn with start, then with the occurrences after it in order, until fn returns false.
+func (r Rule) each(start time.Time, fn func(candidate time.Time) bool) {
+	if !fn(start) {
+		return
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.candidates(start, period) {
+			if candidate.After(start) && !fn(candidate) {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrences of the nth period of the rule.
+func (r Rule) candidates(start time.Time, period int) (candidates []time.Time) {
+	year, month, day := start.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		candidate := at(year, month, day+period*r.Interval)
+		if len(r.ByDay) == 0 || r.hasWeekday(candidate.Weekday()) {
+			candidates = append(candidates, candidate)
+		}
+	case Weekly:
+		monday := day - (int(start.Weekday())+6)%7 + period*r.Interval*7
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(year, month, monday+(int(start.Weekday())+6)%7)}
+		}
+		for offset := 0; offset < 7; offset++ {
+			if candidate := at(year, month, monday+offset); r.hasWeekday(candidate.Weekday()) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case Monthly:
+		first := time.Date(year, month+time.Month(period*r.Interval), 1, 0, 0, 0, 0, start.Location())
+		days := r.monthDays(first.Year(), first.Month(), day)
+		for _, monthDay := range days {
+			candidates = append(candidates, at(first.Year(), first.Month(), monthDay))
+		}
+	}
+	return
+}
+
+// monthDays returns the sorted days of the month that match the rule, the day of start by default.
+// BYDAY limits BYMONTHDAY when both are set, and a day the month doesn't have, like the 31st of april, is skipped.
+func (r Rule) monthDays(year int, month time.Month, startDay int) (days []int) {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	byMonthDay := make(map[int]bool)
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay < 0 {
+			monthDay = daysInMonth + monthDay + 1
+		}
+		if monthDay >= 1 && monthDay <= daysInMonth {
+			byMonthDay[monthDay] = true
+		}
+	}
+
+	byDay := make(map[int]bool)
+	for _, weekday := range r.ByDay {
+		var sameDays []int
+		for day := 1; day <= daysInMonth; day++ {
+			if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == weekday.Day {
+				sameDays = append(sameDays, day)
+			}
+		}
+		switch {
+		case weekday.N == 0:
+			for _, day := range sameDays {
+				byDay[day] = true
+			}
+		case weekday.N > 0 && weekday.N <= len(sameDays):
+			byDay[sameDays[weekday.N-1]] = true
+		case weekday.N < 0 && -weekday.N <= len(sameDays):
+			byDay[sameDays[len(sameDays)+weekday.N]] = true
+		}
+	}
+
+	for day := 1; day <= daysInMonth; day++ {
+		matched := day == startDay
+		switch {
+		case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+			matched = byMonthDay[day] && byDay[day]
+		case len(r.ByMonthDay) > 0:
+			matched = byMonthDay[day]
+		case len(r.ByDay) > 0:
+			matched = byDay[day]
+		}
+		if matched {
+			days = append(days, day)
+		}
+	}
+	return
+}
+
+func (r Rule) hasWeekday(day time.Weekday) bool {
+	for _, weekday := range r.ByDay {
+		if weekday.Day == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package rrule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-app-api/pkg/rrule"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"FREQ=DAILY":                            "FREQ=DAILY",
+		"RRULE:freq=weekly;byday=mo,we;count=4": "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+		"FREQ=MONTHLY;BYDAY=-1FR;INTERVAL=2":    "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR",
+		"FREQ=MONTHLY;UNTIL=20240131T170000Z":   "FREQ=MONTHLY;UNTIL=20240131T170000Z",
+	}
+	for value, expected := range cases {
+		rule, err := rrule.Parse(value, time.UTC)
+		if err != nil {
+			t.Errorf("%s: %v", value, err)
+			continue
+		}
+		if rule.String() != expected {
+			t.Errorf("%s: expected %s, got %s", value, expected, rule)
+		}
+	}
+
+	for _, value := range []string{"", "FREQ=YEARLY", "INTERVAL=2", "FREQ=DAILY;COUNT=2;UNTIL=20240101", "FREQ=WEEKLY;BYDAY=1MO", "FREQ=DAILY;BYDAY=XX", "FREQ=DAILY;BYHOUR=9"} {
+		if _, err := rrule.Parse(value, time.UTC); !errors.Is(err, rrule.ErrInvalidRule) {
+			t.Errorf("%q: expected an invalid rule, got %v", value, err)
+		}
+	}
+}
+
+func TestNth(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	newYork, _ := time.LoadLocation("America/New_York")
+	date := func(location *time.Location, year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, location)
+	}
+
+	cases := []struct {
+		rule     string
+		start    time.Time
+		expected []time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=2;COUNT=3", date(jakarta, 2024, 1, 30, 9), []time.Time{date(jakarta, 2024, 1, 30, 9), date(jakarta, 2024, 2, 1, 9), date(jakarta, 2024, 2, 3, 9)}},
+		// monday, wednesday and friday from a wednesday.
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", date(jakarta, 2024, 5, 1, 8), []time.Time{date(jakarta, 2024, 5, 1, 8), date(jakarta, 2024, 5, 3, 8), date(jakarta, 2024, 5, 6, 8), date(jakarta, 2024, 5, 8, 8)}},
+		{"FREQ=WEEKLY;INTERVAL=2", date(jakarta, 2024, 5, 1, 8), []time.Time{date(jakarta, 2024, 5, 1, 8), date(jakarta, 2024, 5, 15, 8)}},
+		// the 31st is skipped by the months without it.
+		{"FREQ=MONTHLY", date(jakarta, 2024, 1, 31, 8), []time.Time{date(jakarta, 2024, 1, 31, 8), date(jakarta, 2024, 3, 31, 8), date(jakarta, 2024, 5, 31, 8)}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", date(jakarta, 2024, 1, 26, 8), []time.Time{date(jakarta, 2024, 1, 26, 8), date(jakarta, 2024, 2, 23, 8), date(jakarta, 2024, 3, 29, 8)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", date(jakarta, 2024, 2, 1, 8), []time.Time{date(jakarta, 2024, 2, 1, 8), date(jakarta, 2024, 2, 29, 8), date(jakarta, 2024, 3, 1, 8)}},
+		// the wall clock is kept across the daylight saving change.
+		{"FREQ=DAILY", date(newYork, 2024, 3, 9, 9), []time.Time{date(newYork, 2024, 3, 9, 9), date(newYork, 2024, 3, 10, 9), date(newYork, 2024, 3, 11, 9)}},
+		{"FREQ=DAILY;UNTIL=20240102", date(jakarta, 2024, 1, 1, 9), []time.Time{date(jakarta, 2024, 1, 1, 9), date(jakarta, 2024, 1, 2, 9)}},
+	}
+
+	for _, c := range cases {
+		rule, err := rrule.Parse(c.rule, c.start.Location())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, expected := range c.expected {
+			if occurrence, ok := rule.Nth(c.start, i+1); !ok || !occurrence.Equal(expected) || occurrence.Hour() != expected.Hour() {
+				t.Errorf("%s: expected occurrence %d at %s, got %s %v", c.rule, i+1, expected, occurrence, ok)
+			}
+		}
+		if rule.Count > 0 || rule.Until != nil {
+			if occurrence, ok := rule.Nth(c.start, len(c.expected)+1); ok {
+				t.Errorf("%s: expected the series to end, got %s", c.rule, occurrence)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const clamdChunkSize = 32 << 10
+
+type clamdScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner returns a scanner that streams the file to clamd with the INSTREAM command.
+// See https://linux.die.net/man/8/clamd for the protocol.
+func NewClamdScanner(address string, timeout time.Duration) Scanner {
+	return &clamdScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan implements Scanner.
+func (s *clamdScanner) Scan(ctx context.Context, file io.Reader) (result Result, err error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if s.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := file.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err = conn.Write(size); err != nil {
+				return
+			}
+			if _, err = conn.Write(chunk[:n]); err != nil {
+				return
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			err = readErr
+			return
+		}
+	}
+
+	// a zero length chunk terminates the stream.
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err = conn.Write(size); err != nil {
+		return
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply parses replies such as "stream: OK" or "stream: Eicar-Signature FOUND".
+func parseClamdReply(reply string) (result Result, err error) {
+	verdict := strings.TrimSpace(reply[strings.Index(reply, ":")+1:])
+	switch {
+	case verdict == "OK":
+		return
+	case strings.HasSuffix(verdict, " FOUND"):
+		result.Infected = true
+		result.Signature = strings.TrimSuffix(verdict, " FOUND")
+		return
+	}
+
+	err = fmt.Errorf("clamd: unexpected reply '%s'", reply)
+	return
+}
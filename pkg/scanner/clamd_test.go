@@ -0,0 +1,100 @@
+package scanner_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"todo-app-api/pkg/scanner"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd accepts INSTREAM commands and reports the EICAR test string as infected.
+func fakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				command := make([]byte, len("zINSTREAM\x00"))
+				if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var stream bytes.Buffer
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(conn, size); err != nil {
+						return
+					}
+					length := binary.BigEndian.Uint32(size)
+					if length == 0 {
+						break
+					}
+					if _, err := io.CopyN(&stream, conn, int64(length)); err != nil {
+						return
+					}
+				}
+
+				if strings.Contains(stream.String(), eicar) {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				conn.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamdScanner(t *testing.T) {
+	clamd := scanner.NewClamdScanner(fakeClamd(t), time.Second*5)
+
+	result, err := clamd.Scan(context.Background(), strings.NewReader(eicar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Errorf("expected the eicar file to be infected, got %+v", result)
+	}
+
+	// larger than a single chunk, to make sure the stream is framed properly.
+	clean := bytes.Repeat([]byte("a"), 100<<10)
+	result, err = clamd.Scan(context.Background(), bytes.NewReader(clean))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Infected {
+		t.Errorf("expected the file to be clean, got %+v", result)
+	}
+}
+
+func TestClamdScannerUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := scanner.NewClamdScanner(address, time.Second).Scan(context.Background(), strings.NewReader("file")); err == nil {
+		t.Error("expected an error when clamd is not reachable")
+	}
+}
@@ -0,0 +1,18 @@
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+type noopScanner struct{}
+
+// NewNoopScanner returns a scanner that considers every file clean.
+func NewNoopScanner() Scanner {
+	return noopScanner{}
+}
+
+// Scan implements Scanner.
+func (noopScanner) Scan(ctx context.Context, file io.Reader) (result Result, err error) {
+	return
+}
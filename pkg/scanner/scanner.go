@@ -0,0 +1,17 @@
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+// Result is the verdict of a scan.
+type Result struct {
+	Infected  bool
+	Signature string
+}
+
+// Scanner is an abstraction of a malware scanner.
+type Scanner interface {
+	Scan(ctx context.Context, file io.Reader) (result Result, err error)
+}
@@ -2,12 +2,17 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
 	gcstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
+// gcsMaxComposeSources is the maximum number of objects GCS composes in a single request.
+const gcsMaxComposeSources = 32
+
 type gcsAdapter struct {
 	gcpAccessID                   string
 	gcpPrivateKey                 string
@@ -25,11 +30,19 @@ func NewGCSAdapter(client *gcstorage.Client, accessID, privateKey string) Storag
 }
 
 func (gcs *gcsAdapter) PutObject(ctx context.Context, bucketName string, filepath string, file io.Reader, contentType string, meta map[string]string) (err error) {
+	// cancelling the writer's context is the only way to abort the upload without finalizing a partial object.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	w := gcs.client.Bucket(bucketName).Object(filepath).NewWriter(ctx)
 	w.ContentType = contentType
 	w.Metadata = meta
 
-	io.Copy(w, file)
+	if _, err = io.Copy(w, file); err != nil {
+		cancel()
+		w.Close()
+		return
+	}
 
 	return w.Close()
 }
@@ -45,3 +58,78 @@ func (gcs *gcsAdapter) SignURL(ctx context.Context, method, bucketName, filepath
 
 	return
 }
+
+func (gcs *gcsAdapter) DeleteObject(ctx context.Context, bucketName string, filepath string) (err error) {
+	err = gcs.client.Bucket(bucketName).Object(filepath).Delete(ctx)
+	if err == gcstorage.ErrObjectNotExist {
+		err = nil
+	}
+
+	return
+}
+
+func (gcs *gcsAdapter) GetObject(ctx context.Context, bucketName string, filepath string) (file io.ReadCloser, err error) {
+	return gcs.client.Bucket(bucketName).Object(filepath).NewReader(ctx)
+}
+
+// ComposeObject concatenates the sources into a single object.
+// More than 32 sources are composed into intermediate objects first, which are deleted afterwards.
+func (gcs *gcsAdapter) ComposeObject(ctx context.Context, bucketName string, filepath string, sources []string, contentType string) (err error) {
+	bucket := gcs.client.Bucket(bucketName)
+	intermediates := make([]string, 0)
+	defer func() {
+		for _, intermediate := range intermediates {
+			bucket.Object(intermediate).Delete(ctx)
+		}
+	}()
+
+	for round := 0; len(sources) > gcsMaxComposeSources; round++ {
+		composed := make([]string, 0, len(sources)/gcsMaxComposeSources+1)
+		for i := 0; i < len(sources); i += gcsMaxComposeSources {
+			batch := sources[i:min(i+gcsMaxComposeSources, len(sources))]
+			intermediate := fmt.Sprintf("%s.compose-%d-%d", filepath, round, i/gcsMaxComposeSources)
+			if err = gcs.compose(ctx, bucket, intermediate, batch, contentType); err != nil {
+				return
+			}
+			intermediates = append(intermediates, intermediate)
+			composed = append(composed, intermediate)
+		}
+		sources = composed
+	}
+
+	return gcs.compose(ctx, bucket, filepath, sources, contentType)
+}
+
+func (gcs *gcsAdapter) compose(ctx context.Context, bucket *gcstorage.BucketHandle, filepath string, sources []string, contentType string) (err error) {
+	handles := make([]*gcstorage.ObjectHandle, len(sources))
+	for i, source := range sources {
+		handles[i] = bucket.Object(source)
+	}
+
+	composer := bucket.Object(filepath).ComposerFrom(handles...)
+	composer.ContentType = contentType
+	_, err = composer.Run(ctx)
+	return
+}
+
+func (gcs *gcsAdapter) ListObjects(ctx context.Context, bucketName string, prefix string) (objects []ObjectAttrs, err error) {
+	it := gcs.client.Bucket(bucketName).Objects(ctx, &gcstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, ObjectAttrs{
+			Name:        attrs.Name,
+			Size:        attrs.Size,
+			ContentType: attrs.ContentType,
+			CreatedAt:   attrs.Created,
+		})
+	}
+
+	return
+}
@@ -6,7 +6,19 @@ import (
 	"time"
 )
 
+// ObjectAttrs is the metadata of a stored object.
+type ObjectAttrs struct {
+	Name        string
+	Size        int64
+	ContentType string
+	CreatedAt   time.Time
+}
+
 type Storage interface {
 	PutObject(ctx context.Context, bucketName string, filepath string, file io.Reader, contentType string, meta map[string]string) (err error)
 	SignURL(ctx context.Context, method, bucketName, filepath string, expiresIn time.Duration) (url string, err error)
+	DeleteObject(ctx context.Context, bucketName string, filepath string) (err error)
+	GetObject(ctx context.Context, bucketName string, filepath string) (file io.ReadCloser, err error)
+	ComposeObject(ctx context.Context, bucketName string, filepath string, sources []string, contentType string) (err error)
+	ListObjects(ctx context.Context, bucketName string, prefix string) (objects []ObjectAttrs, err error)
 }